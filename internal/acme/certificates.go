@@ -0,0 +1,471 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	envoytls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"golang.org/x/crypto/acme"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/envoy"
+	gwmetrics "github.com/hashicorp/consul-api-gateway/internal/metrics"
+)
+
+var _ envoy.SecretClient = (*SecretClient)(nil)
+
+// ErrCertificatePending is returned when fetching a certificate that is still being issued
+var ErrCertificatePending = errors.New("certificate is being issued")
+
+const (
+	// issuanceTimeout bounds how long we wait on the ACME server to validate
+	// our challenges and issue a certificate
+	issuanceTimeout = 5 * time.Minute
+	// issuanceRetryInterval is how long we wait before retrying a failed issuance, so that
+	// repeatedly fetching a certificate doesn't run into the ACME server's rate limits
+	issuanceRetryInterval = time.Minute
+)
+
+// Storage persists the ACME account key and issued certificates
+type Storage interface {
+	// LoadAccountKey returns the PEM encoded ACME account key, or nil if none has been stored
+	LoadAccountKey(ctx context.Context) ([]byte, error)
+	StoreAccountKey(ctx context.Context, key []byte) error
+	// LoadCertificate returns the PEM encoded certificate chain and private key stored
+	// in the given secret, or nil if the secret doesn't exist
+	LoadCertificate(ctx context.Context, namespace, name string) ([]byte, []byte, error)
+	StoreCertificate(ctx context.Context, namespace, name string, certificate, privateKey []byte) error
+}
+
+// Config configures the ACME account used to issue certificates.
+type Config struct {
+	// DirectoryURL is the ACME server's directory endpoint
+	DirectoryURL string
+	// Email is an optional contact address registered with the account
+	Email string
+	// CACert is an optional path to a CA used to verify the ACME server,
+	// i.e. for test servers such as Pebble
+	CACert string
+}
+
+// SecretClient issues and renews certificates for listener hostnames from an ACME
+// server, solving HTTP-01 challenges with an HTTP01Solver and storing the account
+// key and issued certificates with a Storage implementation. Certificates are issued
+// in the background by Manage, fetching them only ever returns stored certificates.
+type SecretClient struct {
+	logger  hclog.Logger
+	client  *acme.Client
+	email   string
+	storage Storage
+	solver  *HTTP01Solver
+
+	// issuances holds the certificates waiting to be issued, keyed by the secret
+	// they're stored in so that we don't place duplicate orders for a secret
+	issuances map[string]*issuance
+	mutex     sync.Mutex
+	// queued signals Manage that a certificate is waiting to be issued
+	queued chan struct{}
+}
+
+// issuance tracks a certificate that has been requested but not yet issued
+type issuance struct {
+	secret  Secret
+	started bool
+	// the error from the last failed attempt and when it may be retried
+	err   error
+	retry time.Time
+}
+
+// NewSecretClient creates a SecretClient for the given ACME configuration.
+func NewSecretClient(logger hclog.Logger, config Config, storage Storage, solver *HTTP01Solver) (*SecretClient, error) {
+	client := &acme.Client{
+		DirectoryURL: config.DirectoryURL,
+		UserAgent:    "consul-api-gateway",
+	}
+	if config.CACert != "" {
+		ca, err := os.ReadFile(config.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ACME CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("no certificates found in ACME CA")
+		}
+		client.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{RootCAs: pool},
+			},
+		}
+	}
+
+	return &SecretClient{
+		logger:    logger,
+		client:    client,
+		email:     config.Email,
+		storage:   storage,
+		solver:    solver,
+		issuances: make(map[string]*issuance),
+		queued:    make(chan struct{}, 1),
+	}, nil
+}
+
+// FetchSecret returns the stored certificate for the hostnames in the given ACME
+// secret. If none is stored, the stored one doesn't cover all of the hostnames, or it
+// is due for renewal, a certificate is queued for issuance. ErrCertificatePending, or
+// the error from the last failed attempt, is returned until one is stored, although a
+// stored certificate that is being renewed keeps being served until it expires. The
+// returned expiration is the time at which the certificate should be re-fetched.
+func (c *SecretClient) FetchSecret(ctx context.Context, name string) (*envoytls.Secret, time.Time, error) {
+	secret, err := ParseSecret(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	c.logger.Trace("fetching SDS secret", "name", name)
+	gwmetrics.Registry.IncrCounterWithLabels(gwmetrics.SDSCertificateFetches, 1, []metrics.Label{{
+		Name:  "fetcher",
+		Value: "acme",
+	}, {
+		Name:  "name",
+		Value: name,
+	}})
+
+	certificate, privateKey, err := c.storage.LoadCertificate(ctx, secret.Namespace, secret.Name)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error loading stored certificate: %w", err)
+	}
+	if certificate != nil {
+		leaf, err := parseLeaf(certificate)
+		if err == nil && covers(leaf, secret.Hostnames) {
+			now := time.Now()
+			if now.Before(renewalTime(leaf)) {
+				return tlsSecret(name, certificate, privateKey), renewalTime(leaf), nil
+			}
+			if now.Before(leaf.NotAfter) {
+				c.logger.Debug("stored certificate is due for renewal", "name", name)
+				if err := c.schedule(secret); err != nil {
+					c.logger.Error("error renewing certificate", "name", name, "error", err)
+				}
+				// keep serving the certificate and have it re-fetched right
+				// away so that the renewed one is picked up once it's stored
+				return tlsSecret(name, certificate, privateKey), now, nil
+			}
+		}
+		c.logger.Debug("stored certificate is invalid or expired", "name", name)
+	}
+
+	if err := c.schedule(secret); err != nil {
+		return nil, time.Time{}, fmt.Errorf("error issuing certificate: %w", err)
+	}
+	return nil, time.Time{}, ErrCertificatePending
+}
+
+// Manage issues queued certificates one at a time until the context is canceled.
+func (c *SecretClient) Manage(ctx context.Context) error {
+	for {
+		if key, secret, ok := c.next(); ok {
+			c.logger.Info("issuing certificate", "hostnames", secret.Hostnames)
+			err := c.issueSecret(ctx, secret)
+			if err != nil {
+				c.logger.Error("error issuing certificate", "hostnames", secret.Hostnames, "error", err)
+			}
+			c.finish(key, err)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.queued:
+		}
+	}
+}
+
+// schedule queues a certificate for issuance unless it's already queued, returning
+// the error from the last attempt if it failed and can't be retried yet
+func (c *SecretClient) schedule(secret Secret) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := secret.Namespace + "/" + secret.Name
+	if existing, ok := c.issuances[key]; ok {
+		// issue the certificate for the latest hostnames
+		existing.secret = secret
+		if existing.err == nil {
+			return nil
+		}
+		if time.Now().Before(existing.retry) {
+			return existing.err
+		}
+		existing.err = nil
+	} else {
+		c.issuances[key] = &issuance{secret: secret}
+	}
+
+	select {
+	case c.queued <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// next returns the next queued certificate and marks it as started
+func (c *SecretClient) next() (string, Secret, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, issuance := range c.issuances {
+		if !issuance.started && issuance.err == nil {
+			issuance.started = true
+			return key, issuance.secret, true
+		}
+	}
+	return "", Secret{}, false
+}
+
+// finish records the result of issuing a certificate, failures are
+// kept around so that fetches return the error until it's retried
+func (c *SecretClient) finish(key string, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	issuance, ok := c.issuances[key]
+	if !ok {
+		return
+	}
+	if err == nil {
+		delete(c.issuances, key)
+		return
+	}
+	issuance.started = false
+	issuance.err = err
+	issuance.retry = time.Now().Add(issuanceRetryInterval)
+}
+
+// issueSecret issues a certificate for the hostnames of a secret and stores it
+func (c *SecretClient) issueSecret(ctx context.Context, secret Secret) error {
+	certificate, privateKey, err := c.issue(ctx, secret.Hostnames)
+	if err != nil {
+		return err
+	}
+	if err := c.storage.StoreCertificate(ctx, secret.Namespace, secret.Name, certificate, privateKey); err != nil {
+		return fmt.Errorf("error storing certificate: %w", err)
+	}
+	return nil
+}
+
+// ensureAccount loads or creates the account key and makes sure that
+// it is registered with the ACME server.
+func (c *SecretClient) ensureAccount(ctx context.Context) error {
+	if c.client.Key != nil {
+		return nil
+	}
+
+	stored, err := c.storage.LoadAccountKey(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading account key: %w", err)
+	}
+
+	var key crypto.Signer
+	if stored != nil {
+		block, _ := pem.Decode(stored)
+		if block == nil {
+			return errors.New("failed to parse account key PEM")
+		}
+		if key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+			return fmt.Errorf("failed to parse account key: %w", err)
+		}
+	} else {
+		if key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+			return err
+		}
+	}
+
+	c.client.Key = key
+	account := &acme.Account{}
+	if c.email != "" {
+		account.Contact = []string{"mailto:" + c.email}
+	}
+	if _, err := c.client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		c.client.Key = nil
+		return fmt.Errorf("error registering account: %w", err)
+	}
+
+	if stored == nil {
+		encoded, err := encodeKey(key.(*ecdsa.PrivateKey))
+		if err != nil {
+			return err
+		}
+		if err := c.storage.StoreAccountKey(ctx, encoded); err != nil {
+			return fmt.Errorf("error storing account key: %w", err)
+		}
+	}
+	return nil
+}
+
+// issue places an order for the given hostnames, solves its HTTP-01 challenges
+// and returns the PEM encoded certificate chain and private key.
+func (c *SecretClient) issue(ctx context.Context, hostnames []string) ([]byte, []byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, issuanceTimeout)
+	defer cancel()
+
+	if err := c.ensureAccount(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	order, err := c.client.AuthorizeOrder(ctx, acme.DomainIDs(hostnames...))
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, url := range order.AuthzURLs {
+		if err := c.authorize(ctx, url); err != nil {
+			return nil, nil, err
+		}
+	}
+	if order, err = c.client.WaitOrder(ctx, order.URI); err != nil {
+		return nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: hostnames[0]},
+		DNSNames: hostnames,
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	chain, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var certificate bytes.Buffer
+	for _, der := range chain {
+		if err := pem.Encode(&certificate, &pem.Block{Type: "CERTIFICATE", Bytes: der}); err != nil {
+			return nil, nil, err
+		}
+	}
+	privateKey, err := encodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return certificate.Bytes(), privateKey, nil
+}
+
+// authorize solves the HTTP-01 challenge for a pending authorization.
+func (c *SecretClient) authorize(ctx context.Context, url string) error {
+	authorization, err := c.client.GetAuthorization(ctx, url)
+	if err != nil {
+		return err
+	}
+	if authorization.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, candidate := range authorization.Challenges {
+		if candidate.Type == "http-01" {
+			challenge = candidate
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("no http-01 challenge offered for %s", authorization.Identifier.Value)
+	}
+
+	response, err := c.client.HTTP01ChallengeResponse(challenge.Token)
+	if err != nil {
+		return err
+	}
+	c.solver.present(challenge.Token, response)
+	defer c.solver.cleanup(challenge.Token)
+
+	if _, err := c.client.Accept(ctx, challenge); err != nil {
+		return err
+	}
+	if _, err := c.client.WaitAuthorization(ctx, authorization.URI); err != nil {
+		return fmt.Errorf("error validating %s: %w", authorization.Identifier.Value, err)
+	}
+	return nil
+}
+
+func encodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+func parseLeaf(certificate []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return nil, errors.New("failed to parse certificate PEM")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+	return leaf, nil
+}
+
+// covers checks that a certificate is valid for all of the given hostnames
+func covers(certificate *x509.Certificate, hostnames []string) bool {
+	for _, hostname := range hostnames {
+		if err := certificate.VerifyHostname(hostname); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// renewalTime returns when a certificate should be renewed, once a third of its
+// lifetime remains, which gives plenty of time to retry any failed renewals
+func renewalTime(certificate *x509.Certificate) time.Time {
+	lifetime := certificate.NotAfter.Sub(certificate.NotBefore)
+	return certificate.NotAfter.Add(-lifetime / 3)
+}
+
+func tlsSecret(name string, certificate, privateKey []byte) *envoytls.Secret {
+	return &envoytls.Secret{
+		Type: &envoytls.Secret_TlsCertificate{
+			TlsCertificate: &envoytls.TlsCertificate{
+				CertificateChain: &core.DataSource{
+					Specifier: &core.DataSource_InlineBytes{
+						InlineBytes: certificate,
+					},
+				},
+				PrivateKey: &core.DataSource{
+					Specifier: &core.DataSource_InlineBytes{
+						InlineBytes: privateKey,
+					},
+				},
+			},
+		},
+		Name: name,
+	}
+}
@@ -0,0 +1,498 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acme
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	envoytls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme"
+
+	"github.com/hashicorp/go-hclog"
+
+	gwTesting "github.com/hashicorp/consul-api-gateway/internal/testing"
+)
+
+func TestSecretClient(t *testing.T) {
+	t.Parallel()
+
+	storage := newMemoryStorage()
+	server := newFakeACMEServer(t, storage)
+	solver := NewHTTP01Solver(hclog.NewNullLogger())
+	server.solver = solver
+
+	client, err := NewSecretClient(hclog.NewNullLogger(), Config{
+		DirectoryURL: server.URL + "/directory",
+		Email:        "admin@example.com",
+	}, storage, solver)
+	require.NoError(t, err)
+	runManager(t, client)
+
+	name := NewSecret("default", "secret", "example.com").String()
+
+	// Test issuing a new certificate in the background
+	_, _, err = client.FetchSecret(context.Background(), name)
+	require.Equal(t, ErrCertificatePending, err)
+	secret, renewal := fetchIssued(t, client, name)
+	assert.Equal(t, name, secret.Name)
+	assert.Equal(t, 1, server.orderCount())
+	assert.NotNil(t, storage.storedAccountKey())
+
+	stored := storage.certificate("default/secret")
+	require.NotNil(t, stored)
+	assert.Equal(t, stored.certificate, secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+	assert.Equal(t, stored.privateKey, secret.GetTlsCertificate().PrivateKey.GetInlineBytes())
+	leaf, err := parseLeaf(stored.certificate)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com"}, leaf.DNSNames)
+	assert.Equal(t, renewalTime(leaf), renewal)
+	assert.Equal(t, 2, bytes.Count(stored.certificate, []byte("BEGIN CERTIFICATE")))
+
+	// Test re-using the stored certificate
+	_, _, err = client.FetchSecret(context.Background(), name)
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.orderCount())
+
+	// Test re-issuing when the hostnames change
+	fetchIssued(t, client, NewSecret("default", "secret", "example.com", "www.example.com").String())
+	assert.Equal(t, 2, server.orderCount())
+	leaf, err = parseLeaf(storage.certificate("default/secret").certificate)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, leaf.DNSNames)
+
+	// Test renewing a certificate with less than a third of its lifetime remaining,
+	// the stored certificate is served and re-fetched right away while it's renewed
+	server.setValidity(-time.Hour, time.Minute)
+	renewing := NewSecret("default", "renewing", "example.com").String()
+	_, renewal = fetchIssued(t, client, renewing)
+	assert.WithinDuration(t, time.Now(), renewal, time.Minute)
+	require.Eventually(t, func() bool {
+		return server.orderCount() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Test invalid secret
+	_, _, err = client.FetchSecret(context.Background(), "acme:///default")
+	assert.Equal(t, ErrInvalidSecret, err)
+}
+
+func TestSecretClientChallengeFailure(t *testing.T) {
+	t.Parallel()
+
+	storage := newMemoryStorage()
+	server := newFakeACMEServer(t, storage)
+	// the server validates challenges against a solver that isn't serving them
+	server.solver = NewHTTP01Solver(hclog.NewNullLogger())
+
+	client, err := NewSecretClient(hclog.NewNullLogger(), Config{
+		DirectoryURL: server.URL + "/directory",
+	}, storage, NewHTTP01Solver(hclog.NewNullLogger()))
+	require.NoError(t, err)
+	runManager(t, client)
+
+	name := NewSecret("default", "secret", "example.com").String()
+	_, _, err = client.FetchSecret(context.Background(), name)
+	require.Equal(t, ErrCertificatePending, err)
+
+	// the failure is returned until the issuance can be retried
+	require.Eventually(t, func() bool {
+		_, _, err = client.FetchSecret(context.Background(), name)
+		return err != ErrCertificatePending
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error validating example.com")
+	assert.Nil(t, storage.certificate("default/secret"))
+
+	_, _, err = client.FetchSecret(context.Background(), name)
+	assert.Contains(t, err.Error(), "error validating example.com")
+	assert.Equal(t, 1, server.orderCount())
+}
+
+// TestSecretClientPebble runs against a local Pebble server started from a checkout
+// of github.com/letsencrypt/pebble with its bundled test configuration, i.e.
+//
+//	pebble -config ./test/config/pebble-config.json
+//
+// with PEBBLE_DIRECTORY_URL set to its directory (https://localhost:14000/dir) and
+// PEBBLE_CA_FILE set to the CA it serves the directory with (test/certs/pebble.minica.pem
+// in the Pebble checkout). Pebble must be able to reach the challenge solver on
+// PEBBLE_HTTP_PORT (default 5002) for the test hostname PEBBLE_HOSTNAME (default localhost).
+func TestSecretClientPebble(t *testing.T) {
+	directory := os.Getenv("PEBBLE_DIRECTORY_URL")
+	if directory == "" {
+		t.Skip("PEBBLE_DIRECTORY_URL not set")
+	}
+	port := os.Getenv("PEBBLE_HTTP_PORT")
+	if port == "" {
+		port = "5002"
+	}
+	hostname := os.Getenv("PEBBLE_HOSTNAME")
+	if hostname == "" {
+		hostname = "localhost"
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	solver := NewHTTP01Solver(hclog.NewNullLogger())
+	go func() {
+		_ = solver.Run(ctx, ":"+port)
+	}()
+
+	storage := newMemoryStorage()
+	client, err := NewSecretClient(hclog.NewNullLogger(), Config{
+		DirectoryURL: directory,
+		CACert:       os.Getenv("PEBBLE_CA_FILE"),
+	}, storage, solver)
+	require.NoError(t, err)
+	runManager(t, client)
+
+	fetchIssued(t, client, NewSecret("default", "secret", hostname).String())
+
+	leaf, err := parseLeaf(storage.certificate("default/secret").certificate)
+	require.NoError(t, err)
+	assert.NoError(t, leaf.VerifyHostname(hostname))
+}
+
+// runManager issues certificates in the background until the test finishes
+func runManager(t *testing.T, client *SecretClient) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = client.Manage(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// fetchIssued fetches a certificate until it has been issued
+func fetchIssued(t *testing.T, client *SecretClient, name string) (*envoytls.Secret, time.Time) {
+	t.Helper()
+
+	var secret *envoytls.Secret
+	var renewal time.Time
+	require.Eventually(t, func() bool {
+		var err error
+		secret, renewal, err = client.FetchSecret(context.Background(), name)
+		if err == ErrCertificatePending {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}, 30*time.Second, 10*time.Millisecond)
+	return secret, renewal
+}
+
+type storedCertificate struct {
+	certificate []byte
+	privateKey  []byte
+}
+
+type memoryStorage struct {
+	accountKey   []byte
+	certificates map[string]*storedCertificate
+	mutex        sync.Mutex
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{
+		certificates: make(map[string]*storedCertificate),
+	}
+}
+
+func (s *memoryStorage) storedAccountKey() []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.accountKey
+}
+
+func (s *memoryStorage) certificate(key string) *storedCertificate {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.certificates[key]
+}
+
+func (s *memoryStorage) LoadAccountKey(ctx context.Context) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.accountKey, nil
+}
+
+func (s *memoryStorage) StoreAccountKey(ctx context.Context, key []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.accountKey = key
+	return nil
+}
+
+func (s *memoryStorage) LoadCertificate(ctx context.Context, namespace, name string) ([]byte, []byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored, found := s.certificates[namespace+"/"+name]
+	if !found {
+		return nil, nil, nil
+	}
+	return stored.certificate, stored.privateKey, nil
+}
+
+func (s *memoryStorage) StoreCertificate(ctx context.Context, namespace, name string, certificate, privateKey []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.certificates[namespace+"/"+name] = &storedCertificate{
+		certificate: certificate,
+		privateKey:  privateKey,
+	}
+	return nil
+}
+
+// fakeACMEServer implements just enough of RFC 8555 to issue certificates signed
+// by the default test CA, validating HTTP-01 challenges by requesting them from a
+// solver directly. It doesn't verify any request signatures.
+type fakeACMEServer struct {
+	*httptest.Server
+
+	t       *testing.T
+	storage *memoryStorage
+	solver  http.Handler
+
+	orders      int
+	identifiers []string
+	valid       map[string]bool
+	notBefore   time.Duration
+	notAfter    time.Duration
+	chain       []byte
+	mutex       sync.Mutex
+}
+
+func newFakeACMEServer(t *testing.T, storage *memoryStorage) *fakeACMEServer {
+	s := &fakeACMEServer{
+		t:         t,
+		storage:   storage,
+		notBefore: -time.Minute,
+		notAfter:  90 * 24 * time.Hour,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeACMEServer) orderCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.orders
+}
+
+func (s *fakeACMEServer) setValidity(notBefore, notAfter time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.notBefore = notBefore
+	s.notAfter = notAfter
+}
+
+func (s *fakeACMEServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", time.Now().UnixNano()))
+
+	if r.Method == http.MethodGet && r.URL.Path == "/directory" {
+		s.respond(w, http.StatusOK, map[string]string{
+			"newNonce":   s.URL + "/nonce",
+			"newAccount": s.URL + "/account",
+			"newOrder":   s.URL + "/order",
+			"revokeCert": s.URL + "/revoke",
+			"keyChange":  s.URL + "/key-change",
+		})
+		return
+	}
+	if r.URL.Path == "/nonce" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	var request struct {
+		Payload string `json:"payload"`
+	}
+	require.NoError(s.t, json.NewDecoder(r.Body).Decode(&request))
+	payload, err := base64.RawURLEncoding.DecodeString(request.Payload)
+	require.NoError(s.t, err)
+
+	path := r.URL.Path
+	switch {
+	case path == "/account":
+		w.Header().Set("Location", s.URL+"/account/1")
+		s.respond(w, http.StatusCreated, map[string]string{"status": "valid"})
+	case path == "/order":
+		var order struct {
+			Identifiers []struct {
+				Value string `json:"value"`
+			} `json:"identifiers"`
+		}
+		require.NoError(s.t, json.Unmarshal(payload, &order))
+		s.orders++
+		s.identifiers = nil
+		s.chain = nil
+		s.valid = make(map[string]bool)
+		for _, identifier := range order.Identifiers {
+			s.identifiers = append(s.identifiers, identifier.Value)
+		}
+		w.Header().Set("Location", s.URL+"/order/1")
+		s.respond(w, http.StatusCreated, s.order())
+	case path == "/order/1":
+		w.Header().Set("Location", s.URL+"/order/1")
+		s.respond(w, http.StatusOK, s.order())
+	case strings.HasPrefix(path, "/authz/"):
+		s.respond(w, http.StatusOK, s.authorization(strings.TrimPrefix(path, "/authz/")))
+	case strings.HasPrefix(path, "/challenge/"):
+		hostname := strings.TrimPrefix(path, "/challenge/")
+		s.valid[hostname] = s.validate(hostname)
+		s.respond(w, http.StatusOK, s.challenge(hostname))
+	case path == "/finalize":
+		var finalize struct {
+			CSR string `json:"csr"`
+		}
+		require.NoError(s.t, json.Unmarshal(payload, &finalize))
+		der, err := base64.RawURLEncoding.DecodeString(finalize.CSR)
+		require.NoError(s.t, err)
+		s.chain = s.sign(der)
+		w.Header().Set("Location", s.URL+"/order/1")
+		s.respond(w, http.StatusOK, s.order())
+	case path == "/certificate":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		_, _ = w.Write(s.chain)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeACMEServer) respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	require.NoError(s.t, json.NewEncoder(w).Encode(body))
+}
+
+func (s *fakeACMEServer) order() map[string]interface{} {
+	status := acme.StatusReady
+	authorizations := []string{}
+	identifiers := []map[string]string{}
+	for _, identifier := range s.identifiers {
+		authorizations = append(authorizations, s.URL+"/authz/"+identifier)
+		identifiers = append(identifiers, map[string]string{"type": "dns", "value": identifier})
+		if !s.valid[identifier] {
+			status = acme.StatusPending
+		}
+	}
+	order := map[string]interface{}{
+		"status":         status,
+		"identifiers":    identifiers,
+		"authorizations": authorizations,
+		"finalize":       s.URL + "/finalize",
+	}
+	if s.chain != nil && status == acme.StatusReady {
+		order["status"] = acme.StatusValid
+		order["certificate"] = s.URL + "/certificate"
+	}
+	return order
+}
+
+func (s *fakeACMEServer) authorization(hostname string) map[string]interface{} {
+	status := acme.StatusPending
+	if valid, found := s.valid[hostname]; found {
+		status = acme.StatusInvalid
+		if valid {
+			status = acme.StatusValid
+		}
+	}
+	return map[string]interface{}{
+		"status":     status,
+		"identifier": map[string]string{"type": "dns", "value": hostname},
+		"challenges": []interface{}{s.challenge(hostname)},
+	}
+}
+
+func (s *fakeACMEServer) challenge(hostname string) map[string]interface{} {
+	status := acme.StatusPending
+	if valid, found := s.valid[hostname]; found {
+		status = acme.StatusInvalid
+		if valid {
+			status = acme.StatusValid
+		}
+	}
+	return map[string]interface{}{
+		"type":   "http-01",
+		"url":    s.URL + "/challenge/" + hostname,
+		"token":  "token-" + hostname,
+		"status": status,
+	}
+}
+
+// validate requests the challenge response for a hostname from the solver
+// and checks it against the key authorization for the stored account key
+func (s *fakeACMEServer) validate(hostname string) bool {
+	token := "token-" + hostname
+	request := httptest.NewRequest(http.MethodGet, "http://"+hostname+ChallengePathPrefix+token, nil)
+	recorder := httptest.NewRecorder()
+	s.solver.ServeHTTP(recorder, request)
+
+	accountKey, err := s.storage.LoadAccountKey(context.Background())
+	require.NoError(s.t, err)
+	block, _ := pem.Decode(accountKey)
+	require.NotNil(s.t, block)
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	require.NoError(s.t, err)
+	thumbprint, err := acme.JWKThumbprint(key.Public())
+	require.NoError(s.t, err)
+
+	return recorder.Code == http.StatusOK && recorder.Body.String() == token+"."+thumbprint
+}
+
+func (s *fakeACMEServer) sign(der []byte) []byte {
+	csr, err := x509.ParseCertificateRequest(der)
+	require.NoError(s.t, err)
+
+	ca := gwTesting.DefaultTestCA
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(int64(s.orders)),
+		Subject:      csr.Subject,
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now().Add(s.notBefore),
+		NotAfter:     time.Now().Add(s.notAfter),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, ca.Cert, csr.PublicKey, ca.PrivateKey)
+	require.NoError(s.t, err)
+
+	chain := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+	return append(chain, ca.CertBytes...)
+}
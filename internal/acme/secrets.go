@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acme
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrInvalidSecret = errors.New("invalid acme secret")
+)
+
+const (
+	SecretScheme = "acme"
+
+	queryParamHostnames = "hostnames"
+)
+
+// Secret describes a certificate issued through ACME for a set of hostnames
+// and stored in the Kubernetes secret with the given namespace and name.
+type Secret struct {
+	Namespace string
+	Name      string
+	Hostnames []string
+}
+
+func NewSecret(namespace, name string, hostnames ...string) Secret {
+	return Secret{
+		Namespace: namespace,
+		Name:      name,
+		Hostnames: hostnames,
+	}
+}
+
+func ParseSecret(ref string) (Secret, error) {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	if parsed.Scheme != SecretScheme {
+		return Secret{}, ErrInvalidSecret
+	}
+
+	if !strings.HasPrefix(parsed.Path, "/") {
+		return Secret{}, ErrInvalidSecret
+	}
+	tokens := strings.SplitN(strings.TrimPrefix(parsed.Path, "/"), "/", 2)
+	if len(tokens) != 2 || tokens[0] == "" || tokens[1] == "" {
+		return Secret{}, ErrInvalidSecret
+	}
+
+	hostnames := parsed.Query().Get(queryParamHostnames)
+	if hostnames == "" {
+		return Secret{}, ErrInvalidSecret
+	}
+
+	return NewSecret(tokens[0], tokens[1], strings.Split(hostnames, ",")...), nil
+}
+
+func (s Secret) String() string {
+	v := url.Values{}
+
+	if len(s.Hostnames) > 0 {
+		v.Add(queryParamHostnames, strings.Join(s.Hostnames, ","))
+	}
+
+	u := &url.URL{
+		Scheme:   SecretScheme,
+		RawQuery: v.Encode(),
+	}
+	if s.Namespace != "" || s.Name != "" {
+		u.Path = "/" + s.Namespace + "/" + s.Name
+	}
+	return u.String()
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acme
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecret(t *testing.T) {
+	// Test empty name
+	_, err := ParseSecret("")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+
+	// Test invalid scheme
+	_, err = ParseSecret("invalid://")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+
+	// Test missing name
+	_, err = ParseSecret("acme:///default?hostnames=example.com")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+
+	// Test missing hostnames
+	_, err = ParseSecret("acme:///default/secret")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+
+	// Test full set of serialized values
+	secret, err := ParseSecret("acme:///default/secret?hostnames=example.com%2Cwww.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "default", secret.Namespace)
+	assert.Equal(t, "secret", secret.Name)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, secret.Hostnames)
+
+	// Test round trip
+	secret2, err := ParseSecret(secret.String())
+	require.NoError(t, err)
+	assert.Equal(t, secret, secret2)
+}
+
+func TestSecret_String(t *testing.T) {
+	secret := NewSecret("", "")
+
+	// Test empty
+	assert.Equal(t, "acme:", secret.String())
+
+	// Test with secret
+	secret.Namespace = "default"
+	secret.Name = "secret"
+	assert.Equal(t, "acme:///default/secret", secret.String())
+
+	// Test with hostnames
+	secret.Hostnames = []string{"example.com", "www.example.com"}
+	assert.Equal(t, "acme:///default/secret?hostnames=example.com%2Cwww.example.com", secret.String())
+}
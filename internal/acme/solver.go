@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acme
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// ChallengePathPrefix is the path that ACME servers request HTTP-01 challenge responses on.
+const ChallengePathPrefix = "/.well-known/acme-challenge/"
+
+// HTTP01Solver serves the key authorizations for pending HTTP-01 challenges.
+// Gateways route requests for ChallengePathPrefix on their HTTP listeners to it.
+type HTTP01Solver struct {
+	logger hclog.Logger
+
+	// responses holds the key authorization for each pending challenge token
+	responses map[string]string
+	mutex     sync.RWMutex
+}
+
+func NewHTTP01Solver(logger hclog.Logger) *HTTP01Solver {
+	return &HTTP01Solver{
+		logger:    logger,
+		responses: make(map[string]string),
+	}
+}
+
+func (s *HTTP01Solver) present(token, response string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.responses[token] = response
+}
+
+func (s *HTTP01Solver) cleanup(token string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.responses, token)
+}
+
+func (s *HTTP01Solver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, ChallengePathPrefix) {
+		http.NotFound(w, r)
+		return
+	}
+	token := strings.TrimPrefix(r.URL.Path, ChallengePathPrefix)
+
+	s.mutex.RLock()
+	response, found := s.responses[token]
+	s.mutex.RUnlock()
+
+	if !found {
+		s.logger.Debug("unknown challenge token requested", "token", token, "host", r.Host)
+		http.NotFound(w, r)
+		return
+	}
+
+	s.logger.Trace("serving challenge response", "token", token, "host", r.Host)
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(response))
+}
+
+// Run serves challenge responses on the given address until the context is canceled.
+func (s *HTTP01Solver) Run(ctx context.Context, address string) error {
+	server := &http.Server{
+		Addr:    address,
+		Handler: s,
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			// graceful shutdown failed, exit
+			s.logger.Error("error shutting down ACME challenge server", "error", err)
+		}
+	}()
+	defer wg.Wait()
+
+	if err := server.ListenAndServe(); err != nil {
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	}
+	return nil
+}
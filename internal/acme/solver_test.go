@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package acme
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestHTTP01Solver(t *testing.T) {
+	t.Parallel()
+
+	solver := NewHTTP01Solver(hclog.NewNullLogger())
+	solver.present("token", "token.thumbprint")
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		solver.ServeHTTP(recorder, httptest.NewRequest(method, "http://example.com"+path, nil))
+		return recorder
+	}
+
+	// Test pending challenge
+	response := serve(http.MethodGet, ChallengePathPrefix+"token")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "token.thumbprint", response.Body.String())
+
+	// Test unknown token
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, ChallengePathPrefix+"other").Code)
+
+	// Test other paths and methods
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/token").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, ChallengePathPrefix+"token").Code)
+
+	// Test cleaned up challenge
+	solver.cleanup("token")
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, ChallengePathPrefix+"token").Code)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul-api-gateway/internal/acme"
+	"github.com/hashicorp/consul-api-gateway/internal/core"
+)
+
+// acmeSolverService is the name of the external service that routes
+// ACME HTTP-01 challenge requests to the controller's solver
+const acmeSolverService = "consul-api-gateway-acme-solver"
+
+// WithACMESolver routes the HTTP-01 challenges for any ACME issued listener
+// certificates to the challenge solver listening on the given address and port.
+func (a *SyncAdapter) WithACMESolver(address string, port int) *SyncAdapter {
+	a.acmeSolver = &core.ResolvedService{
+		Service: acmeSolverService,
+		External: &core.ExternalService{
+			Address: address,
+			Port:    port,
+			Owner:   acmeSolverService,
+		},
+	}
+	return a
+}
+
+// addACMEChallengeRoutes adds a route for the HTTP-01 challenge path to every plaintext
+// HTTP listener of a gateway that can serve the hostnames of the gateway's ACME issued
+// certificates, sending challenge requests to the solver.
+func addACMEChallengeRoutes(gateway core.ResolvedGateway, solver core.ResolvedService) core.ResolvedGateway {
+	hostnames := []string{}
+	for _, listener := range gateway.Listeners {
+		for _, certificate := range listener.TLS.Certificates {
+			if secret, err := acme.ParseSecret(certificate); err == nil {
+				hostnames = append(hostnames, secret.Hostnames...)
+			}
+		}
+	}
+	if len(hostnames) == 0 {
+		return gateway
+	}
+	sort.Strings(hostnames)
+
+	listeners := make([]core.ResolvedListener, 0, len(gateway.Listeners))
+	for _, listener := range gateway.Listeners {
+		if listener.Protocol != "http" || len(listener.TLS.Certificates) > 0 {
+			listeners = append(listeners, listener)
+			continue
+		}
+
+		matching := []string{}
+		for i, hostname := range hostnames {
+			if (i == 0 || hostnames[i-1] != hostname) && listenerAllowsHostname(listener.Hostname, hostname) {
+				matching = append(matching, hostname)
+			}
+		}
+		if len(matching) > 0 {
+			challenges := core.NewHTTPRouteBuilder().
+				WithName(gateway.ID.Service + "-acme-challenges").
+				WithNamespace(gateway.ID.ConsulNamespace).
+				WithMeta(gateway.Meta).
+				WithHostnames(matching).
+				WithRules([]core.HTTPRouteRule{{
+					Matches: []core.HTTPMatch{{
+						Path: core.HTTPPathMatch{
+							Type:  core.HTTPPathMatchPrefixType,
+							Value: acme.ChallengePathPrefix,
+						},
+					}},
+					Services: []core.HTTPService{{
+						Service: solver,
+						Weight:  1,
+					}},
+				}}).
+				Build()
+			listener.Routes = append(append([]core.ResolvedRoute{}, listener.Routes...), challenges)
+		}
+		listeners = append(listeners, listener)
+	}
+	gateway.Listeners = listeners
+
+	return gateway
+}
+
+// listenerAllowsHostname checks if a listener with the given hostname can
+// serve requests for a concrete hostname
+func listenerAllowsHostname(listenerHostname, hostname string) bool {
+	switch {
+	case listenerHostname == "" || listenerHostname == "*":
+		return true
+	case strings.HasPrefix(listenerHostname, "*."):
+		suffix := strings.TrimPrefix(listenerHostname, "*")
+		return strings.HasSuffix(hostname, suffix) && !strings.Contains(strings.TrimSuffix(hostname, suffix), ".")
+	default:
+		return listenerHostname == hostname
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul-api-gateway/internal/acme"
+	"github.com/hashicorp/consul-api-gateway/internal/core"
+)
+
+func TestAddACMEChallengeRoutes(t *testing.T) {
+	t.Parallel()
+
+	solver := core.ResolvedService{
+		Service: acmeSolverService,
+		External: &core.ExternalService{
+			Address: "10.0.0.1",
+			Port:    9091,
+		},
+	}
+	existing := core.NewHTTPRouteBuilder().WithName("existing").WithHostnames([]string{"example.com"}).Build()
+
+	gateway := core.ResolvedGateway{
+		ID: core.GatewayID{Service: "gateway"},
+		Listeners: []core.ResolvedListener{{
+			Name:     "https",
+			Hostname: "example.com",
+			Protocol: "http",
+			TLS: core.TLSParams{
+				Certificates: []string{acme.NewSecret("default", "example", "example.com").String()},
+			},
+		}, {
+			Name:     "https-other",
+			Protocol: "http",
+			TLS: core.TLSParams{
+				Certificates: []string{acme.NewSecret("default", "other", "other.example.org").String()},
+			},
+		}, {
+			Name:     "http",
+			Protocol: "http",
+			Routes:   []core.ResolvedRoute{existing},
+		}, {
+			Name:     "http-wildcard",
+			Hostname: "*.example.org",
+			Protocol: "http",
+		}, {
+			Name:     "http-unmatched",
+			Hostname: "example.net",
+			Protocol: "http",
+		}, {
+			Name:     "tcp",
+			Protocol: "tcp",
+		}},
+	}
+
+	resolved := addACMEChallengeRoutes(gateway, solver)
+	require.Len(t, resolved.Listeners, 6)
+
+	// TLS listeners are left alone
+	require.Empty(t, resolved.Listeners[0].Routes)
+	require.Empty(t, resolved.Listeners[1].Routes)
+
+	// a listener without a hostname gets challenges for all hostnames
+	plaintext := resolved.Listeners[2]
+	require.Len(t, plaintext.Routes, 2)
+	require.Equal(t, existing, plaintext.Routes[0])
+	challenges := plaintext.Routes[1].(core.HTTPRoute)
+	require.Equal(t, []string{"example.com", "other.example.org"}, challenges.Hostnames)
+	require.Len(t, challenges.Rules, 1)
+	require.Equal(t, core.HTTPPathMatch{
+		Type:  core.HTTPPathMatchPrefixType,
+		Value: acme.ChallengePathPrefix,
+	}, challenges.Rules[0].Matches[0].Path)
+	require.Equal(t, solver, challenges.Rules[0].Services[0].Service)
+
+	// the original gateway is not modified
+	require.Len(t, gateway.Listeners[2].Routes, 1)
+
+	// listeners with hostnames only get challenges for matching hostnames
+	require.Len(t, resolved.Listeners[3].Routes, 1)
+	require.Equal(t, []string{"other.example.org"}, resolved.Listeners[3].Routes[0].(core.HTTPRoute).Hostnames)
+	require.Empty(t, resolved.Listeners[4].Routes)
+	require.Empty(t, resolved.Listeners[5].Routes)
+
+	// the solver is registered as an external service
+	index := gatewayExternalServices(resolved)
+	require.Len(t, index, 1)
+
+	// gateways without ACME certificates are unchanged
+	gateway.Listeners = gateway.Listeners[2:]
+	require.Equal(t, gateway, addACMEChallengeRoutes(gateway, solver))
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"github.com/hashicorp/consul/api"
+
+	"github.com/hashicorp/consul-api-gateway/internal/consul"
+	"github.com/hashicorp/consul-api-gateway/internal/core"
+)
+
+// externalServiceKey identifies an external service by its Consul service name along
+// with what it's registered for, since Kubernetes objects in different namespaces can
+// map to the same Consul service name
+type externalServiceKey struct {
+	Name  api.CompoundServiceName
+	Owner string
+}
+
+// externalServiceIndex holds the external services that need to be registered in Consul
+type externalServiceIndex map[externalServiceKey]consul.ExternalService
+
+func (i externalServiceIndex) add(service core.ResolvedService) {
+	if service.External == nil {
+		return
+	}
+	name := api.CompoundServiceName{
+		Name:      service.Service,
+		Namespace: service.ConsulNamespace,
+	}
+	external := consul.ExternalService{
+		Name:    name,
+		Address: service.External.Address,
+		Port:    service.External.Port,
+		Owner:   service.External.Owner,
+	}
+	if tls := service.External.TLS; tls != nil {
+		external.CAFile = tls.CAFile
+		external.CertFile = tls.CertFile
+		external.KeyFile = tls.KeyFile
+		external.SNI = tls.SNI
+	}
+	i.set(external)
+}
+
+func (i externalServiceIndex) set(service consul.ExternalService) {
+	i[externalServiceKey{Name: service.Name, Owner: service.Owner}] = service
+}
+
+func (i externalServiceIndex) merge(other externalServiceIndex) {
+	for key, service := range other {
+		i[key] = service
+	}
+}
+
+// difference returns all services in the current index that
+// are not found in the other index
+func (i externalServiceIndex) difference(other externalServiceIndex) []consul.ExternalService {
+	services := []consul.ExternalService{}
+	for key, service := range i {
+		if _, found := other[key]; !found {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+func (i externalServiceIndex) toArray() []consul.ExternalService {
+	services := make([]consul.ExternalService, 0, len(i))
+	for _, service := range i {
+		services = append(services, service)
+	}
+	return services
+}
+
+// gatewayExternalServices returns all of the external services referenced
+// by the routes attached to a gateway
+func gatewayExternalServices(gateway core.ResolvedGateway) externalServiceIndex {
+	index := make(externalServiceIndex)
+	for _, listener := range gateway.Listeners {
+		for _, route := range listener.Routes {
+			switch route.GetType() {
+			case core.ResolvedHTTPRouteType:
+				for _, rule := range route.(core.HTTPRoute).Rules {
+					for _, service := range rule.Services {
+						index.add(service.Service)
+					}
+				}
+			case core.ResolvedTCPRouteType:
+				index.add(route.(core.TCPRoute).Service)
+			}
+		}
+	}
+	return index
+}
+
+func externalServiceDefault(service core.ResolvedService, meta map[string]string) *api.ServiceConfigEntry {
+	return &api.ServiceConfigEntry{
+		Kind:      api.ServiceDefaults,
+		Name:      service.Service,
+		Namespace: service.ConsulNamespace,
+		Protocol:  "http",
+		Meta:      meta,
+	}
+}
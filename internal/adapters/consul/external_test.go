@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/hashicorp/consul-api-gateway/internal/consul"
+	"github.com/hashicorp/consul-api-gateway/internal/core"
+)
+
+func TestGatewayExternalServices(t *testing.T) {
+	t.Parallel()
+
+	external := core.ResolvedService{
+		Service:         "external",
+		ConsulNamespace: "namespace",
+		External: &core.ExternalService{
+			Address: "10.0.0.1",
+			Port:    8080,
+			Owner:   "Service/namespace/external",
+		},
+	}
+	tcpExternal := core.ResolvedService{
+		Service: "tcp-external",
+		External: &core.ExternalService{
+			Address: "10.0.0.2",
+			Port:    9090,
+		},
+	}
+	mesh := core.ResolvedService{
+		Service: "mesh",
+	}
+
+	gateway := core.ResolvedGateway{
+		Listeners: []core.ResolvedListener{{
+			Routes: []core.ResolvedRoute{
+				core.NewHTTPRouteBuilder().WithRules([]core.HTTPRouteRule{{
+					Services: []core.HTTPService{{Service: external}, {Service: mesh}},
+				}}).Build(),
+			},
+		}, {
+			Routes: []core.ResolvedRoute{
+				core.NewTCPRouteBuilder().WithService(tcpExternal).Build(),
+			},
+		}},
+	}
+
+	index := gatewayExternalServices(gateway)
+	require.Len(t, index, 2)
+
+	service, found := index[externalServiceKey{Name: api.CompoundServiceName{Name: "external", Namespace: "namespace"}, Owner: "Service/namespace/external"}]
+	require.True(t, found)
+	require.Equal(t, "10.0.0.1", service.Address)
+	require.Equal(t, 8080, service.Port)
+
+	tcpService, found := index[externalServiceKey{Name: api.CompoundServiceName{Name: "tcp-external"}}]
+	require.True(t, found)
+
+	remaining := make(externalServiceIndex)
+	remaining.add(external)
+	require.Equal(t, []consul.ExternalService{tcpService}, index.difference(remaining))
+	require.Empty(t, remaining.difference(index))
+
+	// the same Consul service registered for an object in another namespace is tracked separately
+	other := external
+	other.External = &core.ExternalService{Address: "10.0.0.3", Port: 8080, Owner: "Service/other/external"}
+	remaining.add(other)
+	require.Len(t, remaining, 2)
+	require.Len(t, remaining.difference(index), 1)
+}
@@ -6,6 +6,7 @@ package consul
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -19,11 +20,17 @@ import (
 	"github.com/hashicorp/consul-api-gateway/internal/core"
 )
 
+// orphanedExternalServiceGracePeriod is how long external services that were registered
+// before the controller started are kept without being referenced, it's long enough for
+// every gateway to be synced at least once so that those still in use aren't removed
+const orphanedExternalServiceGracePeriod = 2 * time.Minute
+
 type syncState struct {
 	ingress   *api.IngressGatewayConfigEntry
 	routers   *consul.ConfigEntryIndex
 	splitters *consul.ConfigEntryIndex
 	defaults  *consul.ConfigEntryIndex
+	external  externalServiceIndex
 }
 
 type SyncAdapter struct {
@@ -32,6 +39,16 @@ type SyncAdapter struct {
 
 	sync       map[core.GatewayID]syncState
 	intentions map[core.GatewayID]*consul.IntentionsReconciler
+	// external tracks the external services registered across all gateways
+	external externalServiceIndex
+	// orphaned holds the external services found in the Consul catalog on startup,
+	// they're deregistered after the grace period unless a gateway references them
+	orphaned        externalServiceIndex
+	orphanedLoaded  bool
+	orphanedExpires time.Time
+	registry        *consul.ExternalServiceRegistry
+	// acmeSolver is the service that ACME challenge requests are routed to, if any
+	acmeSolver *core.ResolvedService
 	mutex      sync.Mutex
 }
 
@@ -43,9 +60,18 @@ func NewSyncAdapter(logger hclog.Logger, consulClient consul.Client) *SyncAdapte
 		consul:     consulClient,
 		sync:       make(map[core.GatewayID]syncState),
 		intentions: make(map[core.GatewayID]*consul.IntentionsReconciler),
+		external:   make(externalServiceIndex),
+		registry:   consul.NewExternalServiceRegistry(logger, consulClient, consul.DefaultTerminatingGateway),
 	}
 }
 
+// WithTerminatingGateway sets the name of the terminating gateway that any
+// external services referenced by routes are linked to.
+func (a *SyncAdapter) WithTerminatingGateway(name string) *SyncAdapter {
+	a.registry = consul.NewExternalServiceRegistry(a.logger, a.consul, name)
+	return a
+}
+
 func (a *SyncAdapter) setConfigEntries(ctx context.Context, entries ...api.ConfigEntry) error {
 	options := &api.WriteOptions{}
 	var result error
@@ -86,6 +112,15 @@ func routeDiscoveryChain(route core.ResolvedRoute) (*api.IngressService, *api.Se
 				defaults.Add(httpServiceDefault(split, meta))
 			}
 		}
+		for _, rule := range httpRoute.Rules {
+			for _, service := range rule.Services {
+				if service.Service.External != nil {
+					// external services aren't configured by any mesh injector,
+					// so we need to set their protocol to match the router
+					defaults.Add(externalServiceDefault(service.Service, meta))
+				}
+			}
+		}
 
 		return &api.IngressService{
 			Name:      router.Name,
@@ -230,13 +265,57 @@ func (a *SyncAdapter) entriesForGateway(id core.GatewayID) (*api.IngressGatewayC
 	return existing.ingress, existing.routers, existing.splitters, existing.defaults
 }
 
-func (a *SyncAdapter) setEntriesForGateway(gateway core.ResolvedGateway, ingress *api.IngressGatewayConfigEntry, routers *consul.ConfigEntryIndex, splitters *consul.ConfigEntryIndex, defaults *consul.ConfigEntryIndex) {
+func (a *SyncAdapter) setEntriesForGateway(gateway core.ResolvedGateway, ingress *api.IngressGatewayConfigEntry, routers *consul.ConfigEntryIndex, splitters *consul.ConfigEntryIndex, defaults *consul.ConfigEntryIndex, external externalServiceIndex) {
 	a.sync[gateway.ID] = syncState{
 		ingress:   ingress,
 		routers:   routers,
 		splitters: splitters,
 		defaults:  defaults,
+		external:  external,
+	}
+}
+
+// loadOrphanedExternalServices rebuilds the external services registered by a previous
+// run of the controller from the Consul catalog, so that those no longer referenced by
+// any gateway are eventually deregistered.
+func (a *SyncAdapter) loadOrphanedExternalServices(ctx context.Context) error {
+	if a.orphanedLoaded {
+		return nil
+	}
+	registered, err := a.registry.Registered(ctx)
+	if err != nil {
+		return err
+	}
+	a.orphaned = make(externalServiceIndex)
+	for _, service := range registered {
+		a.orphaned.set(service)
+	}
+	a.orphanedLoaded = true
+	a.orphanedExpires = time.Now().Add(orphanedExternalServiceGracePeriod)
+	return nil
+}
+
+// deregisterUnusedExternalServices removes any external service registrations that
+// are no longer referenced by the routes of any gateway.
+func (a *SyncAdapter) deregisterUnusedExternalServices(ctx context.Context) error {
+	referenced := make(externalServiceIndex)
+	for _, state := range a.sync {
+		referenced.merge(state.external)
+	}
+
+	registered := make(externalServiceIndex)
+	registered.merge(a.external)
+	if len(a.orphaned) > 0 && time.Now().After(a.orphanedExpires) {
+		registered.merge(a.orphaned)
+		a.orphaned = nil
+	}
+
+	removed := registered.difference(referenced)
+	if err := a.registry.Deregister(ctx, removed...); err != nil {
+		return err
 	}
+	a.external = referenced
+	return nil
 }
 
 func (a *SyncAdapter) syncIntentionsForGateway(gateway core.GatewayID, ingress *api.IngressGatewayConfigEntry) error {
@@ -303,6 +382,13 @@ func (a *SyncAdapter) Clear(ctx context.Context, id core.GatewayID) error {
 
 	a.stopIntentionSyncForGateway(id)
 	delete(a.sync, id)
+
+	if err := a.loadOrphanedExternalServices(ctx); err != nil {
+		return fmt.Errorf("error loading external services: %w", err)
+	}
+	if err := a.deregisterUnusedExternalServices(ctx); err != nil {
+		return fmt.Errorf("error removing external services: %w", err)
+	}
 	return nil
 }
 
@@ -320,14 +406,21 @@ func (a *SyncAdapter) Sync(ctx context.Context, gateway core.ResolvedGateway) (b
 		defer a.logger.Trace("reconciliation finished", "time", time.Now(), "spent", time.Since(started))
 	}
 
+	if a.acmeSolver != nil {
+		gateway = addACMEChallengeRoutes(gateway, *a.acmeSolver)
+	}
+
 	ingress, computedRouters, computedSplitters, computedDefaults := discoveryChain(gateway)
 	_, existingRouters, existingSplitters, existingDefaults := a.entriesForGateway(gateway.ID)
+	computedExternal := gatewayExternalServices(gateway)
 
 	// Since we can't make multiple config entry changes in a single transaction we must
 	// perform the operations in a set that is least likely to induce downtime.
-	// First the new service-defaults, routers and splitters should be set
-	// Second the ingress gateway
-	// Third the removal of any service-defaults, routers or splitters that no longer exist
+	// First any external services should be registered
+	// Second the new service-defaults, routers and splitters should be set
+	// Third the ingress gateway
+	// Fourth the removal of any service-defaults, routers or splitters that no longer exist
+	// Finally the removal of any external services that are no longer referenced
 
 	addedRouters := computedRouters.ToArray()
 	addedDefaults := computedDefaults.ToArray()
@@ -359,6 +452,19 @@ func (a *SyncAdapter) Sync(ctx context.Context, gateway core.ResolvedGateway) (b
 		}
 	}
 
+	if err := a.loadOrphanedExternalServices(ctx); err != nil {
+		return false, fmt.Errorf("error loading external services: %w", err)
+	}
+	var conflict *consul.ExternalServiceConflictError
+	if err := a.registry.Register(ctx, computedExternal.toArray()...); errors.As(err, &conflict) {
+		// routes to the conflicting services are left pointing at whatever already has their
+		// names, the rest of the gateway is still synced
+		a.logger.Warn("not registering external services whose names are already taken", "gateway", gateway.ID, "error", err)
+	} else if err != nil {
+		return false, fmt.Errorf("error registering external services: %w", err)
+	}
+	a.external.merge(computedExternal)
+
 	// defaults need to go first, otherwise the routers are always configured to use tcp
 	if err := a.setConfigEntries(ctx, addedDefaults...); err != nil {
 		return false, fmt.Errorf("error adding service defaults config entries: %w", err)
@@ -384,7 +490,10 @@ func (a *SyncAdapter) Sync(ctx context.Context, gateway core.ResolvedGateway) (b
 		return false, fmt.Errorf("error removing service defaults config entries: %w", err)
 	}
 
-	a.setEntriesForGateway(gateway, ingress, computedRouters, computedSplitters, computedDefaults)
+	a.setEntriesForGateway(gateway, ingress, computedRouters, computedSplitters, computedDefaults, computedExternal)
+	if err := a.deregisterUnusedExternalServices(ctx); err != nil {
+		return false, fmt.Errorf("error removing external services: %w", err)
+	}
 	if err := a.syncIntentionsForGateway(gateway.ID, ingress); err != nil {
 		return false, fmt.Errorf("error syncing service intention config entries: %w", err)
 	}
@@ -218,3 +218,82 @@ func TestConsulSyncAdapter_Sync(t *testing.T) {
 			reflect.DeepEqual(ingress.Listeners[0].TLS.CipherSuites, common.DefaultTLSCipherSuites())
 	}, 30*time.Second, 1*time.Second, "listener TLS config not synced in the allotted time")
 }
+
+func TestConsulSyncAdapter_ExternalServiceCollision(t *testing.T) {
+	if generate {
+		t.Skip("Skipping for generate due to consul binary dependency")
+	}
+
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consulSrv, err := testutil.NewTestServerConfigT(t, func(c *testutil.TestServerConfig) {
+		c.Connect = map[string]interface{}{"enabled": true}
+		c.Peering = nil
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		cancel()
+		_ = consulSrv.Stop()
+	})
+
+	cfg := api.DefaultConfig()
+	cfg.Address = consulSrv.HTTPAddr
+	c, err := api.NewClient(cfg)
+	require.NoError(t, err)
+	consul := consultesting.NewTestClient(c)
+
+	adapter := NewSyncAdapter(testutil.Logger(t), consul)
+
+	// an ExternalService and an ExternalName Service from different Kubernetes
+	// namespaces that map to the same Consul service
+	gatewayFor := func(name, owner, address string) core.ResolvedGateway {
+		return core.ResolvedGateway{
+			ID: core.GatewayID{Service: name},
+			Listeners: []core.ResolvedListener{{
+				Routes: []core.ResolvedRoute{
+					core.NewTCPRouteBuilder().
+						WithName(name + "/route").
+						WithService(core.ResolvedService{
+							Service: "search",
+							External: &core.ExternalService{
+								Address: address,
+								Port:    443,
+								Owner:   owner,
+							},
+						}).
+						Build(),
+				},
+			}},
+		}
+	}
+	externalService := gatewayFor("team-a", "ExternalService/team-a/search", "search.saas.example.com")
+	externalName := gatewayFor("team-b", "Service/team-b/search", "search.example.com")
+
+	registeredAddress := func() string {
+		services, _, err := c.Catalog().Service("search", "", nil)
+		require.NoError(t, err)
+		if len(services) == 0 {
+			return ""
+		}
+		require.Len(t, services, 1)
+		return services[0].ServiceAddress
+	}
+
+	_, err = adapter.Sync(ctx, externalService)
+	require.NoError(t, err)
+	require.Equal(t, "search.saas.example.com", registeredAddress())
+
+	// the second gateway still syncs, but doesn't take over the registration
+	_, err = adapter.Sync(ctx, externalName)
+	require.NoError(t, err)
+	require.Equal(t, "search.saas.example.com", registeredAddress())
+
+	// nor does it remove the registration once it's cleared
+	require.NoError(t, adapter.Clear(ctx, externalName.ID))
+	require.Equal(t, "search.saas.example.com", registeredAddress())
+
+	require.NoError(t, adapter.Clear(ctx, externalService.ID))
+	require.Equal(t, "", registeredAddress())
+}
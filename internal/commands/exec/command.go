@@ -36,6 +36,9 @@ const (
 
 	// The amount of time to wait for the first cert write
 	defaultCertWaitTime = 1 * time.Minute
+
+	// How often to check for a new token once Envoy's is about to expire
+	tokenRotationRetryInterval = 10 * time.Second
 )
 
 type Command struct {
@@ -199,6 +202,9 @@ func (c *Command) Run(args []string) (ret int) {
 				Meta:        map[string]string{},
 			},
 		}
+		// the token is read again on every login so that
+		// rotated projected tokens are picked up
+		consulClientConfig.BearerTokenFile = c.flagBearerTokenFile
 	}
 
 	return RunExec(ExecConfig{
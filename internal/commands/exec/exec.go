@@ -6,6 +6,7 @@ package exec
 import (
 	"context"
 	"io"
+	"math/rand"
 	"os"
 	"os/signal"
 	"syscall"
@@ -126,6 +127,9 @@ func RunExec(config ExecConfig) (ret int) {
 		sessionCancel()
 	}()
 
+	// Envoy can't pick up a new token without being restarted, so keep
+	// the token it's started with around for as long as it's valid
+	token, tokenExpiration := client.RetainToken()
 	envoyManager := envoy.NewManager(
 		config.Logger.Named("envoy-manager"),
 		envoy.ManagerConfig{
@@ -137,7 +141,7 @@ func RunExec(config ExecConfig) (ret int) {
 			ConsulXDSPort:     config.EnvoyConfig.XDSPort,
 			BootstrapFilePath: config.EnvoyConfig.BootstrapFile,
 			LogLevel:          config.LogLevel,
-			Token:             client.Token(),
+			Token:             token,
 			EnvoyBinary:       config.EnvoyConfig.Binary,
 			ExtraArgs:         config.EnvoyConfig.ExtraArgs,
 			Output:            config.EnvoyConfig.Output,
@@ -188,7 +192,7 @@ func RunExec(config ExecConfig) (ret int) {
 	config.Logger.Trace("initial certificates written")
 
 	group.Go(func() error {
-		return envoyManager.Run(ctx)
+		return runEnvoy(ctx, config.Logger, client, envoyManager, sdsConfig, tokenExpiration)
 	})
 
 	config.Logger.Info("started consul-api-gateway api gateway")
@@ -200,3 +204,76 @@ func RunExec(config ExecConfig) (ret int) {
 	config.Logger.Info("shutting down")
 	return 0
 }
+
+// runEnvoy runs Envoy until the context is canceled, restarting it with a
+// freshly rendered bootstrap configuration before the Consul ACL token it
+// was started with expires. Envoy isn't hot restarted, so the gateway instance
+// stops serving traffic until the new Envoy has been configured
+func runEnvoy(ctx context.Context, logger hclog.Logger, client consul.Client, manager *envoy.Manager, sdsConfig string, expiration time.Time) error {
+	for {
+		envoyCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- manager.Run(envoyCtx)
+		}()
+
+		rotate, err := waitForTokenRotation(client, manager.Token, expiration, done)
+		if !rotate {
+			cancel()
+			return err
+		}
+
+		logger.Info("restarting envoy with a new consul acl token")
+		cancel()
+		if err := <-done; err != nil {
+			return err
+		}
+
+		var token string
+		token, expiration = client.RetainToken()
+		manager.Token = token
+		if err := manager.RenderBootstrap(sdsConfig); err != nil {
+			return err
+		}
+	}
+}
+
+// waitForTokenRotation blocks until Envoy's token is about to expire and the client has
+// logged in again, returning false along with Envoy's error if Envoy exits first,
+// which it does once the context it was run with is canceled
+func waitForTokenRotation(client consul.Client, token string, expiration time.Time, done <-chan error) (bool, error) {
+	var restart <-chan time.Time
+	if !expiration.IsZero() {
+		timer := time.NewTimer(rotationDelay(time.Until(expiration)))
+		defer timer.Stop()
+		restart = timer.C
+	}
+
+	for {
+		select {
+		case err := <-done:
+			return false, err
+		case <-restart:
+			if client.Token() != token {
+				return true, nil
+			}
+			// the client hasn't managed to log in again yet
+			restart = time.After(tokenRotationRetryInterval)
+		}
+	}
+}
+
+// rotationDelay picks a random point between 2/3 and 5/6 of the way to a token's
+// expiration so that gateway replicas don't all restart envoy at the same time
+func rotationDelay(remaining time.Duration) time.Duration {
+	if remaining <= 0 {
+		return 0
+	}
+	minimum := remaining * 2 / 3
+	window := remaining*5/6 - minimum
+	if window <= 0 {
+		return minimum
+	}
+	jitter := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return minimum + time.Duration(jitter.Int63n(int64(window)))
+}
@@ -337,6 +337,48 @@ func TestRunExecShutdown(t *testing.T) {
 	require.Contains(t, buffer.String(), "shutting down")
 }
 
+func TestWaitForTokenRotation(t *testing.T) {
+	t.Parallel()
+
+	client := consul.NewTestClient(nil)
+	expired := time.Now().Add(-time.Second)
+
+	// envoy exiting stops the wait
+	done := make(chan error, 1)
+	done <- errors.New("exited")
+	rotate, err := waitForTokenRotation(client, "token", time.Time{}, done)
+	require.False(t, rotate)
+	require.EqualError(t, err, "exited")
+
+	// the token is rotated once it's about to expire and the client has a new one
+	rotate, err = waitForTokenRotation(client, "token", expired, make(chan error))
+	require.True(t, rotate)
+	require.NoError(t, err)
+
+	// envoy keeps running while the client doesn't have a new token yet
+	done = make(chan error, 1)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		done <- nil
+	}()
+	rotate, err = waitForTokenRotation(client, "", expired, done)
+	require.False(t, rotate)
+	require.NoError(t, err)
+}
+
+func TestRotationDelay(t *testing.T) {
+	t.Parallel()
+
+	require.Zero(t, rotationDelay(-time.Second))
+	require.Zero(t, rotationDelay(0))
+
+	for i := 0; i < 100; i++ {
+		delay := rotationDelay(time.Hour)
+		require.GreaterOrEqual(t, delay, 40*time.Minute)
+		require.Less(t, delay, 50*time.Minute)
+	}
+}
+
 type mockConsulOptions struct {
 	loginFail      bool
 	logoutFail     bool
@@ -15,21 +15,29 @@ import (
 	"time"
 
 	"github.com/mitchellh/cli"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	"github.com/hashicorp/consul-server-connection-manager/discovery"
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/hashicorp/consul-api-gateway/internal/acme"
 	"github.com/hashicorp/consul-api-gateway/internal/consul"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	"github.com/hashicorp/consul-api-gateway/internal/vault"
 )
 
 const (
 	defaultGRPCPort      = 8502
 	defaultSDSServerHost = "consul-api-gateway-controller.default.svc.cluster.local"
 	defaultSDSServerPort = 9090
+	defaultVaultPKIMount = "pki"
+	defaultVaultKVMount  = "secret"
+
+	defaultACMESolverPort    = 9091
+	defaultACMEAccountSecret = "consul-api-gateway-acme-account"
 	// The amount of time to wait for the first cert write
 	defaultCertWaitTime = 1 * time.Minute
 )
@@ -47,6 +55,8 @@ type Command struct {
 
 	flagConsulAddress string // Consul server address
 
+	flagTerminatingGateway string // Terminating gateway used to reach external services
+
 	flagPrimaryDatacenter string // Primary datacenter, may or may not be the datacenter this controller is running in
 
 	flagSDSServerHost string // SDS server host
@@ -56,11 +66,41 @@ type Command struct {
 	flagK8sContext    string // context to use
 	flagK8sNamespace  string // namespace we're run in
 
+	flagSDSAllowedTrustDomains string // additional SPIFFE trust domains allowed to connect to the SDS server
+
 	// Consul namespaces
 	flagConsulDestinationNamespace string
 	flagMirrorK8SNamespaces        bool
 	flagMirrorK8SNamespacePrefix   string
 
+	// Vault
+	flagVaultAddress             string
+	flagVaultCACert              string
+	flagVaultClientCert          string
+	flagVaultClientKey           string
+	flagVaultTLSServerName       string
+	flagVaultTLSSkipVerify       bool
+	flagVaultNamespace           string
+	flagVaultAuthMethod          string
+	flagVaultAuthMount           string
+	flagVaultKubernetesRole      string
+	flagVaultKubernetesTokenPath string
+	flagVaultAppRoleID           string
+	flagVaultAppRoleSecretIDFile string
+	flagVaultPKIMount            string
+	flagVaultPKIRole             string
+	flagVaultPKIAllowedRoles     string
+	flagVaultKVMount             string
+	flagVaultKVAllowedPaths      string
+
+	// ACME
+	flagACMEDirectoryURL           string
+	flagACMEEmail                  string
+	flagACMECAFile                 string
+	flagACMESolverPort             int
+	flagACMEAccountSecret          string
+	flagACMEAccountSecretNamespace string
+
 	// Logging
 	flagLogLevel string
 	flagLogJSON  bool
@@ -80,11 +120,14 @@ func (c *Command) init() {
 	c.flagSet.StringVar(&c.flagCASecret, "ca-secret", "", "CA Secret for Consul server.")
 	c.flagSet.StringVar(&c.flagCASecretNamespace, "ca-secret-namespace", "default", "CA Secret namespace for Consul server.")
 	c.flagSet.StringVar(&c.flagConsulAddress, "consul-address", "", "Consul Address.")
+	c.flagSet.StringVar(&c.flagTerminatingGateway, "terminating-gateway", consul.DefaultTerminatingGateway, "Name of the Consul terminating gateway that external services are linked to.")
 	c.flagSet.StringVar(&c.flagPrimaryDatacenter, "primary-datacenter", "", "Name of the primary Consul datacenter")
 	c.flagSet.StringVar(&c.flagSDSServerHost, "sds-server-host", defaultSDSServerHost, "SDS Server Host.")
 	c.flagSet.StringVar(&c.flagK8sContext, "k8s-context", "", "Kubernetes context to use.")
 	c.flagSet.StringVar(&c.flagK8sNamespace, "k8s-namespace", "", "Kubernetes namespace to use.")
 	c.flagSet.IntVar(&c.flagSDSServerPort, "sds-server-port", defaultSDSServerPort, "SDS Server Port.")
+	c.flagSet.StringVar(&c.flagSDSAllowedTrustDomains, "sds-allowed-trust-domains", "",
+		"Comma separated list of SPIFFE trust domains, in addition to the Consul CA's, that gateways may connect to the SDS server from, i.e. when the Consul CA is backed by Vault.")
 	c.flagSet.IntVar(&c.flagMetricsPort, "metrics-port", 0, "Metrics port, if not set, metrics are not enabled.")
 	c.flagSet.IntVar(&c.flagPprofPort, "pprof-port", 0, "Go pprof port, if not set, profiling is not enabled.")
 
@@ -95,6 +138,41 @@ func (c *Command) init() {
 		c.flagSet.StringVar(&c.flagMirrorK8SNamespacePrefix, "mirroring-k8s-prefix", "", "Namespace prefix for Consul services when mirroring Kubernetes namespaces.")
 	}
 
+	{
+		// Vault
+		c.flagSet.StringVar(&c.flagVaultAddress, "vault-address", "", "Vault address, defaults to VAULT_ADDR.")
+		c.flagSet.StringVar(&c.flagVaultCACert, "vault-ca-file", "", "Path to CA for the Vault server, defaults to VAULT_CACERT.")
+		c.flagSet.StringVar(&c.flagVaultClientCert, "vault-client-cert", "", "Path to client certificate for Vault TLS authentication.")
+		c.flagSet.StringVar(&c.flagVaultClientKey, "vault-client-key", "", "Path to client key for Vault TLS authentication.")
+		c.flagSet.StringVar(&c.flagVaultTLSServerName, "vault-tls-server-name", "", "SNI host to use when connecting to Vault.")
+		c.flagSet.BoolVar(&c.flagVaultTLSSkipVerify, "vault-tls-skip-verify", false, "Disable verification of the Vault server certificate.")
+		c.flagSet.StringVar(&c.flagVaultNamespace, "vault-namespace", "", "Vault namespace to use.")
+		c.flagSet.StringVar(&c.flagVaultAuthMethod, "vault-auth-method", vault.AuthMethodToken,
+			"Vault auth method. Supported values are \"token\", \"kubernetes\", and \"approle\".")
+		c.flagSet.StringVar(&c.flagVaultAuthMount, "vault-auth-mount", "", "Path the Vault auth method is mounted at, defaults to the auth method name.")
+		c.flagSet.StringVar(&c.flagVaultKubernetesRole, "vault-kubernetes-role", "", "Vault role to log in with when using kubernetes auth.")
+		c.flagSet.StringVar(&c.flagVaultKubernetesTokenPath, "vault-kubernetes-token-path", vault.DefaultKubernetesTokenPath, "Path to the service account token used for kubernetes auth.")
+		c.flagSet.StringVar(&c.flagVaultAppRoleID, "vault-approle-role-id", "", "Role id to log in with when using approle auth.")
+		c.flagSet.StringVar(&c.flagVaultAppRoleSecretIDFile, "vault-approle-secret-id-file", "", "Path to the secret id used for approle auth.")
+		c.flagSet.StringVar(&c.flagVaultPKIMount, "vault-pki-mount", defaultVaultPKIMount, "Default path the Vault PKI engine is mounted at.")
+		c.flagSet.StringVar(&c.flagVaultPKIRole, "vault-pki-role", "", "Default Vault PKI role used to issue certificates.")
+		c.flagSet.StringVar(&c.flagVaultPKIAllowedRoles, "vault-pki-allowed-roles", "",
+			"Comma separated list of <namespace>=<mount>[/<role>] entries allowing VaultCertificates in a namespace, or every namespace for \"*\", to issue certificates with the role, or any role of the mount.")
+		c.flagSet.StringVar(&c.flagVaultKVMount, "vault-kv-mount", defaultVaultKVMount, "Default path the Vault KV v2 engine is mounted at.")
+		c.flagSet.StringVar(&c.flagVaultKVAllowedPaths, "vault-kv-allowed-paths", "",
+			"Comma separated list of <namespace>=<mount>/<path> entries allowing VaultCertificates in a namespace, or every namespace for \"*\", to read certificates from KV paths beneath the path.")
+	}
+
+	{
+		// ACME
+		c.flagSet.StringVar(&c.flagACMEDirectoryURL, "acme-directory-url", "", "ACME server directory URL, if not set, ACME certificates are not enabled.")
+		c.flagSet.StringVar(&c.flagACMEEmail, "acme-email", "", "Contact email to register the ACME account with.")
+		c.flagSet.StringVar(&c.flagACMECAFile, "acme-ca-file", "", "Path to CA for the ACME server.")
+		c.flagSet.IntVar(&c.flagACMESolverPort, "acme-solver-port", defaultACMESolverPort, "Port that ACME HTTP-01 challenge requests are served on.")
+		c.flagSet.StringVar(&c.flagACMEAccountSecret, "acme-account-secret", defaultACMEAccountSecret, "Secret the ACME account key is stored in.")
+		c.flagSet.StringVar(&c.flagACMEAccountSecretNamespace, "acme-account-secret-namespace", "default", "Namespace of the secret the ACME account key is stored in.")
+	}
+
 	{
 		// Logging
 		c.flagSet.StringVar(&c.flagLogLevel, "log-level", "info",
@@ -132,6 +210,24 @@ func (c *Command) Run(args []string) int {
 	cfg.Namespace = c.flagK8sNamespace
 	cfg.PrimaryDatacenter = c.flagPrimaryDatacenter
 
+	vaultKVPaths, err := vault.ParseAllowlist(splitList(c.flagVaultKVAllowedPaths))
+	if err != nil {
+		logger.Error("error parsing vault kv allowed paths", "error", err)
+		return 1
+	}
+	vaultPKIRoles, err := vault.ParseAllowlist(splitList(c.flagVaultPKIAllowedRoles))
+	if err != nil {
+		logger.Error("error parsing vault pki allowed roles", "error", err)
+		return 1
+	}
+	cfg.VaultCertificatePolicy = vault.CertificatePolicy{
+		KVMount:  c.flagVaultKVMount,
+		KVPaths:  vaultKVPaths,
+		PKIMount: c.flagVaultPKIMount,
+		PKIRole:  c.flagVaultPKIRole,
+		PKIRoles: vaultPKIRoles,
+	}
+
 	consulCfg := api.DefaultConfig()
 	if c.flagCAFile != "" {
 		consulCfg.TLSConfig.CAFile = c.flagCAFile
@@ -236,8 +332,38 @@ func (c *Command) Run(args []string) int {
 		ProfilingPort:      c.flagPprofPort,
 		MetricsPort:        c.flagMetricsPort,
 		PrimaryDatacenter:  c.flagPrimaryDatacenter,
-		isTest:             c.isTest,
-		ConsulClientConfig: consulClientConfig,
+		TerminatingGateway: c.flagTerminatingGateway,
+		VaultConfig: vault.Config{
+			Address:             c.flagVaultAddress,
+			CACert:              c.flagVaultCACert,
+			ClientCert:          c.flagVaultClientCert,
+			ClientKey:           c.flagVaultClientKey,
+			TLSServerName:       c.flagVaultTLSServerName,
+			TLSSkipVerify:       c.flagVaultTLSSkipVerify,
+			Namespace:           c.flagVaultNamespace,
+			AuthMethod:          c.flagVaultAuthMethod,
+			AuthMount:           c.flagVaultAuthMount,
+			KubernetesRole:      c.flagVaultKubernetesRole,
+			KubernetesTokenPath: c.flagVaultKubernetesTokenPath,
+			AppRoleID:           c.flagVaultAppRoleID,
+			AppRoleSecretIDPath: c.flagVaultAppRoleSecretIDFile,
+		},
+		VaultPKIMount: c.flagVaultPKIMount,
+		VaultPKIRole:  c.flagVaultPKIRole,
+		VaultKVMount:  c.flagVaultKVMount,
+		ACMEConfig: acme.Config{
+			DirectoryURL: c.flagACMEDirectoryURL,
+			Email:        c.flagACMEEmail,
+			CACert:       c.flagACMECAFile,
+		},
+		ACMESolverPort: c.flagACMESolverPort,
+		ACMEAccountSecret: types.NamespacedName{
+			Namespace: c.flagACMEAccountSecretNamespace,
+			Name:      c.flagACMEAccountSecret,
+		},
+		SDSAllowedTrustDomains: splitList(c.flagSDSAllowedTrustDomains),
+		isTest:                 c.isTest,
+		ConsulClientConfig:     consulClientConfig,
 	})
 }
 
@@ -259,3 +385,14 @@ func (c *Command) Help() string {
 Usage: consul-api-gateway server [options]
 `
 }
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(value string) []string {
+	values := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+	return values
+}
@@ -14,13 +14,16 @@ import (
 	"time"
 
 	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/hashicorp/consul-api-gateway/internal/acme"
 	consulAdapters "github.com/hashicorp/consul-api-gateway/internal/adapters/consul"
 	"github.com/hashicorp/consul-api-gateway/internal/consul"
 	"github.com/hashicorp/consul-api-gateway/internal/envoy"
+	"github.com/hashicorp/consul-api-gateway/internal/file"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
 	"github.com/hashicorp/consul-api-gateway/internal/metrics"
@@ -42,6 +45,18 @@ type ServerConfig struct {
 	ProfilingPort      int
 	MetricsPort        int
 	PrimaryDatacenter  string
+	TerminatingGateway string
+	VaultConfig        vault.Config
+	VaultPKIMount      string
+	VaultPKIRole       string
+	VaultKVMount       string
+	ACMEConfig         acme.Config
+	ACMESolverPort     int
+	ACMEAccountSecret  types.NamespacedName
+
+	// SDSAllowedTrustDomains are SPIFFE trust domains allowed to
+	// connect to the SDS server in addition to the Consul CA's
+	SDSAllowedTrustDomains []string
 
 	// for testing only
 	isTest bool
@@ -54,10 +69,23 @@ func RunServer(config ServerConfig) int {
 
 	group, groupCtx := errgroup.WithContext(ctx)
 
-	secretClient, err := registerSecretClients(config)
+	var acmeSolver *acme.HTTP01Solver
+	if config.ACMEConfig.DirectoryURL != "" {
+		acmeSolver = acme.NewHTTP01Solver(config.Logger.Named("acme-solver"))
+	}
+
+	secretClient, vaultClient, acmeClient, err := registerSecretClients(config, acmeSolver)
 	if err != nil {
 		return 1
 	}
+	group.Go(func() error {
+		return vaultClient.Manage(groupCtx)
+	})
+	if acmeClient != nil {
+		group.Go(func() error {
+			return acmeClient.Manage(groupCtx)
+		})
+	}
 
 	controller, err := k8s.New(config.Logger, config.K8sConfig)
 	if err != nil {
@@ -74,7 +102,19 @@ func RunServer(config ServerConfig) int {
 		return 1
 	}
 
-	adapter := consulAdapters.NewSyncAdapter(config.Logger.Named("consul-adapter"), client)
+	consulKVClient := consul.NewKVSecretClient(config.Logger.Named("consul-kv-cert-fetcher"), client)
+	secretClient.Register(consul.KVSecretScheme, consulKVClient)
+
+	adapter := consulAdapters.NewSyncAdapter(config.Logger.Named("consul-adapter"), client).
+		WithTerminatingGateway(config.TerminatingGateway)
+	if acmeSolver != nil {
+		// challenge requests reach the solver through the terminating gateway
+		// at the same address that gateways use for SDS
+		adapter = adapter.WithACMESolver(config.K8sConfig.SDSServerHost, config.ACMESolverPort)
+		group.Go(func() error {
+			return acmeSolver.Run(groupCtx, fmt.Sprintf(":%d", config.ACMESolverPort))
+		})
+	}
 	store := store.New(k8s.StoreConfig(adapter, controller.Client(), client, config.Logger, *config.K8sConfig))
 
 	group.Go(func() error {
@@ -109,7 +149,8 @@ func RunServer(config ServerConfig) int {
 	config.Logger.Trace("initial certificates written")
 
 	// Run SDS server
-	server := envoy.NewSDSServer(config.Logger.Named("sds-server"), certManager, secretClient, store)
+	server := envoy.NewSDSServer(config.Logger.Named("sds-server"), certManager, secretClient, store).
+		WithAllowedTrustDomains(config.SDSAllowedTrustDomains...)
 	group.Go(func() error {
 		return server.Run(groupCtx)
 	})
@@ -143,31 +184,47 @@ func RunServer(config ServerConfig) int {
 	return 0
 }
 
-func registerSecretClients(config ServerConfig) (*envoy.MultiSecretClient, error) {
+func registerSecretClients(config ServerConfig, acmeSolver *acme.HTTP01Solver) (*envoy.MultiSecretClient, *vault.Client, *acme.SecretClient, error) {
 	secretClient := envoy.NewMultiSecretClient()
 
 	k8sSecretClient, err := k8s.NewK8sSecretClient(config.Logger.Named("k8s-cert-fetcher"), config.K8sConfig.RestConfig)
 	if err != nil {
 		config.Logger.Error("error initializing the kubernetes secret fetcher", "error", err)
-		return nil, err
+		return nil, nil, nil, err
 	}
 	secretClient.Register(utils.K8sSecretScheme, k8sSecretClient)
 
-	vaultPKIClient, err := vault.NewPKISecretClient(config.Logger.Named("vault-pki-cert-fetcher"), "pki", "TODO")
+	vaultClient, err := vault.NewClient(config.Logger.Named("vault"), config.VaultConfig)
 	if err != nil {
-		config.Logger.Error("error initializing the Vault PKI cert fetcher", "error", err)
-		return nil, err
+		config.Logger.Error("error initializing the Vault client", "error", err)
+		return nil, nil, nil, err
 	}
+
+	vaultPKIClient := vault.NewPKISecretClient(config.Logger.Named("vault-pki-cert-fetcher"), vaultClient, config.VaultPKIMount, config.VaultPKIRole)
 	secretClient.Register(vault.PKISecretScheme, vaultPKIClient)
 
-	vaultStaticClient, err := vault.NewKVSecretClient(config.Logger.Named("vault-kv-cert-fetcher"), "secret")
-	if err != nil {
-		config.Logger.Error("error initializing the Vault KV cert fetcher", "error", err)
-		return nil, err
-	}
+	vaultStaticClient := vault.NewKVSecretClient(config.Logger.Named("vault-kv-cert-fetcher"), vaultClient, config.VaultKVMount)
 	secretClient.Register(vault.KVSecretScheme, vaultStaticClient)
 
-	return secretClient, nil
+	fileClient := file.NewSecretClient(config.Logger.Named("file-cert-fetcher"))
+	secretClient.Register(file.SecretScheme, fileClient)
+
+	var acmeClient *acme.SecretClient
+	if acmeSolver != nil {
+		storage, err := k8s.NewACMEStorage(config.K8sConfig.RestConfig, config.ACMEAccountSecret.Namespace, config.ACMEAccountSecret.Name)
+		if err != nil {
+			config.Logger.Error("error initializing the ACME certificate storage", "error", err)
+			return nil, nil, nil, err
+		}
+		acmeClient, err = acme.NewSecretClient(config.Logger.Named("acme-cert-fetcher"), config.ACMEConfig, storage, acmeSolver)
+		if err != nil {
+			config.Logger.Error("error initializing the ACME client", "error", err)
+			return nil, nil, nil, err
+		}
+		secretClient.Register(acme.SecretScheme, acmeClient)
+	}
+
+	return secretClient, vaultClient, acmeClient, nil
 }
 
 func parseConsulHTTPAddress() (scheme string, cmd string, port int, err error) {
@@ -49,7 +49,17 @@ func (a *Authenticator) WithTries(tries uint64) *Authenticator {
 // Authenticate logs into Consul using the given auth method and returns the generated
 // token.
 func (a *Authenticator) Authenticate(ctx context.Context, service, bearerToken string) (string, error) {
-	var token string
+	token, err := a.Login(ctx, service, bearerToken)
+	if err != nil {
+		return "", err
+	}
+	return token.SecretID, nil
+}
+
+// Login logs into Consul using the given auth method and returns the generated
+// token along with its metadata, such as when it expires.
+func (a *Authenticator) Login(ctx context.Context, service, bearerToken string) (*api.ACLToken, error) {
+	var token *api.ACLToken
 	var err error
 
 	err = backoff.Retry(func() error {
@@ -62,7 +72,7 @@ func (a *Authenticator) Authenticate(ctx context.Context, service, bearerToken s
 	return token, err
 }
 
-func (a *Authenticator) authenticate(ctx context.Context, service, bearerToken string) (string, error) {
+func (a *Authenticator) authenticate(ctx context.Context, service, bearerToken string) (*api.ACLToken, error) {
 	gwName := service
 
 	opts := &api.WriteOptions{}
@@ -77,7 +87,7 @@ func (a *Authenticator) authenticate(ctx context.Context, service, bearerToken s
 		Meta:        map[string]string{authMetaKey: gwName},
 	}, opts.WithContext(ctx))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return token.SecretID, nil
+	return token, nil
 }
@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-hclog"
+)
+
+// WatchedService is a Consul service that an owner depends on along with
+// whether or not the owner last saw it registered in the catalog.
+type WatchedService struct {
+	Name       api.CompoundServiceName
+	Registered bool
+}
+
+// CatalogWatcher uses blocking queries to watch the Consul catalog for the
+// services that owners, i.e. routes, depend on. Whenever a service is
+// registered or deregistered in a way that an owner has not yet observed,
+// the handler is called with the owner's id so that it can be re-resolved.
+type CatalogWatcher struct {
+	ctx     context.Context
+	catalog consulCatalog
+	logger  hclog.Logger
+	handler func(owner string)
+
+	// owners maps an owner id to the registration state it last observed
+	// for each of the services it depends on
+	owners   map[string]map[api.CompoundServiceName]bool
+	watchers map[api.CompoundServiceName]*catalogServiceWatcher
+	// guards the above maps
+	mutex sync.Mutex
+}
+
+type consulCatalog interface {
+	Service(service, tag string, q *api.QueryOptions) ([]*api.CatalogService, *api.QueryMeta, error)
+}
+
+// NewCatalogWatcher creates a new CatalogWatcher, all blocking queries are
+// stopped once the given context is canceled.
+func NewCatalogWatcher(ctx context.Context, logger hclog.Logger, client Client, handler func(owner string)) *CatalogWatcher {
+	return &CatalogWatcher{
+		ctx:      ctx,
+		catalog:  client.Catalog(),
+		logger:   logger,
+		handler:  handler,
+		owners:   make(map[string]map[api.CompoundServiceName]bool),
+		watchers: make(map[api.CompoundServiceName]*catalogServiceWatcher),
+	}
+}
+
+// Watch replaces the set of services that the given owner depends on.
+func (c *CatalogWatcher) Watch(owner string, services ...WatchedService) {
+	if len(services) == 0 {
+		c.Unwatch(owner)
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	stale := false
+	observed := make(map[api.CompoundServiceName]bool, len(services))
+	for _, service := range services {
+		observed[service.Name] = service.Registered
+		watcher, found := c.watchers[service.Name]
+		if !found {
+			c.watchers[service.Name] = newCatalogServiceWatcher(c.ctx, service.Name, c)
+			continue
+		}
+		// the service may have changed while the owner was being resolved
+		if watcher.synced && watcher.registered != service.Registered {
+			observed[service.Name] = watcher.registered
+			stale = true
+		}
+	}
+	c.owners[owner] = observed
+	c.cancelUnusedWatchers()
+
+	if stale {
+		// don't block the caller, which is likely resolving the owner itself
+		go c.handler(owner)
+	}
+}
+
+// Unwatch stops tracking the services that the given owner depends on.
+func (c *CatalogWatcher) Unwatch(owner string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, found := c.owners[owner]; !found {
+		return
+	}
+	delete(c.owners, owner)
+	c.cancelUnusedWatchers()
+}
+
+func (c *CatalogWatcher) cancelUnusedWatchers() {
+	for name, watcher := range c.watchers {
+		used := false
+		for _, observed := range c.owners {
+			if _, found := observed[name]; found {
+				used = true
+				break
+			}
+		}
+		if !used {
+			watcher.cancel()
+			delete(c.watchers, name)
+		}
+	}
+}
+
+// update records the current registration state of a service and notifies
+// every owner that last saw the service in a different state.
+func (c *CatalogWatcher) update(watcher *catalogServiceWatcher, registered bool) {
+	name := watcher.name
+
+	c.mutex.Lock()
+	if c.watchers[name] != watcher {
+		// the watcher was canceled while its query was in flight
+		c.mutex.Unlock()
+		return
+	}
+	watcher.synced = true
+	watcher.registered = registered
+
+	stale := []string{}
+	for owner, observed := range c.owners {
+		if previous, found := observed[name]; found && previous != registered {
+			observed[name] = registered
+			stale = append(stale, owner)
+		}
+	}
+	c.mutex.Unlock()
+
+	for _, owner := range stale {
+		c.logger.Trace("consul catalog changed", "service", name.Name, "namespace", name.Namespace, "registered", registered, "owner", owner)
+		c.handler(owner)
+	}
+}
+
+// catalogServiceWatcher runs the blocking queries for a single service
+type catalogServiceWatcher struct {
+	name    api.CompoundServiceName
+	ctx     context.Context
+	cancel  context.CancelFunc
+	watcher *CatalogWatcher
+
+	// the result of the last query, guarded by the CatalogWatcher's mutex
+	synced     bool
+	registered bool
+}
+
+func newCatalogServiceWatcher(ctx context.Context, name api.CompoundServiceName, watcher *CatalogWatcher) *catalogServiceWatcher {
+	child, cancel := context.WithCancel(ctx)
+	w := &catalogServiceWatcher{
+		name:    name,
+		ctx:     child,
+		cancel:  cancel,
+		watcher: watcher,
+	}
+	go w.watchLoop()
+	return w
+}
+
+func (w *catalogServiceWatcher) watchLoop() {
+	var index uint64
+	for {
+		opts := &api.QueryOptions{WaitIndex: index, Namespace: w.name.Namespace}
+		services, meta, err := w.watcher.catalog.Service(w.name.Name, "", opts.WithContext(w.ctx))
+		if err != nil {
+			if w.ctx.Err() != nil {
+				return
+			}
+			w.watcher.logger.Warn("blocking query for catalog service failed", "service", w.name.Name, "error", err)
+			select {
+			case <-w.ctx.Done():
+				return
+			case <-time.After(time.Second):
+				// avoid hot looping on error
+			}
+			continue
+		}
+
+		if meta.LastIndex < index {
+			index = 0
+		} else {
+			index = meta.LastIndex
+		}
+
+		select {
+		case <-w.ctx.Done():
+			return
+		default:
+			w.watcher.update(w, len(services) > 0)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestCatalogWatcher(t *testing.T) {
+	require := require.New(t)
+	consulSrv, err := testutil.NewTestServerConfigT(t, func(c *testutil.TestServerConfig) {
+		c.Peering = nil
+	})
+	require.NoError(err)
+	consulSrv.WaitForLeader(t)
+	cfg := api.DefaultConfig()
+	cfg.Address = consulSrv.HTTPAddr
+	c, err := api.NewClient(cfg)
+	require.NoError(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		_ = consulSrv.Stop()
+	})
+
+	notifications := make(chan string, 10)
+	watcher := NewCatalogWatcher(ctx, hclog.NewNullLogger(), NewTestClient(c), func(owner string) {
+		notifications <- owner
+	})
+
+	expectNotification := func(owners ...string) {
+		notified := []string{}
+		for range owners {
+			select {
+			case owner := <-notifications:
+				notified = append(notified, owner)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for notifications for %v", owners)
+			}
+		}
+		require.ElementsMatch(owners, notified)
+	}
+	expectNoNotification := func() {
+		select {
+		case notified := <-notifications:
+			t.Fatalf("unexpected notification for %s", notified)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	name := api.CompoundServiceName{Name: "backend"}
+	watcher.Watch("route", WatchedService{Name: name})
+	expectNoNotification()
+
+	// registering the service re-triggers the route
+	require.NoError(c.Agent().ServiceRegister(&api.AgentServiceRegistration{
+		Name:    "backend",
+		Port:    9991,
+		Address: "127.0.0.1",
+	}))
+	expectNotification("route")
+
+	// the route has re-resolved the service
+	watcher.Watch("route", WatchedService{Name: name, Registered: true})
+	expectNoNotification()
+
+	// a route that resolved while the service was missing is re-triggered
+	// as soon as it starts watching
+	watcher.Watch("stale", WatchedService{Name: name})
+	expectNotification("stale")
+
+	// deregistering the service re-triggers both routes
+	require.NoError(c.Agent().ServiceDeregister("backend"))
+	expectNotification("route", "stale")
+
+	// unwatched routes aren't notified
+	watcher.Unwatch("route")
+	watcher.Unwatch("stale")
+	require.NoError(c.Agent().ServiceRegister(&api.AgentServiceRegistration{
+		Name:    "backend",
+		Port:    9991,
+		Address: "127.0.0.1",
+	}))
+	expectNoNotification()
+
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+	require.Empty(watcher.watchers)
+}
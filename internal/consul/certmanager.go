@@ -119,6 +119,7 @@ type CertManager struct {
 	privateKey          []byte
 	tlsCertificate      *tls.Certificate
 	rootCertificatePool *x509.CertPool
+	trustDomain         string
 
 	// watches
 	rootWatch *watch.Plan
@@ -172,6 +173,7 @@ func (c *CertManager) handleRootWatch(blockParam watch.BlockingParamVal, raw int
 	}
 
 	c.rootCertificatePool = roots
+	c.trustDomain = v.TrustDomain
 
 	if err := c.writeCerts(); err != nil {
 		c.logger.Error("error persisting root certificates")
@@ -332,6 +334,14 @@ func (c *CertManager) RootPool() *x509.CertPool {
 	return c.rootCertificatePool
 }
 
+// TrustDomain returns the SPIFFE trust domain of the connect root CA
+func (c *CertManager) TrustDomain() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.trustDomain
+}
+
 // Certificate returns the current leaf cert
 func (c *CertManager) Certificate() []byte {
 	c.lock.RLock()
@@ -96,6 +96,7 @@ func TestManage(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, server.fakeRootCertPEM, string(rootCA))
 			require.Equal(t, server.fakeRootCertPEM, string(manager.RootCA()))
+			require.Equal(t, fakeTrustDomain, manager.TrustDomain())
 			require.Equal(t, server.fakeClientCert, string(clientCert))
 			require.Equal(t, server.fakeClientCert, string(manager.Certificate()))
 			require.Equal(t, server.fakeClientPrivateKey, string(clientPrivateKey))
@@ -166,6 +167,8 @@ func TestManage_WaitCancel(t *testing.T) {
 	require.Error(t, err)
 }
 
+const fakeTrustDomain = "11111111-2222-3333-4444-555555555555.consul"
+
 type certServer struct {
 	consul *api.Client
 
@@ -237,6 +240,7 @@ func runCertServer(t *testing.T, leafFailures, rootFailures uint64, service stri
 				return
 			}
 			rootCert, err := json.Marshal(map[string]interface{}{
+				"TrustDomain": fakeTrustDomain,
 				"Roots": []map[string]interface{}{{
 					"RootCert": server.fakeRootCertPEM,
 					"Active":   true,
@@ -8,6 +8,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -27,12 +28,17 @@ type Client interface {
 	Catalog() *api.Catalog
 	ConfigEntries() *api.ConfigEntries
 	DiscoveryChain() *api.DiscoveryChain
+	KV() *api.KV
 	Namespaces() *api.Namespaces
 	Peerings() PeeringClient
 
 	WatchServers(ctx context.Context) error
 
 	Token() string
+	// RetainToken returns the current ACL token along with its expiration, the zero
+	// time if it doesn't expire, and keeps the token from being logged out when the
+	// client logs in again. Only the most recently retained token is kept.
+	RetainToken() (string, time.Time)
 	Wait(until time.Duration) error
 
 	// TODO: drop this
@@ -50,6 +56,9 @@ type ClientConfig struct {
 	GRPCPort        int
 	TLS             *tls.Config
 	Credentials     discovery.Credentials
+	// BearerTokenFile, if set, is read for the bearer token on every
+	// login so that rotated service account tokens are picked up
+	BearerTokenFile string
 	Logger          hclog.Logger
 }
 
@@ -59,6 +68,13 @@ type client struct {
 	token       string
 	mutex       sync.RWMutex
 	initialized chan error
+	failed      chan error
+	configMutex sync.Mutex
+
+	// the session the client is using and the one, if any,
+	// whose token has been retained for a longer lived consumer
+	current  *session
+	retained *session
 }
 
 func NewClient(config ClientConfig) Client {
@@ -66,6 +82,7 @@ func NewClient(config ClientConfig) Client {
 	return &client{
 		config:      config,
 		initialized: make(chan error, 1),
+		failed:      make(chan error, 1),
 	}
 }
 
@@ -79,67 +96,192 @@ func (c *client) Wait(until time.Duration) error {
 }
 
 func (c *client) WatchServers(ctx context.Context) error {
-	if !c.config.UseDynamic {
-		c.config.ApiClientConfig.Address = fmt.Sprintf("%s:%d", c.config.Addresses, c.config.HTTPPort)
-
-		var err error
-		var client *api.Client
-		var token string
-		if c.config.Credentials.Type == discovery.CredentialsTypeLogin {
-			baseClient, err := api.NewClient(c.config.ApiClientConfig)
-			if err != nil {
-				c.initialized <- err
-				return err
-			}
-			if c.config.Namespace != "" {
-				c.config.ApiClientConfig.Namespace = c.config.Namespace
-			}
-			client, token, err = login(ctx, baseClient, c.config)
-			if err != nil {
-				c.initialized <- err
-				return err
-			}
-			defer logout(baseClient, token, c.config)
+	if c.config.UseDynamic {
+		return c.manageSessions(ctx, c.watch)
+	}
 
-		} else {
-			// this might be empty
-			c.config.ApiClientConfig.Token = c.config.Credentials.Static.Token
-			if c.config.Namespace != "" {
-				c.config.ApiClientConfig.Namespace = c.config.Namespace
-			}
-			client, err = api.NewClient(c.config.ApiClientConfig)
-			if err != nil {
-				c.initialized <- err
-				return err
+	c.config.ApiClientConfig.Address = fmt.Sprintf("%s:%d", c.config.Addresses, c.config.HTTPPort)
+
+	if c.config.Credentials.Type == discovery.CredentialsTypeLogin {
+		baseClient, err := api.NewClient(c.config.ApiClientConfig)
+		if err != nil {
+			c.initialized <- err
+			return err
+		}
+		if c.config.Namespace != "" {
+			c.config.ApiClientConfig.Namespace = c.config.Namespace
+		}
+		return c.manageSessions(ctx, func(ctx context.Context) (*session, error) {
+			return c.login(ctx, baseClient)
+		})
+	}
+
+	// this might be empty
+	c.config.ApiClientConfig.Token = c.config.Credentials.Static.Token
+	if c.config.Namespace != "" {
+		c.config.ApiClientConfig.Namespace = c.config.Namespace
+	}
+	client, err := api.NewClient(c.config.ApiClientConfig)
+	if err != nil {
+		c.initialized <- err
+		return err
+	}
+
+	c.mutex.Lock()
+	c.client = client
+	c.token = c.config.ApiClientConfig.Token
+	c.mutex.Unlock()
+
+	close(c.initialized)
+
+	<-ctx.Done()
+	return nil
+}
+
+// manageSessions starts a session and then replaces it with a new one whenever it
+// needs to be refreshed, ending all sessions once the given context is canceled
+func (c *client) manageSessions(ctx context.Context, start func(context.Context) (*session, error)) error {
+	current, err := start(ctx)
+	if err != nil {
+		c.initialized <- err
+		return err
+	}
+	c.setSession(current)
+	close(c.initialized)
+	defer c.endSessions()
+
+	refresh := current.refresh
+	for {
+		if err := c.waitUntil(ctx, refresh); err != nil {
+			if ctx.Err() != nil {
+				return nil
 			}
+			return err
 		}
 
-		c.mutex.Lock()
-		c.client = client
-		c.token = c.config.ApiClientConfig.Token
-		c.mutex.Unlock()
+		c.config.Logger.Debug("logging in to consul before token expiration")
+		next, err := start(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			c.config.Logger.Error("error logging in to consul, retrying", "error", err)
+			refresh = time.Now().Add(loginRetryInterval)
+			continue
+		}
+		c.setSession(next)
+		refresh = next.refresh
+	}
+}
 
-		close(c.initialized)
+// waitUntil blocks until the given time, forever if it's zero, returning
+// early if the context is canceled or the current session fails
+func (c *client) waitUntil(ctx context.Context, t time.Time) error {
+	var ch <-chan time.Time
+	if !t.IsZero() {
+		timer := time.NewTimer(time.Until(t))
+		defer timer.Stop()
+		ch = timer.C
+	}
 
-		<-ctx.Done()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-c.failed:
+		return err
+	case <-ch:
 		return nil
 	}
+}
 
-	var static bool
-	serverName := c.config.Addresses
-	if strings.Contains(serverName, "=") {
-		serverName = ""
-	} else {
-		static = true
+// setSession switches the client over to the given session, ending
+// the previous one unless its token has been retained
+func (c *client) setSession(s *session) {
+	c.mutex.Lock()
+	previous := c.current
+	c.current = s
+	c.client = s.client
+	c.token = s.token
+	retained := c.retained
+	c.mutex.Unlock()
+
+	if previous != nil && previous != retained {
+		previous.close()
 	}
-	if c.config.TLS != nil && c.config.TLS.ServerName != "" {
-		serverName = c.config.TLS.ServerName
+}
+
+func (c *client) endSessions() {
+	c.mutex.Lock()
+	current, retained := c.current, c.retained
+	c.mutex.Unlock()
+
+	current.close()
+	if retained != nil {
+		retained.close()
+	}
+}
+
+// login logs in with the configured auth method and returns
+// a session for a client that uses the resulting token
+func (c *client) login(ctx context.Context, baseClient *api.Client) (*session, error) {
+	bearerToken, err := c.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	authenticator := NewAuthenticator(
+		c.config.Logger.Named("authenticator"),
+		baseClient,
+		c.config.Credentials.Login.AuthMethod,
+		c.config.Credentials.Login.Namespace,
+	)
+	token, err := authenticator.Login(ctx, c.config.Name, bearerToken)
+	if err != nil {
+		return nil, fmt.Errorf("error logging in to consul: %w", err)
+	}
+
+	// Now create a client that will read the ACL token we just fetched.
+	config := *c.config.ApiClientConfig
+	config.Token = token.SecretID
+	client, err := api.NewClient(&config)
+	if err != nil {
+		_ = logout(baseClient, token.SecretID, c.config)
+		return nil, fmt.Errorf("error updating client connection with token: %w", err)
+	}
+
+	var expiration time.Time
+	if token.ExpirationTime != nil {
+		expiration = *token.ExpirationTime
+	}
+	return &session{
+		client:     client,
+		token:      token.SecretID,
+		expiration: expiration,
+		refresh:    refreshTime(time.Now(), expiration, bearerTokenExpiration(bearerToken)),
+		end: func() {
+			if err := logout(baseClient, token.SecretID, c.config); err != nil {
+				c.config.Logger.Error("error logging out of consul", "error", err)
+			}
+		},
+	}, nil
+}
+
+// watch starts a server watcher that logs in with the configured
+// credentials and returns a session for a client that follows it
+func (c *client) watch(ctx context.Context) (*session, error) {
+	credentials := c.config.Credentials
+	if credentials.Type == discovery.CredentialsTypeLogin {
+		bearerToken, err := c.bearerToken()
+		if err != nil {
+			return nil, err
+		}
+		credentials.Login.BearerToken = bearerToken
 	}
 
 	config := discovery.Config{
 		Addresses:   c.config.Addresses,
 		GRPCPort:    c.config.GRPCPort,
-		Credentials: c.config.Credentials,
+		Credentials: credentials,
 	}
 
 	if !c.config.PlainText {
@@ -148,62 +290,130 @@ func (c *client) WatchServers(ctx context.Context) error {
 
 	watcher, err := discovery.NewWatcher(ctx, config, c.config.Logger)
 	if err != nil {
-		c.initialized <- err
-		return err
+		return nil, err
 	}
 	go watcher.Run()
-	defer watcher.Stop()
 
 	// Wait for initial state.
 	state, err := watcher.State()
 	if err != nil {
-		c.initialized <- err
-		return err
+		watcher.Stop()
+		return nil, err
+	}
+	client, err := c.serverClient(state)
+	if err != nil {
+		watcher.Stop()
+		return nil, err
 	}
-	updateClient := func(s discovery.State) error {
-		cfg := c.config.ApiClientConfig
-		if c.config.Namespace != "" {
-			cfg.Namespace = c.config.Namespace
-		}
-		cfg.Address = fmt.Sprintf("%s:%d", s.Address.IP.String(), c.config.HTTPPort)
-		if static {
-			// This is to fix the fact that s.Address always resolves to an IP, if
-			// we pass a DNS address without an IPSANS, regardless of setting cfg.TLSConfig.Address
-			// below, we have a connection error on cert validation.
-			cfg.Address = fmt.Sprintf("%s:%d", c.config.Addresses, c.config.HTTPPort)
-		}
-		cfg.Token = s.Token
-		cfg.TLSConfig.Address = serverName
 
-		client, err := api.NewClient(cfg)
-		if err != nil {
-			return err
+	stopped := make(chan struct{})
+	s := &session{
+		client: client,
+		token:  state.Token,
+		end: func() {
+			watcher.Stop()
+			close(stopped)
+		},
+	}
+	if credentials.Type == discovery.CredentialsTypeLogin {
+		s.expiration = tokenExpiration(client, c.config.Logger)
+		s.refresh = refreshTime(time.Now(), s.expiration, bearerTokenExpiration(credentials.Login.BearerToken))
+	}
+
+	go func() {
+		ch := watcher.Subscribe()
+		for {
+			select {
+			case state := <-ch:
+				client, err := c.serverClient(state)
+
+				c.mutex.Lock()
+				current := c.current == s
+				if err == nil {
+					s.client = client
+					if current {
+						c.client = client
+						c.token = state.Token
+					}
+				}
+				c.mutex.Unlock()
+
+				if err != nil && current {
+					select {
+					case c.failed <- err:
+					default:
+					}
+				}
+			case <-stopped:
+				return
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
 
-		c.mutex.Lock()
-		c.client = client
-		c.token = s.Token
-		c.mutex.Unlock()
+	return s, nil
+}
 
-		return nil
+// serverClient returns a client for the server in the given watcher state
+func (c *client) serverClient(s discovery.State) (*api.Client, error) {
+	// watchers that are still running for a retained token may
+	// update concurrently with the current one
+	c.configMutex.Lock()
+	defer c.configMutex.Unlock()
+
+	var static bool
+	serverName := c.config.Addresses
+	if strings.Contains(serverName, "=") {
+		serverName = ""
+	} else {
+		static = true
 	}
-	if err := updateClient(state); err != nil {
-		c.initialized <- err
-		return err
+	if c.config.TLS != nil && c.config.TLS.ServerName != "" {
+		serverName = c.config.TLS.ServerName
 	}
-	close(c.initialized)
 
-	ch := watcher.Subscribe()
-	for {
-		select {
-		case state := <-ch:
-			if err := updateClient(state); err != nil {
-				return err
-			}
-		case <-ctx.Done():
-			return nil
-		}
+	cfg := c.config.ApiClientConfig
+	if c.config.Namespace != "" {
+		cfg.Namespace = c.config.Namespace
+	}
+	cfg.Address = fmt.Sprintf("%s:%d", s.Address.IP.String(), c.config.HTTPPort)
+	if static {
+		// This is to fix the fact that s.Address always resolves to an IP, if
+		// we pass a DNS address without an IPSANS, regardless of setting cfg.TLSConfig.Address
+		// below, we have a connection error on cert validation.
+		cfg.Address = fmt.Sprintf("%s:%d", c.config.Addresses, c.config.HTTPPort)
+	}
+	cfg.Token = s.Token
+	cfg.TLSConfig.Address = serverName
+
+	return api.NewClient(cfg)
+}
+
+// bearerToken returns the token to log in to Consul with
+func (c *client) bearerToken() (string, error) {
+	if c.config.BearerTokenFile == "" {
+		return c.config.Credentials.Login.BearerToken, nil
+	}
+	data, err := os.ReadFile(c.config.BearerTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading bearer token: %w", err)
 	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// tokenExpiration returns when the token used by the given client expires,
+// or the zero time if it doesn't or its expiration can't be read
+func tokenExpiration(client *api.Client, logger hclog.Logger) time.Time {
+	token, _, err := client.ACL().TokenReadSelf(nil)
+	if err != nil {
+		logger.Warn("unable to read acl token expiration", "error", err)
+		return time.Time{}
+	}
+	if token.ExpirationTime == nil {
+		return time.Time{}
+	}
+	return *token.ExpirationTime
 }
 
 func (c *client) Agent() *api.Agent {
@@ -234,6 +444,13 @@ func (c *client) DiscoveryChain() *api.DiscoveryChain {
 	return c.client.DiscoveryChain()
 }
 
+func (c *client) KV() *api.KV {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	return c.client.KV()
+}
+
 func (c *client) Namespaces() *api.Namespaces {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
@@ -262,6 +479,23 @@ func (c *client) Token() string {
 	return c.token
 }
 
+func (c *client) RetainToken() (string, time.Time) {
+	c.mutex.Lock()
+	previous, current := c.retained, c.current
+	c.retained = current
+	token := c.token
+	c.mutex.Unlock()
+
+	if previous != nil && previous != current {
+		previous.close()
+	}
+	if current == nil {
+		// static tokens aren't ours to log out
+		return token, time.Time{}
+	}
+	return current.token, current.expiration
+}
+
 func (c *client) Internal() *api.Client {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
@@ -269,28 +503,6 @@ func (c *client) Internal() *api.Client {
 	return c.client
 }
 
-func login(ctx context.Context, client *api.Client, config ClientConfig) (*api.Client, string, error) {
-	authenticator := NewAuthenticator(
-		config.Logger.Named("authenticator"),
-		client,
-		config.Credentials.Login.AuthMethod,
-		config.Credentials.Login.Namespace,
-	)
-
-	token, err := authenticator.Authenticate(ctx, config.Name, config.Credentials.Login.BearerToken)
-	if err != nil {
-		return nil, "", fmt.Errorf("error logging in to consul: %w", err)
-	}
-
-	// Now update the client so that it will read the ACL token we just fetched.
-	config.ApiClientConfig.Token = token
-	newClient, err := api.NewClient(config.ApiClientConfig)
-	if err != nil {
-		return nil, "", fmt.Errorf("error updating client connection with token: %w", err)
-	}
-	return newClient, token, nil
-}
-
 func logout(client *api.Client, token string, config ClientConfig) error {
 	config.Logger.Info("deleting acl token")
 	_, err := client.ACL().Logout(&api.WriteOptions{Token: token})
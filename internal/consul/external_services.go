@@ -0,0 +1,363 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// ExternalServiceNode is the synthetic catalog node that all external services
+	// registered by consul-api-gateway are attached to.
+	ExternalServiceNode = "consul-api-gateway-external-services"
+	// DefaultTerminatingGateway is the name of the terminating gateway that
+	// external services are linked to if no other name is configured.
+	DefaultTerminatingGateway = "terminating-gateway"
+
+	externalServiceNodeAddress = "127.0.0.1"
+	externalServiceSource      = "consul-api-gateway"
+
+	// externalServiceSourceMeta marks the services registered by consul-api-gateway
+	externalServiceSourceMeta = "external-source"
+	// externalServiceOwnerMeta records the Kubernetes object a service was registered for
+	externalServiceOwnerMeta = "external-owner"
+)
+
+// ExternalServiceConflictError is returned when external services aren't registered
+// because their names are already taken in the Consul catalog, either by services
+// that consul-api-gateway didn't register or by those registered for another object.
+type ExternalServiceConflictError struct {
+	Conflicts []string
+}
+
+func (e *ExternalServiceConflictError) Error() string {
+	return fmt.Sprintf("external services not registered: %s", strings.Join(e.Conflicts, "; "))
+}
+
+// ExternalService describes a service that does not participate in the service mesh
+// but that is registered in the Consul catalog so that it can be reached through a
+// terminating gateway.
+type ExternalService struct {
+	Name    api.CompoundServiceName
+	Address string
+	Port    int
+	// Owner identifies the Kubernetes object the service is registered for, only
+	// that object's registration can update or remove the service
+	Owner string
+
+	// The following optionally configure the terminating gateway to originate
+	// TLS to the service, file paths are relative to the terminating gateway.
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	SNI      string
+}
+
+func (s ExternalService) linkedService() api.LinkedService {
+	return api.LinkedService{
+		Name:      s.Name.Name,
+		Namespace: s.Name.Namespace,
+		CAFile:    s.CAFile,
+		CertFile:  s.CertFile,
+		KeyFile:   s.KeyFile,
+		SNI:       s.SNI,
+	}
+}
+
+// ExternalServiceRegistry registers services that live outside of the service mesh in
+// the Consul catalog and links them to a terminating gateway. Note that, like the
+// ServiceRegistry, it is *not* thread safe and callers must synchronize access to it.
+type ExternalServiceRegistry struct {
+	client  Client
+	logger  hclog.Logger
+	gateway string
+
+	tries           uint64
+	backoffInterval time.Duration
+}
+
+// NewExternalServiceRegistry creates a new registry that links external services to
+// the given terminating gateway.
+func NewExternalServiceRegistry(logger hclog.Logger, client Client, terminatingGateway string) *ExternalServiceRegistry {
+	if terminatingGateway == "" {
+		terminatingGateway = DefaultTerminatingGateway
+	}
+	return &ExternalServiceRegistry{
+		client:          client,
+		logger:          logger,
+		gateway:         terminatingGateway,
+		tries:           defaultMaxAttempts,
+		backoffInterval: defaultBackoffInterval,
+	}
+}
+
+// Register registers the given services in the Consul catalog and adds them to the
+// terminating gateway's linked services. Services whose names are already taken are
+// skipped and returned in an ExternalServiceConflictError once the rest are registered.
+func (r *ExternalServiceRegistry) Register(ctx context.Context, services ...ExternalService) error {
+	if len(services) == 0 {
+		return nil
+	}
+
+	registered := make([]ExternalService, 0, len(services))
+	conflicts := []string{}
+	for _, service := range services {
+		conflict, err := r.conflict(ctx, service)
+		if err != nil {
+			return err
+		}
+		if conflict != "" {
+			conflicts = append(conflicts, conflict)
+			continue
+		}
+
+		registration := &api.CatalogRegistration{
+			Node:    ExternalServiceNode,
+			Address: externalServiceNodeAddress,
+			NodeMeta: map[string]string{
+				"external-node":  "true",
+				"external-probe": "false",
+			},
+			Service: &api.AgentService{
+				ID:        service.Name.Name,
+				Service:   service.Name.Name,
+				Namespace: service.Name.Namespace,
+				Address:   service.Address,
+				Port:      service.Port,
+				Meta: map[string]string{
+					externalServiceSourceMeta: externalServiceSource,
+					externalServiceOwnerMeta:  service.Owner,
+				},
+			},
+		}
+		if _, err := r.client.Catalog().Register(registration, (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+			return fmt.Errorf("error registering external service %s: %w", service.Name.Name, err)
+		}
+		registered = append(registered, service)
+	}
+
+	if err := r.updateLinkedServices(ctx, func(entry *api.TerminatingGatewayConfigEntry) bool {
+		updated := false
+		for _, service := range registered {
+			linked := service.linkedService()
+			switch i := linkedServiceIndex(entry, service.Name); {
+			case i == -1:
+				entry.Services = append(entry.Services, linked)
+				updated = true
+			case !sameTLSOrigination(entry.Services[i], linked):
+				entry.Services[i].CAFile = linked.CAFile
+				entry.Services[i].CertFile = linked.CertFile
+				entry.Services[i].KeyFile = linked.KeyFile
+				entry.Services[i].SNI = linked.SNI
+				updated = true
+			}
+		}
+		return updated
+	}); err != nil {
+		return err
+	}
+
+	if len(conflicts) > 0 {
+		return &ExternalServiceConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// conflict returns why a service can't be registered under its name, if it can't
+func (r *ExternalServiceRegistry) conflict(ctx context.Context, service ExternalService) (string, error) {
+	instances, _, err := r.client.Catalog().Service(service.Name.Name, "", (&api.QueryOptions{Namespace: service.Name.Namespace}).WithContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("error checking for existing service %s: %w", service.Name.Name, err)
+	}
+	for _, instance := range instances {
+		if instance.Node != ExternalServiceNode || instance.ServiceMeta[externalServiceSourceMeta] != externalServiceSource {
+			return fmt.Sprintf("%s is already registered in Consul", service.Name.Name), nil
+		}
+		if !ownedBy(instance.ServiceMeta, service.Owner) {
+			return fmt.Sprintf("%s is already registered for %s", service.Name.Name, instance.ServiceMeta[externalServiceOwnerMeta]), nil
+		}
+	}
+	return "", nil
+}
+
+// ownedBy returns whether a registration was made for the given owner, registrations made
+// before owners were recorded are treated as belonging to whoever registers them next
+func ownedBy(meta map[string]string, owner string) bool {
+	registeredFor := meta[externalServiceOwnerMeta]
+	return registeredFor == "" || registeredFor == owner
+}
+
+// Registered returns the external services registered by consul-api-gateway in the Consul
+// catalog, so that a restarted controller can remove those that are no longer referenced.
+func (r *ExternalServiceRegistry) Registered(ctx context.Context) ([]ExternalService, error) {
+	// the default namespace
+	namespaces := []string{""}
+	consulNamespaces, _, err := r.client.Namespaces().List((&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		if !strings.Contains(err.Error(), "Unexpected response code: 404") {
+			return nil, fmt.Errorf("error listing namespaces: %w", err)
+		}
+		// we're dealing with an OSS version of Consul, which only has the default namespace
+	} else {
+		for _, namespace := range consulNamespaces {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+
+	services := []ExternalService{}
+	seen := make(map[api.CompoundServiceName]struct{})
+	filter := fmt.Sprintf(`Meta[%q] == %q`, externalServiceSourceMeta, externalServiceSource)
+	for _, namespace := range namespaces {
+		node, _, err := r.client.Catalog().NodeServiceList(ExternalServiceNode, (&api.QueryOptions{
+			Filter:    filter,
+			Namespace: namespace,
+		}).WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("error listing external services: %w", err)
+		}
+		if node == nil {
+			continue
+		}
+		for _, service := range node.Services {
+			name := api.CompoundServiceName{Name: service.Service, Namespace: normalizeNamespace(service.Namespace)}
+			if _, ok := seen[name]; ok {
+				// the default namespace is listed both explicitly and by name
+				continue
+			}
+			seen[name] = struct{}{}
+			services = append(services, ExternalService{
+				Name:    name,
+				Address: service.Address,
+				Port:    service.Port,
+				Owner:   service.Meta[externalServiceOwnerMeta],
+			})
+		}
+	}
+	return services, nil
+}
+
+// Deregister removes the given services from the terminating gateway's linked services
+// and deregisters them from the Consul catalog. Services that are registered for a
+// different owner are left in place.
+func (r *ExternalServiceRegistry) Deregister(ctx context.Context, services ...ExternalService) error {
+	names := []api.CompoundServiceName{}
+	for _, service := range services {
+		owned, err := r.owns(ctx, service)
+		if err != nil {
+			return err
+		}
+		if owned {
+			names = append(names, service.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	if err := r.updateLinkedServices(ctx, func(entry *api.TerminatingGatewayConfigEntry) bool {
+		updated := false
+		for _, name := range names {
+			if i := linkedServiceIndex(entry, name); i != -1 {
+				entry.Services = append(entry.Services[:i], entry.Services[i+1:]...)
+				updated = true
+			}
+		}
+		return updated
+	}); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if _, err := r.client.Catalog().Deregister(&api.CatalogDeregistration{
+			Node:      ExternalServiceNode,
+			ServiceID: name.Name,
+			Namespace: name.Namespace,
+		}, (&api.WriteOptions{}).WithContext(ctx)); err != nil {
+			return fmt.Errorf("error deregistering external service %s: %w", name.Name, err)
+		}
+	}
+	return nil
+}
+
+// owns returns whether a service is registered for its owner
+func (r *ExternalServiceRegistry) owns(ctx context.Context, service ExternalService) (bool, error) {
+	instances, _, err := r.client.Catalog().Service(service.Name.Name, "", (&api.QueryOptions{Namespace: service.Name.Namespace}).WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("error checking for existing service %s: %w", service.Name.Name, err)
+	}
+	for _, instance := range instances {
+		if instance.Node == ExternalServiceNode && instance.ServiceMeta[externalServiceSourceMeta] == externalServiceSource && ownedBy(instance.ServiceMeta, service.Owner) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *ExternalServiceRegistry) updateLinkedServices(ctx context.Context, updateFn func(entry *api.TerminatingGatewayConfigEntry) bool) error {
+	return backoff.Retry(func() error {
+		entry, idx, err := r.getOrInitTerminatingGateway(ctx)
+		if err != nil {
+			return err
+		}
+
+		if !updateFn(entry) {
+			return nil
+		}
+
+		ok, _, err := r.client.ConfigEntries().CAS(entry, idx, (&api.WriteOptions{}).WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("CAS operation failed")
+		}
+		return nil
+	}, backoff.WithContext(backoff.WithMaxRetries(backoff.NewConstantBackOff(r.backoffInterval), r.tries), ctx))
+}
+
+func (r *ExternalServiceRegistry) getOrInitTerminatingGateway(ctx context.Context) (*api.TerminatingGatewayConfigEntry, uint64, error) {
+	entry, _, err := r.client.ConfigEntries().Get(api.TerminatingGateway, r.gateway, (&api.QueryOptions{}).WithContext(ctx))
+	if err == nil {
+		return entry.(*api.TerminatingGatewayConfigEntry), entry.GetModifyIndex(), nil
+	}
+
+	if strings.Contains(err.Error(), "Unexpected response code: 404") {
+		return &api.TerminatingGatewayConfigEntry{
+			Kind: api.TerminatingGateway,
+			Name: r.gateway,
+		}, 0, nil
+	}
+
+	return nil, 0, err
+}
+
+func linkedServiceIndex(entry *api.TerminatingGatewayConfigEntry, name api.CompoundServiceName) int {
+	for i, service := range entry.Services {
+		if service.Name == name.Name && normalizeNamespace(service.Namespace) == normalizeNamespace(name.Namespace) {
+			return i
+		}
+	}
+	return -1
+}
+
+func sameTLSOrigination(a, b api.LinkedService) bool {
+	return a.CAFile == b.CAFile && a.CertFile == b.CertFile && a.KeyFile == b.KeyFile && a.SNI == b.SNI
+}
+
+// normalizeNamespace maps the default namespace returned by Consul Enterprise
+// to the empty namespace we use when writing entries
+func normalizeNamespace(namespace string) string {
+	if namespace == api.IntentionDefaultNamespace {
+		return ""
+	}
+	return namespace
+}
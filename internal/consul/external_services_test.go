@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+)
+
+func TestExternalServiceRegistry(t *testing.T) {
+	t.Parallel()
+
+	consulSrv, err := testutil.NewTestServerConfigT(t, func(c *testutil.TestServerConfig) {
+		c.Connect = map[string]interface{}{"enabled": true}
+		c.Peering = nil
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = consulSrv.Stop()
+	})
+	consulSrv.WaitForLeader(t)
+
+	cfg := api.DefaultConfig()
+	cfg.Address = consulSrv.HTTPAddr
+	c, err := api.NewClient(cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	registry := NewExternalServiceRegistry(testutil.Logger(t), NewTestClient(c), "")
+
+	external := api.CompoundServiceName{Name: "external"}
+	other := api.CompoundServiceName{Name: "other"}
+	require.NoError(t, registry.Register(ctx, ExternalService{
+		Name:    external,
+		Address: "10.0.0.1",
+		Port:    8080,
+		Owner:   "Service/default/external",
+	}, ExternalService{
+		Name:    other,
+		Address: "10.0.0.2",
+		Port:    9090,
+		Owner:   "Service/default/other",
+	}))
+
+	services, _, err := c.Catalog().Service("external", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, ExternalServiceNode, services[0].Node)
+	require.Equal(t, "10.0.0.1", services[0].ServiceAddress)
+	require.Equal(t, 8080, services[0].ServicePort)
+
+	entry, _, err := c.ConfigEntries().Get(api.TerminatingGateway, DefaultTerminatingGateway, nil)
+	require.NoError(t, err)
+	require.Len(t, entry.(*api.TerminatingGatewayConfigEntry).Services, 2)
+
+	// registering again is a no-op for the terminating gateway
+	require.NoError(t, registry.Register(ctx, ExternalService{
+		Name:    external,
+		Address: "10.0.0.1",
+		Port:    8080,
+		Owner:   "Service/default/external",
+	}))
+	entry, _, err = c.ConfigEntries().Get(api.TerminatingGateway, DefaultTerminatingGateway, nil)
+	require.NoError(t, err)
+	require.Len(t, entry.(*api.TerminatingGatewayConfigEntry).Services, 2)
+
+	// changing TLS origination options updates the linked service
+	require.NoError(t, registry.Register(ctx, ExternalService{
+		Name:    external,
+		Address: "10.0.0.1",
+		Port:    8080,
+		Owner:   "Service/default/external",
+		CAFile:  "/etc/ssl/ca.pem",
+		SNI:     "external.example.com",
+	}))
+	entry, _, err = c.ConfigEntries().Get(api.TerminatingGateway, DefaultTerminatingGateway, nil)
+	require.NoError(t, err)
+	linked := entry.(*api.TerminatingGatewayConfigEntry).Services
+	require.Len(t, linked, 2)
+	require.Equal(t, "/etc/ssl/ca.pem", linked[0].CAFile)
+	require.Equal(t, "external.example.com", linked[0].SNI)
+
+	// services can't be taken over by objects in other namespaces
+	err = registry.Register(ctx, ExternalService{
+		Name:    external,
+		Address: "10.0.0.3",
+		Port:    8080,
+		Owner:   "Service/other/external",
+	})
+	var conflict *ExternalServiceConflictError
+	require.ErrorAs(t, err, &conflict)
+	require.Equal(t, []string{"external is already registered for Service/default/external"}, conflict.Conflicts)
+	require.NoError(t, registry.Deregister(ctx, ExternalService{Name: external, Owner: "Service/other/external"}))
+
+	services, _, err = c.Catalog().Service("external", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, "10.0.0.1", services[0].ServiceAddress)
+
+	// nor can services that consul-api-gateway didn't register
+	_, err = c.Catalog().Register(&api.CatalogRegistration{
+		Node:    "node",
+		Address: "10.0.0.4",
+		Service: &api.AgentService{Service: "mesh", Port: 8080},
+	}, nil)
+	require.NoError(t, err)
+	err = registry.Register(ctx, ExternalService{
+		Name:    api.CompoundServiceName{Name: "mesh"},
+		Address: "10.0.0.5",
+		Port:    8080,
+		Owner:   "Service/default/mesh",
+	})
+	require.ErrorAs(t, err, &conflict)
+	require.Equal(t, []string{"mesh is already registered in Consul"}, conflict.Conflicts)
+	services, _, err = c.Catalog().Service("mesh", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	require.Equal(t, "node", services[0].Node)
+
+	// the registrations can be listed after a restart
+	registered, err := registry.Registered(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []ExternalService{{
+		Name:    external,
+		Address: "10.0.0.1",
+		Port:    8080,
+		Owner:   "Service/default/external",
+	}, {
+		Name:    other,
+		Address: "10.0.0.2",
+		Port:    9090,
+		Owner:   "Service/default/other",
+	}}, registered)
+
+	require.NoError(t, registry.Deregister(ctx, ExternalService{Name: external, Owner: "Service/default/external"}))
+
+	services, _, err = c.Catalog().Service("external", "", nil)
+	require.NoError(t, err)
+	require.Len(t, services, 0)
+
+	entry, _, err = c.ConfigEntries().Get(api.TerminatingGateway, DefaultTerminatingGateway, nil)
+	require.NoError(t, err)
+	require.Equal(t, []api.LinkedService{{Name: "other"}}, entry.(*api.TerminatingGatewayConfigEntry).Services)
+}
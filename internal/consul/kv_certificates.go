@@ -0,0 +1,281 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/envoy"
+	gwmetrics "github.com/hashicorp/consul-api-gateway/internal/metrics"
+)
+
+var _ envoy.SecretClient = (*KVSecretClient)(nil)
+var _ envoy.SecretWatcher = (*KVSecretClient)(nil)
+var _ envoy.SecretUnwatcher = (*KVSecretClient)(nil)
+
+const (
+	// kvWatchWaitTime is the maximum time a blocking query on a KV entry waits for a change
+	kvWatchWaitTime = 5 * time.Minute
+	// kvWatchRetryInterval is how long we wait before retrying a failed blocking query
+	kvWatchRetryInterval = 5 * time.Second
+)
+
+// kvEntry identifies a single KV entry that certificates are read from
+type kvEntry struct {
+	key       string
+	namespace string
+	partition string
+}
+
+func (e kvEntry) queryOptions() *api.QueryOptions {
+	return &api.QueryOptions{
+		Namespace: e.namespace,
+		Partition: e.partition,
+	}
+}
+
+// kvWatch tracks the secrets read from a KV entry and the index they were read at
+type kvWatch struct {
+	index   uint64
+	secrets map[string]struct{}
+	// stop is set once the blocking queries are started
+	stop context.CancelFunc
+}
+
+// KVSecretClient acts as a secret fetcher for PEM encoded certificates stored in Consul KV
+type KVSecretClient struct {
+	logger hclog.Logger
+	client Client
+
+	watches map[kvEntry]*kvWatch
+	mutex   sync.Mutex
+	// fetched signals the watcher that a new entry may need watching
+	fetched chan struct{}
+}
+
+// NewKVSecretClient initializes a KVSecretClient instance
+func NewKVSecretClient(logger hclog.Logger, client Client) *KVSecretClient {
+	return &KVSecretClient{
+		logger:  logger,
+		client:  client,
+		watches: make(map[kvEntry]*kvWatch),
+		fetched: make(chan struct{}, 1),
+	}
+}
+
+// FetchSecret reads a certificate described with the url name of
+// consul+kv:///path/to/certificate?privateKey=path/to/key
+func (c *KVSecretClient) FetchSecret(ctx context.Context, name string) (*tls.Secret, time.Time, error) {
+	secret, err := ParseKVSecret(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	c.logger.Trace("fetching SDS secret", "name", name)
+	gwmetrics.Registry.IncrCounterWithLabels(gwmetrics.SDSCertificateFetches, 1, []metrics.Label{{
+		Name:  "fetcher",
+		Value: "consul-kv",
+	}, {
+		Name:  "name",
+		Value: name,
+	}})
+
+	values := make(map[string][]byte)
+	for _, key := range secret.keys() {
+		entry := kvEntry{key: key, namespace: secret.Namespace, partition: secret.Partition}
+		pair, meta, err := c.client.KV().Get(key, entry.queryOptions().WithContext(ctx))
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("error reading consul kv entry %q: %w", key, err)
+		}
+		// track the entry even if it doesn't exist yet so that we pick up its creation
+		c.track(name, entry, meta.LastIndex)
+		if pair == nil {
+			return nil, time.Time{}, fmt.Errorf("consul kv entry %q not found", key)
+		}
+		values[key] = pair.Value
+	}
+
+	certificateChain := values[secret.Key]
+	certificatePrivateKey := certificateChain
+	if secret.PrivateKeyKey != "" {
+		certificatePrivateKey = values[secret.PrivateKeyKey]
+	}
+
+	block, rest := pem.Decode(certificateChain)
+	for block != nil && block.Type != "CERTIFICATE" {
+		// combined entries may start with the private key
+		block, rest = pem.Decode(rest)
+	}
+	if block == nil {
+		return nil, time.Time{}, errors.New("failed to parse certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return &tls.Secret{
+		Type: &tls.Secret_TlsCertificate{
+			TlsCertificate: &tls.TlsCertificate{
+				CertificateChain: &core.DataSource{
+					Specifier: &core.DataSource_InlineBytes{
+						InlineBytes: certificateChain,
+					},
+				},
+				PrivateKey: &core.DataSource{
+					Specifier: &core.DataSource_InlineBytes{
+						InlineBytes: certificatePrivateKey,
+					},
+				},
+			},
+		},
+		Name: name,
+	}, cert.NotAfter, nil
+}
+
+// WatchSecrets runs a blocking query for every KV entry that a fetched certificate
+// was read from and sends a change for each secret using an entry when it is modified.
+func (c *KVSecretClient) WatchSecrets(ctx context.Context, changes chan<- envoy.SecretChange) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	start := func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		for entry, watch := range c.watches {
+			if watch.stop != nil {
+				continue
+			}
+			watchCtx, stop := context.WithCancel(ctx)
+			watch.stop = stop
+
+			wg.Add(1)
+			go func(entry kvEntry, index uint64) {
+				defer wg.Done()
+				c.watchEntry(watchCtx, entry, index, changes)
+			}(entry, watch.index)
+		}
+	}
+	start()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.fetched:
+			start()
+		}
+	}
+}
+
+// watchEntry runs blocking queries against a KV entry starting
+// from the given index until the context is canceled.
+func (c *KVSecretClient) watchEntry(ctx context.Context, entry kvEntry, index uint64, changes chan<- envoy.SecretChange) {
+	for {
+		options := entry.queryOptions()
+		options.WaitIndex = index
+		options.WaitTime = kvWatchWaitTime
+
+		_, meta, err := c.client.KV().Get(entry.key, options.WithContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Error("error watching consul kv entry", "key", entry.key, "error", err)
+			select {
+			case <-time.After(kvWatchRetryInterval):
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if meta.LastIndex == index {
+			// the blocking query timed out
+			continue
+		}
+		if meta.LastIndex < index {
+			// the index went backwards, i.e. the raft snapshot was restored, so
+			// start over rather than blocking until the index catches up
+			index = 0
+			continue
+		}
+		index = meta.LastIndex
+
+		changed := time.Now()
+		for _, name := range c.secretsFor(entry) {
+			c.logger.Trace("consul kv entry changed", "name", name, "key", entry.key)
+			select {
+			case changes <- envoy.SecretChange{Name: name, Changed: changed}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// UnwatchSecret stops watching a secret that is no longer referenced, the blocking
+// queries for its entries are stopped once no other watched secrets read from them
+func (c *KVSecretClient) UnwatchSecret(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for entry, watch := range c.watches {
+		delete(watch.secrets, name)
+		if len(watch.secrets) > 0 {
+			continue
+		}
+		if watch.stop != nil {
+			watch.stop()
+		}
+		delete(c.watches, entry)
+	}
+}
+
+func (c *KVSecretClient) track(name string, entry kvEntry, index uint64) {
+	c.mutex.Lock()
+	watch, ok := c.watches[entry]
+	if !ok {
+		watch = &kvWatch{
+			index:   index,
+			secrets: make(map[string]struct{}),
+		}
+		c.watches[entry] = watch
+	}
+	watch.secrets[name] = struct{}{}
+	c.mutex.Unlock()
+
+	if !ok {
+		select {
+		case c.fetched <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *KVSecretClient) secretsFor(entry kvEntry) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	names := []string{}
+	if watch, ok := c.watches[entry]; ok {
+		for name := range watch.secrets {
+			names = append(names, name)
+		}
+	}
+	return names
+}
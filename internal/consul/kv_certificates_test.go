@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul-api-gateway/internal/envoy"
+	gwTesting "github.com/hashicorp/consul-api-gateway/internal/testing"
+)
+
+func TestKVSecretClient(t *testing.T) {
+	t.Parallel()
+
+	consulSrv, err := testutil.NewTestServerConfigT(t, func(c *testutil.TestServerConfig) {
+		c.Peering = nil
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = consulSrv.Stop()
+	})
+	consulSrv.WaitForLeader(t)
+
+	cfg := api.DefaultConfig()
+	cfg.Address = consulSrv.HTTPAddr
+	c, err := api.NewClient(cfg)
+	require.NoError(t, err)
+
+	_, server, client := gwTesting.DefaultCertificates()
+	put := func(key string, value []byte) {
+		_, err := c.KV().Put(&api.KVPair{Key: key, Value: value}, nil)
+		require.NoError(t, err)
+	}
+	put("certs/server/cert", server.CertBytes)
+	put("certs/server/key", server.PrivateKeyBytes)
+	put("certs/combined", append(append([]byte{}, server.PrivateKeyBytes...), server.CertBytes...))
+	put("certs/invalid", []byte("invalid"))
+
+	secretClient := NewKVSecretClient(testutil.Logger(t), NewTestClient(c))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan envoy.SecretChange)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- secretClient.WatchSecrets(ctx, changes)
+	}()
+
+	name := NewKVSecret("certs/server/cert", "certs/server/key").String()
+	secret, expires, err := secretClient.FetchSecret(ctx, name)
+	require.NoError(t, err)
+	require.Equal(t, name, secret.Name)
+	require.Equal(t, server.CertBytes, secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+	require.Equal(t, server.PrivateKeyBytes, secret.GetTlsCertificate().PrivateKey.GetInlineBytes())
+	require.WithinDuration(t, server.Cert.NotAfter, expires, time.Second)
+
+	// the private key can be read from the certificate entry
+	combined, expires, err := secretClient.FetchSecret(ctx, NewKVSecret("certs/combined", "").String())
+	require.NoError(t, err)
+	require.Equal(t, combined.GetTlsCertificate().CertificateChain.GetInlineBytes(), combined.GetTlsCertificate().PrivateKey.GetInlineBytes())
+	require.WithinDuration(t, server.Cert.NotAfter, expires, time.Second)
+
+	_, _, err = secretClient.FetchSecret(ctx, NewKVSecret("certs/invalid", "").String())
+	require.EqualError(t, err, "failed to parse certificate PEM")
+
+	missing := NewKVSecret("certs/missing", "").String()
+	_, _, err = secretClient.FetchSecret(ctx, missing)
+	require.EqualError(t, err, `consul kv entry "certs/missing" not found`)
+
+	expectChange := func(name string) {
+		t.Helper()
+
+		select {
+		case change := <-changes:
+			require.Equal(t, name, change.Name)
+			require.False(t, change.Changed.IsZero())
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for a change to %s", name)
+		}
+	}
+
+	// updating the private key entry changes the secret
+	put("certs/server/key", client.PrivateKeyBytes)
+	expectChange(name)
+
+	// creating a missing entry changes the secret that failed to fetch
+	put("certs/missing", append(append([]byte{}, client.PrivateKeyBytes...), client.CertBytes...))
+	expectChange(missing)
+	secret, _, err = secretClient.FetchSecret(ctx, missing)
+	require.NoError(t, err)
+	require.Equal(t, client.PrivateKeyBytes, secret.GetTlsCertificate().PrivateKey.GetInlineBytes()[:len(client.PrivateKeyBytes)])
+
+	// unwatched secrets stop their blocking queries once no other secret reads their entries
+	secretClient.UnwatchSecret(name)
+	secretClient.UnwatchSecret(missing)
+	secretClient.mutex.Lock()
+	require.Len(t, secretClient.watches, 2)
+	require.NotContains(t, secretClient.watches, kvEntry{key: "certs/server/key"})
+	secretClient.mutex.Unlock()
+
+	put("certs/server/key", server.PrivateKeyBytes)
+	select {
+	case change := <-changes:
+		t.Fatalf("unexpected change to %s", change.Name)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-watchErr)
+}
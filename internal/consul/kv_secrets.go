@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrInvalidKVSecret = errors.New("invalid consul kv secret")
+)
+
+const (
+	KVSecretScheme = "consul+kv"
+
+	queryParamPrivateKey = "privateKey"
+	queryParamNamespace  = "namespace"
+	queryParamPartition  = "partition"
+)
+
+// KVSecret describes a PEM encoded certificate chain and private key stored in
+// Consul KV. If no separate private key entry is given, the key is read from
+// the certificate entry.
+type KVSecret struct {
+	Key           string
+	PrivateKeyKey string
+	// Namespace and Partition optionally scope the KV entries in Consul Enterprise
+	Namespace string
+	Partition string
+}
+
+func NewKVSecret(key, privateKeyKey string) KVSecret {
+	return KVSecret{
+		Key:           key,
+		PrivateKeyKey: privateKeyKey,
+	}
+}
+
+func ParseKVSecret(ref string) (KVSecret, error) {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return KVSecret{}, err
+	}
+
+	if parsed.Scheme != KVSecretScheme {
+		return KVSecret{}, ErrInvalidKVSecret
+	}
+
+	key := strings.TrimPrefix(parsed.Path, "/")
+	if key == "" {
+		return KVSecret{}, ErrInvalidKVSecret
+	}
+
+	query := parsed.Query()
+	secret := NewKVSecret(key, strings.TrimPrefix(query.Get(queryParamPrivateKey), "/"))
+	secret.Namespace = query.Get(queryParamNamespace)
+	secret.Partition = query.Get(queryParamPartition)
+	return secret, nil
+}
+
+func (s KVSecret) String() string {
+	v := url.Values{}
+
+	if s.PrivateKeyKey != "" {
+		v.Add(queryParamPrivateKey, s.PrivateKeyKey)
+	}
+	if s.Namespace != "" {
+		v.Add(queryParamNamespace, s.Namespace)
+	}
+	if s.Partition != "" {
+		v.Add(queryParamPartition, s.Partition)
+	}
+
+	return (&url.URL{
+		Scheme:   KVSecretScheme,
+		Path:     "/" + s.Key,
+		RawQuery: v.Encode(),
+	}).String()
+}
+
+// keys returns the KV entries that the secret is read from
+func (s KVSecret) keys() []string {
+	if s.PrivateKeyKey == "" || s.PrivateKeyKey == s.Key {
+		return []string{s.Key}
+	}
+	return []string{s.Key, s.PrivateKeyKey}
+}
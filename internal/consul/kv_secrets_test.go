@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKVSecret(t *testing.T) {
+	// Test empty name
+	_, err := ParseKVSecret("")
+	assert.EqualError(t, ErrInvalidKVSecret, err.Error())
+
+	// Test invalid scheme
+	_, err = ParseKVSecret("vault+kv:///certs/example")
+	assert.EqualError(t, ErrInvalidKVSecret, err.Error())
+
+	// Test missing key
+	_, err = ParseKVSecret("consul+kv:///")
+	assert.EqualError(t, ErrInvalidKVSecret, err.Error())
+
+	// Test combined entry
+	secret, err := ParseKVSecret("consul+kv:///certs/example")
+	require.NoError(t, err)
+	assert.Equal(t, "certs/example", secret.Key)
+	assert.Empty(t, secret.PrivateKeyKey)
+	assert.Equal(t, []string{"certs/example"}, secret.keys())
+
+	// Test full set of serialized values
+	secret, err = ParseKVSecret("consul+kv:///certs/example/cert?privateKey=certs/example/key&namespace=ns&partition=part")
+	require.NoError(t, err)
+	assert.Equal(t, "certs/example/cert", secret.Key)
+	assert.Equal(t, "certs/example/key", secret.PrivateKeyKey)
+	assert.Equal(t, "ns", secret.Namespace)
+	assert.Equal(t, "part", secret.Partition)
+	assert.Equal(t, []string{"certs/example/cert", "certs/example/key"}, secret.keys())
+
+	// Test round trip
+	secret2, err := ParseKVSecret(secret.String())
+	require.NoError(t, err)
+	assert.Equal(t, secret, secret2)
+}
+
+func TestKVSecret_String(t *testing.T) {
+	secret := NewKVSecret("certs/example", "")
+	assert.Equal(t, "consul+kv:///certs/example", secret.String())
+
+	secret.PrivateKeyKey = "certs/key"
+	secret.Namespace = "ns"
+	assert.Equal(t, "consul+kv:///certs/example?namespace=ns&privateKey=certs%2Fkey", secret.String())
+}
@@ -32,6 +32,10 @@ func (c *TestClient) Token() string {
 	return ""
 }
 
+func (c *TestClient) RetainToken() (string, time.Time) {
+	return "", time.Time{}
+}
+
 func (c *TestClient) Wait(time.Duration) error {
 	return nil
 }
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+const (
+	// the shortest time we wait before logging in again, this keeps us
+	// from hammering Consul if we're handed a token that's about to expire
+	minRefreshInterval = 10 * time.Second
+	// how long to wait before retrying a failed login
+	loginRetryInterval = 5 * time.Second
+)
+
+// session is a single login to Consul and the client that uses its token
+type session struct {
+	client     *api.Client
+	token      string
+	expiration time.Time
+	// when the session should be replaced by a new login,
+	// zero if it never needs to be
+	refresh time.Time
+
+	end     func()
+	endOnce sync.Once
+}
+
+// close ends the session, logging its token out of Consul
+func (s *session) close() {
+	s.endOnce.Do(s.end)
+}
+
+// refreshTime returns when a login should be refreshed given the expirations of its
+// ACL token and of the bearer token it was made with. A third of the remaining lifetime
+// is left for logging in again. The zero time is returned if neither token expires.
+func refreshTime(now time.Time, expirations ...time.Time) time.Time {
+	var earliest time.Time
+	for _, expiration := range expirations {
+		if !expiration.IsZero() && (earliest.IsZero() || expiration.Before(earliest)) {
+			earliest = expiration
+		}
+	}
+	if earliest.IsZero() {
+		return earliest
+	}
+
+	wait := earliest.Sub(now) * 2 / 3
+	if wait < minRefreshInterval {
+		wait = minRefreshInterval
+	}
+	return now.Add(wait)
+}
+
+// bearerTokenExpiration returns when the given JWT expires, or the zero time
+// if it doesn't expire or isn't a JWT. The token's signature isn't verified,
+// Consul does that when we log in with it.
+func bearerTokenExpiration(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Expiry int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Expiry, 0)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consul
+
+import (
+	"encoding/base64"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestRefreshTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	require.True(t, refreshTime(now).IsZero())
+	require.True(t, refreshTime(now, time.Time{}, time.Time{}).IsZero())
+	require.Equal(t, now.Add(40*time.Minute), refreshTime(now, now.Add(time.Hour)))
+	require.Equal(t, now.Add(20*time.Minute), refreshTime(now, now.Add(time.Hour), now.Add(30*time.Minute)))
+	require.Equal(t, now.Add(20*time.Minute), refreshTime(now, time.Time{}, now.Add(30*time.Minute)))
+	require.Equal(t, now.Add(minRefreshInterval), refreshTime(now, now.Add(time.Second)))
+	require.Equal(t, now.Add(minRefreshInterval), refreshTime(now, now.Add(-time.Minute)))
+}
+
+func TestBearerTokenExpiration(t *testing.T) {
+	t.Parallel()
+
+	jwt := func(payload string) string {
+		return "header." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+	}
+
+	require.Equal(t, time.Unix(1700000000, 0), bearerTokenExpiration(jwt(`{"aud":["consul"],"exp":1700000000}`)))
+	require.True(t, bearerTokenExpiration(jwt(`{"aud":["consul"]}`)).IsZero())
+	require.True(t, bearerTokenExpiration(jwt(`not json`)).IsZero())
+	require.True(t, bearerTokenExpiration("header.!!!.signature").IsZero())
+	require.True(t, bearerTokenExpiration("opaque-token").IsZero())
+}
+
+func TestClientBearerToken(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+	file := path.Join(directory, "token")
+
+	c := &client{}
+	c.config.Credentials.Login.BearerToken = "static"
+	token, err := c.bearerToken()
+	require.NoError(t, err)
+	require.Equal(t, "static", token)
+
+	c.config.BearerTokenFile = file
+	_, err = c.bearerToken()
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(file, []byte("first\n"), 0600))
+	token, err = c.bearerToken()
+	require.NoError(t, err)
+	require.Equal(t, "first", token)
+
+	// rotated tokens are picked up
+	require.NoError(t, os.WriteFile(file, []byte("second\n"), 0600))
+	token, err = c.bearerToken()
+	require.NoError(t, err)
+	require.Equal(t, "second", token)
+}
+
+func TestClientSessions(t *testing.T) {
+	t.Parallel()
+
+	closed := map[string]int{}
+	newSession := func(token string, expiration time.Time) *session {
+		return &session{
+			token:      token,
+			expiration: expiration,
+			end: func() {
+				closed[token]++
+			},
+		}
+	}
+
+	expiration := time.Now().Add(time.Hour)
+	c := &client{config: ClientConfig{Logger: hclog.NewNullLogger()}}
+
+	c.setSession(newSession("first", expiration))
+	require.Equal(t, "first", c.Token())
+
+	token, retainedExpiration := c.RetainToken()
+	require.Equal(t, "first", token)
+	require.Equal(t, expiration, retainedExpiration)
+
+	// the retained token is kept when the client logs in again
+	c.setSession(newSession("second", time.Time{}))
+	require.Equal(t, "second", c.Token())
+	require.Empty(t, closed)
+
+	// tokens that were never retained are logged out once replaced
+	c.setSession(newSession("third", time.Time{}))
+	require.Equal(t, map[string]int{"second": 1}, closed)
+
+	// retaining a new token releases the old one
+	token, retainedExpiration = c.RetainToken()
+	require.Equal(t, "third", token)
+	require.True(t, retainedExpiration.IsZero())
+	require.Equal(t, map[string]int{"first": 1, "second": 1}, closed)
+
+	c.endSessions()
+	require.Equal(t, map[string]int{"first": 1, "second": 1, "third": 1}, closed)
+}
@@ -11,6 +11,28 @@ import (
 type ResolvedService struct {
 	ConsulNamespace string
 	Service         string
+	// External is set for services that live outside of the service mesh
+	// and must be registered in Consul before they can be routed to.
+	External *ExternalService `json:",omitempty"`
+}
+
+// ExternalService describes how to reach a service that is registered
+// in Consul as an external service behind a terminating gateway.
+type ExternalService struct {
+	Address string
+	Port    int
+	TLS     *ExternalServiceTLS `json:",omitempty"`
+	// Owner identifies what the service is registered for
+	Owner string `json:",omitempty"`
+}
+
+// ExternalServiceTLS holds the options used by a terminating gateway
+// when originating TLS to an external service.
+type ExternalServiceTLS struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	SNI      string
 }
 
 type ResolvedRouteType string
@@ -6,10 +6,18 @@ package envoy
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
+	"syscall"
 	"text/template"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
@@ -19,6 +27,14 @@ import (
 const (
 	// this allows for envoy to log to JSON
 	logFormatString = `{"timestamp":"%Y-%m-%d %T.%e","thread":"%t","level":"%l","name":"%n","source":"%g:%#","message":"%v"}`
+
+	// the admin address rendered into the bootstrap configuration
+	adminHost = "127.0.0.1"
+	adminPort = 19000
+	// how long envoy gets to drain its connections before being stopped
+	defaultDrainTime = 10 * time.Second
+	// how long envoy gets to exit once it's been told to before it's killed
+	shutdownTimeout = 5 * time.Second
 )
 
 var (
@@ -26,6 +42,8 @@ var (
 )
 
 type bootstrapArgs struct {
+	AdminAddress  string
+	AdminPort     int
 	ID            string
 	Namespace     string
 	Partition     string
@@ -60,6 +78,8 @@ type ManagerConfig struct {
 	ExtraArgs         []string
 	Output            io.Writer
 	ForceTLS          bool
+	// DrainTime is how long envoy drains its connections for when stopped
+	DrainTime time.Duration
 }
 
 // Manager wraps and manages an envoy process and its bootstrap configuration
@@ -75,6 +95,9 @@ func NewManager(logger hclog.Logger, config ManagerConfig) *Manager {
 	if config.Output == nil {
 		config.Output = os.Stdout
 	}
+	if config.DrainTime == 0 {
+		config.DrainTime = defaultDrainTime
+	}
 	m := &Manager{
 		logger:        logger,
 		ManagerConfig: config,
@@ -83,27 +106,100 @@ func NewManager(logger hclog.Logger, config ManagerConfig) *Manager {
 	return m
 }
 
-// Run spawns the envoy process
+// Run spawns the envoy process, once the context is canceled envoy drains its
+// connections and is shut down gracefully rather than being killed outright
 func (m *Manager) Run(ctx context.Context) error {
 	m.logger.Trace("running envoy")
 	process, args := m.commandFunc()
-	cmd := exec.CommandContext(ctx, process, args...)
+	cmd := exec.Command(process, args...)
 	cmd.Stdout = m.Output
 	cmd.Stderr = m.Output
-	err := cmd.Run()
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
 
-	// Turns out that when a command spawned with CommandContext
-	// has its context canceled, rather than returnng an error about
-	// canceled context, it sends an error about the spawned process
-	// being killed. This select allows us to effectively check whether
-	// or not the process stopped because the context was canceled even
-	// if the cancellation error isn't propagated up to us.
 	select {
+	case err := <-exited:
+		return err
 	case <-ctx.Done():
+		// any error from envoy exiting is swallowed since we stopped it
+		m.stop(cmd.Process, exited)
 		return nil
-	default:
+	}
+}
+
+// stop fails envoy's health checks, which has it close HTTP connections once their
+// in-flight requests complete, waits out the drain time and then shuts envoy down
+func (m *Manager) stop(process *os.Process, exited <-chan error) {
+	if err := m.drain(); err != nil {
+		m.logger.Warn("error draining envoy connections", "error", err)
+	} else {
+		select {
+		case <-exited:
+			return
+		case <-time.After(m.DrainTime):
+		}
+	}
+
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		m.logger.Warn("error stopping envoy", "error", err)
+	}
+	select {
+	case <-exited:
+	case <-time.After(shutdownTimeout):
+		m.logger.Warn("envoy did not shut down in time, killing it")
+		_ = process.Kill()
+		<-exited
+	}
+}
+
+func (m *Manager) drain() error {
+	address, err := m.adminAddress()
+	if err != nil {
 		return err
 	}
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Post(fmt.Sprintf("http://%s/healthcheck/fail", address), "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// adminAddress reads the address of envoy's admin interface from the bootstrap
+// configuration it was started with
+func (m *Manager) adminAddress() (string, error) {
+	data, err := os.ReadFile(m.BootstrapFilePath)
+	if err != nil {
+		return "", err
+	}
+	var bootstrap struct {
+		Admin struct {
+			Address struct {
+				SocketAddress struct {
+					Address   string `json:"address"`
+					PortValue int    `json:"port_value"`
+				} `json:"socket_address"`
+			} `json:"address"`
+		} `json:"admin"`
+	}
+	if err := json.Unmarshal(data, &bootstrap); err != nil {
+		return "", fmt.Errorf("error parsing envoy bootstrap configuration: %w", err)
+	}
+	socket := bootstrap.Admin.Address.SocketAddress
+	if socket.Address == "" || socket.PortValue == 0 {
+		return "", errors.New("envoy bootstrap configuration has no admin address")
+	}
+	return net.JoinHostPort(socket.Address, strconv.Itoa(socket.PortValue)), nil
 }
 
 // CommandArgs returns the actual command for the manager to invoke
@@ -116,6 +212,8 @@ func (m *Manager) CommandArgs() (string, []string) {
 func (m *Manager) RenderBootstrap(sdsConfig string) error {
 	var bootstrapConfig bytes.Buffer
 	if err := bootstrapTemplate.Execute(&bootstrapConfig, &bootstrapArgs{
+		AdminAddress:  adminHost,
+		AdminPort:     adminPort,
 		SDSCluster:    sdsConfig,
 		ID:            m.ID,
 		Namespace:     m.Namespace,
@@ -138,8 +236,8 @@ const bootstrapJSONTemplate = `{
     "access_log_path": "/dev/null",
     "address": {
       "socket_address": {
-        "address": "127.0.0.1",
-        "port_value": 19000
+        "address": "{{ .AdminAddress }}",
+        "port_value": {{ .AdminPort }}
       }
     }
   },
@@ -168,8 +266,8 @@ const bootstrapJSONTemplate = `{
                   "endpoint": {
                     "address": {
                       "socket_address": {
-                        "address": "127.0.0.1",
-                        "port_value": 19000
+                        "address": "{{ .AdminAddress }}",
+                        "port_value": {{ .AdminPort }}
                       }
                     }
                   }
@@ -7,6 +7,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"testing"
@@ -96,6 +99,35 @@ func TestManagerRenderBootstrap(t *testing.T) {
 	}
 }
 
+func TestManagerDrain(t *testing.T) {
+	t.Parallel()
+
+	drained := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		drained <- r.Method + " " + r.URL.Path
+	}))
+	defer server.Close()
+
+	directory, err := os.MkdirTemp("", "consul-api-gateway-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(directory)
+
+	// the admin address is taken from the bootstrap configuration
+	filePath := path.Join(directory, "bootstrap.json")
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filePath, []byte(fmt.Sprintf(
+		`{"admin":{"address":{"socket_address":{"address":%q,"port_value":%s}}}}`, host, port,
+	)), 0600))
+
+	manager := NewManager(hclog.NewNullLogger(), ManagerConfig{BootstrapFilePath: filePath})
+	require.NoError(t, manager.drain())
+	require.Equal(t, "POST /healthcheck/fail", <-drained)
+
+	require.NoError(t, os.WriteFile(filePath, []byte(`{}`), 0600))
+	require.Error(t, manager.drain())
+}
+
 func TestCommandArgs(t *testing.T) {
 	path := uuid.New().String()
 	manager := NewManager(hclog.NewNullLogger(), ManagerConfig{
@@ -6,8 +6,13 @@ package envoy
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/armon/go-metrics"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
@@ -17,6 +22,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/consul-api-gateway/internal/core"
+	gwmetrics "github.com/hashicorp/consul-api-gateway/internal/metrics"
 	"github.com/hashicorp/consul-api-gateway/internal/store"
 )
 
@@ -54,52 +60,174 @@ func GatewayFromContext(ctx context.Context) core.GatewayID {
 }
 
 // SPIFFEStreamMiddleware verifies the spiffe entries for the certificate
-// and sets the client identidy on the request context. If no
-// spiffe information is detected, or if the service is unknown,
-// the request is rejected.
-func SPIFFEStreamMiddleware(logger hclog.Logger, store store.ReadStore) grpc.StreamServerInterceptor {
+// and sets the client identidy on the request context. If the client
+// certificate doesn't have a Consul SPIFFE ID in a trusted trust domain,
+// or if the service is unknown, the request is rejected.
+func SPIFFEStreamMiddleware(logger hclog.Logger, fetcher CertificateFetcher, allowedTrustDomains []string, store store.ReadStore) grpc.StreamServerInterceptor {
+	verifier := newSPIFFEVerifier(logger, fetcher, allowedTrustDomains, store)
 	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		if info, ok := verifySPIFFE(ss.Context(), logger, store); ok {
+		if info, ok := verifier.verify(ss.Context()); ok {
 			return handler(srv, wrapStream(ss, info))
 		}
 		return status.Errorf(codes.Unauthenticated, "unable to authenticate request")
 	}
 }
 
-func verifySPIFFE(ctx context.Context, logger hclog.Logger, store store.ReadStore) (core.GatewayID, bool) {
-	if p, ok := peer.FromContext(ctx); ok {
-		if mtls, ok := p.AuthInfo.(credentials.TLSInfo); ok {
-			// grab the peer certificate info
-			for _, item := range mtls.State.PeerCertificates {
-				// check each untyped SAN for spiffee information
-				for _, uri := range item.URIs {
-					// we skip validating the host value since not all of our potential roots (i.e. via Vault)
-					// have spiffe urls
-					if uri.Scheme == "spiffe" {
-						// make sure we have a leaf certificate that has been issued by consul
-						// with namespace, datacenter, and service information -- the namespace
-						// and service are used to inform us what gateway is trying to connect
-						info, err := parseURI(uri.Path)
-						if err != nil {
-							logger.Error("error parsing spiffe path, skipping", "error", err, "path", uri.Path)
-							continue
-						}
-						// if we're tracking the gateway then we're good
-						gateway, err := store.GetGateway(ctx, info)
-						if err != nil {
-							logger.Error("error checking for gateway, skipping", "error", err)
-							continue
-						}
-						if gateway != nil {
-							return info, true
-						}
-						logger.Warn("gateway not found", "namespace", info.ConsulNamespace, "service", info.Service)
-					}
-				}
-			}
+// reasons that an SDS connection is denied, used for metrics and audit logs
+const (
+	denyReasonNoCertificate    = "no_certificate"
+	denyReasonInvalidSPIFFEID  = "invalid_spiffe_id"
+	denyReasonUntrustedDomain  = "untrusted_trust_domain"
+	denyReasonGatewayLookup    = "gateway_lookup_error"
+	denyReasonGatewayNotFound  = "gateway_not_found"
+	gatewayLookupCacheDuration = 30 * time.Second
+)
+
+// spiffeVerifier authenticates SDS clients by the SPIFFE ID in their leaf certificate
+type spiffeVerifier struct {
+	logger              hclog.Logger
+	fetcher             CertificateFetcher
+	allowedTrustDomains map[string]struct{}
+	store               store.ReadStore
+
+	// gateways caches successful gateway lookups until the
+	// given time so we don't hit the store on every stream
+	gateways map[core.GatewayID]time.Time
+	mutex    sync.Mutex
+}
+
+func newSPIFFEVerifier(logger hclog.Logger, fetcher CertificateFetcher, allowedTrustDomains []string, store store.ReadStore) *spiffeVerifier {
+	allowed := make(map[string]struct{}, len(allowedTrustDomains))
+	for _, trustDomain := range allowedTrustDomains {
+		allowed[strings.ToLower(trustDomain)] = struct{}{}
+	}
+	return &spiffeVerifier{
+		logger:              logger,
+		fetcher:             fetcher,
+		allowedTrustDomains: allowed,
+		store:               store,
+		gateways:            make(map[core.GatewayID]time.Time),
+	}
+}
+
+func (v *spiffeVerifier) verify(ctx context.Context) (core.GatewayID, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		v.deny(nil, nil, denyReasonNoCertificate, errors.New("no peer information"))
+		return core.GatewayID{}, false
+	}
+	mtls, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(mtls.State.PeerCertificates) == 0 {
+		v.deny(p, nil, denyReasonNoCertificate, errors.New("no client certificate"))
+		return core.GatewayID{}, false
+	}
+
+	// only the leaf certificate identifies the client, the SPIFFE spec
+	// requires that it have exactly one SPIFFE ID
+	var spiffeID *url.URL
+	for _, uri := range mtls.State.PeerCertificates[0].URIs {
+		if uri.Scheme != "spiffe" {
+			continue
+		}
+		if spiffeID != nil {
+			v.deny(p, uri, denyReasonInvalidSPIFFEID, errors.New("certificate has multiple SPIFFE IDs"))
+			return core.GatewayID{}, false
+		}
+		spiffeID = uri
+	}
+	if spiffeID == nil {
+		v.deny(p, nil, denyReasonInvalidSPIFFEID, errors.New("certificate has no SPIFFE ID"))
+		return core.GatewayID{}, false
+	}
+
+	if !v.trusted(spiffeID.Host) {
+		v.deny(p, spiffeID, denyReasonUntrustedDomain, fmt.Errorf("trust domain %q is not trusted", spiffeID.Host))
+		return core.GatewayID{}, false
+	}
+
+	// make sure we have a leaf certificate that has been issued by consul
+	// with namespace, datacenter, and service information -- the namespace
+	// and service are used to inform us what gateway is trying to connect
+	info, err := parseURI(spiffeID.Path)
+	if err != nil {
+		v.deny(p, spiffeID, denyReasonInvalidSPIFFEID, err)
+		return core.GatewayID{}, false
+	}
+
+	// if we're tracking the gateway then we're good
+	found, err := v.gatewayExists(ctx, info)
+	if err != nil {
+		v.deny(p, spiffeID, denyReasonGatewayLookup, err)
+		return core.GatewayID{}, false
+	}
+	if !found {
+		v.deny(p, spiffeID, denyReasonGatewayNotFound, fmt.Errorf("no gateway found for service %q in namespace %q", info.Service, info.ConsulNamespace))
+		return core.GatewayID{}, false
+	}
+	return info, true
+}
+
+// trusted checks a trust domain against the Consul CA's trust domain and the allow-list
+func (v *spiffeVerifier) trusted(trustDomain string) bool {
+	trustDomain = strings.ToLower(trustDomain)
+	if trustDomain == "" {
+		return false
+	}
+	if _, ok := v.allowedTrustDomains[trustDomain]; ok {
+		return true
+	}
+	return trustDomain == strings.ToLower(v.fetcher.TrustDomain())
+}
+
+func (v *spiffeVerifier) gatewayExists(ctx context.Context, info core.GatewayID) (bool, error) {
+	now := time.Now()
+
+	v.mutex.Lock()
+	expires, ok := v.gateways[info]
+	v.mutex.Unlock()
+	if ok && now.Before(expires) {
+		return true, nil
+	}
+
+	gateway, err := v.store.GetGateway(ctx, info)
+	if err != nil {
+		return false, err
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if gateway == nil {
+		delete(v.gateways, info)
+		return false, nil
+	}
+	// only gateways we've found are cached, so we don't need to worry
+	// about clients growing the cache with arbitrary service names,
+	// but drop any expired entries for gateways that were deleted
+	for id, expires := range v.gateways {
+		if now.After(expires) {
+			delete(v.gateways, id)
 		}
 	}
-	return core.GatewayID{}, false
+	v.gateways[info] = now.Add(gatewayLookupCacheDuration)
+	return true, nil
+}
+
+// deny records an audit log and metric for a rejected SDS connection
+func (v *spiffeVerifier) deny(p *peer.Peer, spiffeID *url.URL, reason string, err error) {
+	args := []interface{}{"reason", reason, "error", err}
+	if p != nil && p.Addr != nil {
+		args = append(args, "peer", p.Addr.String())
+	}
+	if spiffeID != nil {
+		args = append(args, "spiffe_id", spiffeID.String())
+	}
+	v.logger.Warn("denied SDS connection", args...)
+
+	gwmetrics.Registry.IncrCounterWithLabels(gwmetrics.SDSDeniedConnections, 1, []metrics.Label{{
+		Name:  "reason",
+		Value: reason,
+	}})
 }
 
 func parseURI(path string) (core.GatewayID, error) {
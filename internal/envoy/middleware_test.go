@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package envoy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/core"
+	"github.com/hashicorp/consul-api-gateway/internal/envoy/mocks"
+	storeMocks "github.com/hashicorp/consul-api-gateway/internal/store/mocks"
+)
+
+func TestSPIFFEVerifier(t *testing.T) {
+	t.Parallel()
+
+	const trustDomain = "11111111-2222-3333-4444-555555555555.consul"
+
+	peerContext := func(uris ...string) context.Context {
+		certificate := &x509.Certificate{}
+		for _, uri := range uris {
+			parsed, err := url.Parse(uri)
+			require.NoError(t, err)
+			certificate.URIs = append(certificate.URIs, parsed)
+		}
+		return peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234},
+			AuthInfo: credentials.TLSInfo{
+				State: tls.ConnectionState{
+					PeerCertificates: []*x509.Certificate{certificate},
+				},
+			},
+		})
+	}
+
+	gatewayID := core.GatewayID{Service: "gateway"}
+
+	for _, test := range []struct {
+		name    string
+		ctx     context.Context
+		allowed []string
+		// lookups is the number of times the gateway is expected to be looked up
+		lookups int
+		found   bool
+		err     error
+		valid   bool
+	}{{
+		name:    "valid",
+		ctx:     peerContext("spiffe://" + trustDomain + "/ns/default/dc/dc1/svc/gateway"),
+		lookups: 1,
+		found:   true,
+		valid:   true,
+	}, {
+		name:    "valid in partition",
+		ctx:     peerContext("spiffe://" + trustDomain + "/ap/part/ns/default/dc/dc1/svc/gateway"),
+		lookups: 1,
+		found:   true,
+		valid:   true,
+	}, {
+		name:    "trust domain case insensitive",
+		ctx:     peerContext("spiffe://11111111-2222-3333-4444-555555555555.CONSUL/ns/default/dc/dc1/svc/gateway"),
+		lookups: 1,
+		found:   true,
+		valid:   true,
+	}, {
+		name:    "allowed trust domain",
+		ctx:     peerContext("spiffe://vault.example.com/ns/default/dc/dc1/svc/gateway"),
+		allowed: []string{"vault.example.com"},
+		lookups: 1,
+		found:   true,
+		valid:   true,
+	}, {
+		name: "untrusted trust domain",
+		ctx:  peerContext("spiffe://other.consul/ns/default/dc/dc1/svc/gateway"),
+	}, {
+		name: "no peer",
+		ctx:  context.Background(),
+	}, {
+		name: "no SPIFFE ID",
+		ctx:  peerContext("https://example.com"),
+	}, {
+		name: "multiple SPIFFE IDs",
+		ctx: peerContext(
+			"spiffe://"+trustDomain+"/ns/default/dc/dc1/svc/gateway",
+			"spiffe://"+trustDomain+"/ns/default/dc/dc1/svc/other",
+		),
+	}, {
+		name: "non-consul SPIFFE ID",
+		ctx:  peerContext("spiffe://" + trustDomain + "/workload/gateway"),
+	}, {
+		name:    "gateway not found",
+		ctx:     peerContext("spiffe://" + trustDomain + "/ns/default/dc/dc1/svc/gateway"),
+		lookups: 1,
+	}, {
+		name:    "gateway lookup error",
+		ctx:     peerContext("spiffe://" + trustDomain + "/ns/default/dc/dc1/svc/gateway"),
+		lookups: 1,
+		err:     errors.New("error"),
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fetcher := mocks.NewMockCertificateFetcher(ctrl)
+			fetcher.EXPECT().TrustDomain().AnyTimes().Return(trustDomain)
+
+			store := storeMocks.NewMockStore(ctrl)
+			if test.lookups > 0 {
+				var gateway *storeMocks.MockGateway
+				if test.found {
+					gateway = storeMocks.NewMockGateway(ctrl)
+				}
+				call := store.EXPECT().GetGateway(gomock.Any(), gatewayID).Times(test.lookups)
+				if gateway != nil {
+					call.Return(gateway, test.err)
+				} else {
+					call.Return(nil, test.err)
+				}
+			}
+
+			verifier := newSPIFFEVerifier(hclog.NewNullLogger(), fetcher, test.allowed, store)
+			info, valid := verifier.verify(test.ctx)
+			require.Equal(t, test.valid, valid)
+			if test.valid {
+				require.Equal(t, gatewayID, info)
+
+				// successful lookups are cached
+				_, valid = verifier.verify(test.ctx)
+				require.True(t, valid)
+			}
+		})
+	}
+}
@@ -62,3 +62,17 @@ func (mr *MockCertificateFetcherMockRecorder) TLSCertificate() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TLSCertificate", reflect.TypeOf((*MockCertificateFetcher)(nil).TLSCertificate))
 }
+
+// TrustDomain mocks base method.
+func (m *MockCertificateFetcher) TrustDomain() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustDomain")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// TrustDomain indicates an expected call of TrustDomain.
+func (mr *MockCertificateFetcherMockRecorder) TrustDomain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustDomain", reflect.TypeOf((*MockCertificateFetcher)(nil).TrustDomain))
+}
@@ -46,6 +46,9 @@ var logOnce sync.Once
 type CertificateFetcher interface {
 	RootPool() *x509.CertPool
 	TLSCertificate() *tls.Certificate
+	// TrustDomain returns the SPIFFE trust domain that client
+	// certificates issued by the CA belong to
+	TrustDomain() string
 }
 
 // SDSServer wraps a gRPC-based SDS Delta server
@@ -58,6 +61,7 @@ type SDSServer struct {
 	protocol                     string
 	store                        store.Store
 	certificateForcePullInterval time.Duration
+	allowedTrustDomains          []string
 }
 
 // NEWSDSServer initializes an SDSServer instance
@@ -78,6 +82,14 @@ func (s *SDSServer) WithAddress(address string, port uint) *SDSServer {
 	return s
 }
 
+// WithAllowedTrustDomains allows clients with SPIFFE IDs in the given trust domains
+// in addition to the trust domain of the Consul CA, i.e. when Consul's CA is backed
+// by Vault and leaf certificates are issued with a different trust domain
+func (s *SDSServer) WithAllowedTrustDomains(trustDomains ...string) *SDSServer {
+	s.allowedTrustDomains = trustDomains
+	return s
+}
+
 // Run starts the SDS server
 func (s *SDSServer) Run(ctx context.Context) error {
 	childCtx, cancel := context.WithCancel(ctx)
@@ -107,7 +119,7 @@ func (s *SDSServer) Run(ctx context.Context) error {
 			},
 			ClientAuth: tls.RequireAndVerifyClientCert,
 		})),
-		grpc.StreamInterceptor(SPIFFEStreamMiddleware(s.logger, s.store)),
+		grpc.StreamInterceptor(SPIFFEStreamMiddleware(s.logger, s.fetcher, s.allowedTrustDomains, s.store)),
 	}
 	s.server = grpc.NewServer(opts...)
 
@@ -122,6 +134,16 @@ func (s *SDSServer) Run(ctx context.Context) error {
 	}
 
 	go secretManager.Manage(childCtx, s.certificateForcePullInterval)
+	if watcher, ok := s.client.(SecretWatcher); ok {
+		changes := make(chan SecretChange)
+		go secretManager.ManageChanges(childCtx, changes)
+		go func() {
+			if err := watcher.WatchSecrets(childCtx, changes); err != nil {
+				// we still periodically re-fetch secrets, so this isn't fatal
+				s.logger.Error("error watching secrets for changes", "error", err)
+			}
+		}()
+	}
 	errs := make(chan error, 1)
 	go func() {
 		errs <- s.server.Serve(listener)
@@ -123,11 +123,8 @@ func TestSDSSPIFFEHostMismatch(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	err = runTestServer(t, ca.CertBytes, func(ctrl *gomock.Controller) store.Store {
-		store := storeMocks.NewMockStore(ctrl)
-		store.EXPECT().GetGateway(gomock.Any(), gomock.Any()).MinTimes(1).Return(nil, nil)
-		return store
-	}, func(serverAddress string, fetcher *mocks.MockCertificateFetcher) {
+	// the gateway is never looked up for an untrusted trust domain
+	err = runTestServer(t, ca.CertBytes, nil, func(serverAddress string, fetcher *mocks.MockCertificateFetcher) {
 		fetcher.EXPECT().TLSCertificate().Return(&server.X509)
 		err := testClientSDS(t, serverAddress, client, ca.CertBytes)
 		require.Error(t, err)
@@ -261,6 +258,7 @@ func runTestServer(t *testing.T, ca []byte, registryFn func(*gomock.Controller)
 	certPool.AddCert(caCert)
 
 	fetcher.EXPECT().RootPool().AnyTimes().Return(certPool)
+	fetcher.EXPECT().TrustDomain().AnyTimes().Return(caCert.URIs[0].Host)
 	secretClient.EXPECT().FetchSecret(gomock.Any(), "test").AnyTimes().Return(&envoyTLS.Secret{
 		Name: "test",
 	}, time.Now(), nil)
@@ -13,7 +13,12 @@ import (
 	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 
+	"github.com/armon/go-metrics"
+	"golang.org/x/sync/errgroup"
+
 	"github.com/hashicorp/go-hclog"
+
+	gwmetrics "github.com/hashicorp/consul-api-gateway/internal/metrics"
 )
 
 //go:generate mockgen -source ./secrets.go -destination ./mocks/secrets.go -package mocks SecretManager,SecretClient,SecretCache
@@ -37,7 +42,35 @@ type SecretClient interface {
 	FetchSecret(ctx context.Context, name string) (*tls.Secret, time.Time, error)
 }
 
+// SecretChange describes a change to the contents of a secret that was
+// previously fetched by a SecretClient.
+type SecretChange struct {
+	// Name is the name the secret was fetched with
+	Name string
+	// Changed is the time that the underlying secret was changed, used
+	// to track how long it takes for a rotated secret to reach the cache
+	Changed time.Time
+}
+
+// SecretWatcher is implemented by SecretClients that can watch the secrets
+// they fetch for changes. Rather than waiting on a secret to near expiration
+// or for the forced re-fetch interval, changes are pushed into the cache as
+// soon as they're observed.
+type SecretWatcher interface {
+	// WatchSecrets sends changes on the given channel until the context is canceled
+	WatchSecrets(ctx context.Context, changes chan<- SecretChange) error
+}
+
+// SecretUnwatcher is implemented by SecretWatchers that watch the secrets they've
+// fetched. Once no nodes reference a secret it's unwatched so that the client stops
+// watching it.
+type SecretUnwatcher interface {
+	UnwatchSecret(name string)
+}
+
 var _ SecretClient = (*MultiSecretClient)(nil)
+var _ SecretWatcher = (*MultiSecretClient)(nil)
+var _ SecretUnwatcher = (*MultiSecretClient)(nil)
 
 // MultiSecretClient implements a registry of secret clients that handle fetching secrets
 // based off of the protocol they're given in the secret name.
@@ -76,6 +109,38 @@ func (m *MultiSecretClient) FetchSecret(ctx context.Context, name string) (*tls.
 	return fetcher.FetchSecret(ctx, name)
 }
 
+// WatchSecrets runs the watches for all of the registered clients that
+// support them, blocking until the context is canceled or a watch fails.
+func (m *MultiSecretClient) WatchSecrets(ctx context.Context, changes chan<- SecretChange) error {
+	m.mutex.RLock()
+	group, groupCtx := errgroup.WithContext(ctx)
+	for _, fetcher := range m.fetchers {
+		if watcher, ok := fetcher.(SecretWatcher); ok {
+			group.Go(func() error {
+				return watcher.WatchSecrets(groupCtx, changes)
+			})
+		}
+	}
+	m.mutex.RUnlock()
+
+	return group.Wait()
+}
+
+// UnwatchSecret unwatches the secret with the registered client
+// for its protocol, if the client supports it.
+func (m *MultiSecretClient) UnwatchSecret(name string) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	parsed, err := url.Parse(name)
+	if err != nil {
+		return
+	}
+	if unwatcher, ok := m.fetchers[parsed.Scheme].(SecretUnwatcher); ok {
+		unwatcher.UnwatchSecret(name)
+	}
+}
+
 // SecretManager handles the lifecycle of watched TLS secrets.
 // TODO Trim down this interface - Watch() and Unwatch() are never used
 type SecretManager interface {
@@ -320,33 +385,95 @@ func (s *secretManager) Manage(ctx context.Context, forceInterval time.Duration)
 	}
 }
 
-func (s *secretManager) manage(ctx context.Context, force bool) {
-	//boolean force
+// ManageChanges re-fetches watched TLS certificates as soon as a change
+// to them is received and pushes them into the cache.
+func (s *secretManager) ManageChanges(ctx context.Context, changes <-chan SecretChange) {
+	for {
+		select {
+		case change := <-changes:
+			s.rotate(ctx, change)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *secretManager) rotate(ctx context.Context, change SecretChange) {
 	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	_, ok := s.registry[change.Name]
+	s.mutex.RUnlock()
+	if !ok {
+		// nothing is watching this secret
+		return
+	}
+
+	s.logger.Debug("rotating secret", "secret", change.Name)
+	// fetch without holding the lock so that slow fetches don't block nodes watching secrets
+	certificate, expires, err := s.client.FetchSecret(ctx, change.Name)
+	if err != nil {
+		s.logger.Error("error fetching secret", "error", err, "secret", change.Name)
+		return
+	}
+	if err := s.storeCertificate(change.Name, certificate, expires); err != nil {
+		s.logger.Error("error updating secret", "error", err, "secret", change.Name)
+		return
+	}
 
+	if !change.Changed.IsZero() {
+		gwmetrics.Registry.AddSampleWithLabels(gwmetrics.SDSCertificateRotationLatency,
+			float32(time.Since(change.Changed).Milliseconds()),
+			[]metrics.Label{{Name: "name", Value: change.Name}})
+	}
+}
+
+func (s *secretManager) manage(ctx context.Context, force bool) {
+	now := time.Now()
+
+	s.mutex.RLock()
+	expiring := []string{}
 	for secretName, secret := range s.registry {
 		// check the certificate to see if we're within a window close to its
 		// expiration, when we want to start re-fetching it because of it
 		// potentially getting re-issued
-		if force || time.Now().After(secret.expiration.Add(-s.expirationDelta)) {
-			// fetch the certificate and add it to the cache
-			certificate, expires, err := s.client.FetchSecret(ctx, secretName)
-			if err != nil {
-				s.logger.Error("error fetching secret", "error", err, "secret", secretName)
-				continue
-			}
-			err = s.updateCertificate(certificate)
-			if err != nil {
-				s.logger.Error("error updating secret", "error", err, "secret", secretName)
-				continue
-			}
-			// since we don't want to lose the referenced nodes, just update individual
-			// fields on the tracking struct
-			secret.Secret = certificate
-			secret.expiration = expires
+		if force || now.After(secret.expiration.Add(-s.expirationDelta)) {
+			expiring = append(expiring, secretName)
 		}
 	}
+	s.mutex.RUnlock()
+
+	for _, secretName := range expiring {
+		// fetch the certificate and add it to the cache
+		certificate, expires, err := s.client.FetchSecret(ctx, secretName)
+		if err != nil {
+			s.logger.Error("error fetching secret", "error", err, "secret", secretName)
+			continue
+		}
+		if err := s.storeCertificate(secretName, certificate, expires); err != nil {
+			s.logger.Error("error updating secret", "error", err, "secret", secretName)
+		}
+	}
+}
+
+// storeCertificate pushes a re-fetched certificate into the cache and updates its
+// tracking entry, unless the certificate was unwatched while it was being fetched
+func (s *secretManager) storeCertificate(name string, certificate *tls.Secret, expires time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	secret, ok := s.registry[name]
+	if !ok {
+		// the fetch may have started watching the secret again
+		s.unwatchSecret(name)
+		return nil
+	}
+	if err := s.updateCertificate(certificate); err != nil {
+		return err
+	}
+	// since we don't want to lose the referenced nodes, just update individual
+	// fields on the tracking struct
+	secret.Secret = certificate
+	secret.expiration = expires
+	return nil
 }
 
 func (s *secretManager) updateCertificate(c *tls.Secret) error {
@@ -368,9 +495,17 @@ func (s *secretManager) removeCertificates(names []string) error {
 	}
 	for _, name := range names {
 		s.logger.Debug("removing resource", "name", name)
+		s.unwatchSecret(name)
 		if err := s.cache.DeleteResource(name); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// unwatchSecret lets the client stop watching a secret that's no longer referenced
+func (s *secretManager) unwatchSecret(name string) {
+	if unwatcher, ok := s.client.(SecretUnwatcher); ok {
+		unwatcher.UnwatchSecret(name)
+	}
+}
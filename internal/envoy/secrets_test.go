@@ -220,6 +220,88 @@ func TestManage(t *testing.T) {
 	manager.manage(context.Background(), true)
 }
 
+func TestManage_UnwatchedDuringFetch(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	secretClient := mocks.NewMockSecretClient(ctrl)
+	cache := mocks.NewMockSecretCache(ctrl)
+	secret := &tls.Secret{
+		Name: "one",
+	}
+	secretClient.EXPECT().FetchSecret(gomock.Any(), secret.Name).Return(secret, time.Now().Add(-20*time.Minute), nil)
+	cache.EXPECT().UpdateResource(secret.Name, secret).Return(nil)
+
+	manager := NewSecretManager(secretClient, cache, hclog.NewNullLogger())
+	require.NoError(t, manager.Watch(context.Background(), []string{secret.Name}, "node"))
+
+	// secrets are fetched without holding the lock, so they can be unwatched in the
+	// meantime, in which case they aren't pushed back into the cache
+	cache.EXPECT().DeleteResource(secret.Name).Return(nil)
+	secretClient.EXPECT().FetchSecret(gomock.Any(), secret.Name).DoAndReturn(func(ctx context.Context, name string) (*tls.Secret, time.Time, error) {
+		require.NoError(t, manager.Unwatch(ctx, []string{name}, "node"))
+		return secret, time.Now().Add(time.Hour), nil
+	})
+	manager.manage(context.Background(), false)
+
+	require.Empty(t, manager.Resources())
+}
+
+func TestManageChanges(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	secretClient := mocks.NewMockSecretClient(ctrl)
+	cache := mocks.NewMockSecretCache(ctrl)
+	secret := &tls.Secret{
+		Name: "one",
+	}
+	secretClient.EXPECT().FetchSecret(gomock.Any(), secret.Name).Return(secret, time.Now().Add(time.Hour), nil)
+	cache.EXPECT().UpdateResource(secret.Name, secret).Return(nil)
+
+	manager := NewSecretManager(secretClient, cache, hclog.NewNullLogger())
+	err := manager.Watch(context.Background(), []string{secret.Name}, "node")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan SecretChange)
+	done := make(chan struct{})
+	go func() {
+		manager.ManageChanges(ctx, changes)
+		close(done)
+	}()
+
+	// changes to unwatched secrets are ignored
+	changes <- SecretChange{Name: "two", Changed: time.Now()}
+
+	// changes to watched secrets are fetched and pushed into the cache
+	rotated := &tls.Secret{
+		Name: "one",
+	}
+	updated := make(chan struct{})
+	secretClient.EXPECT().FetchSecret(gomock.Any(), secret.Name).Return(rotated, time.Now().Add(2*time.Hour), nil)
+	cache.EXPECT().UpdateResource(secret.Name, rotated).DoAndReturn(func(string, interface{}) error {
+		close(updated)
+		return nil
+	})
+	changes <- SecretChange{Name: secret.Name, Changed: time.Now()}
+	<-updated
+
+	// errors on fetch get swallowed
+	secretClient.EXPECT().FetchSecret(gomock.Any(), secret.Name).Return(nil, time.Time{}, errors.New("fetch error"))
+	changes <- SecretChange{Name: secret.Name}
+
+	cancel()
+	<-done
+
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	require.Equal(t, rotated, manager.registry[secret.Name].Secret)
+}
+
 func TestManageLoop(t *testing.T) {
 	t.Parallel()
 
@@ -241,9 +323,25 @@ type testSecretClient struct {
 	called bool
 }
 
+type testSecretWatcher struct {
+	testSecretClient
+	name      string
+	unwatched []string
+}
+
+func (t *testSecretWatcher) UnwatchSecret(name string) {
+	t.unwatched = append(t.unwatched, name)
+}
+
+func (t *testSecretWatcher) WatchSecrets(ctx context.Context, changes chan<- SecretChange) error {
+	changes <- SecretChange{Name: t.name}
+	<-ctx.Done()
+	return nil
+}
+
 func (t *testSecretClient) FetchSecret(ctx context.Context, name string) (*tls.Secret, time.Time, error) {
 	t.called = true
-	return nil, time.Time{}, nil
+	return &tls.Secret{Name: name}, time.Time{}, nil
 }
 
 func TestMultiSecretClient(t *testing.T) {
@@ -258,3 +356,51 @@ func TestMultiSecretClient(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, secretClient.called)
 }
+
+func TestMultiSecretClientWatchSecrets(t *testing.T) {
+	multi := NewMultiSecretClient()
+	multi.Register("no-watch", &testSecretClient{})
+	multi.Register("one", &testSecretWatcher{name: "one://name"})
+	multi.Register("two", &testSecretWatcher{name: "two://name"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan SecretChange)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- multi.WatchSecrets(ctx, changes)
+	}()
+
+	names := []string{(<-changes).Name, (<-changes).Name}
+	require.ElementsMatch(t, []string{"one://name", "two://name"}, names)
+
+	cancel()
+	require.NoError(t, <-errs)
+}
+
+func TestSecretManagerUnwatchSecret(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cache := mocks.NewMockSecretCache(ctrl)
+
+	watcher := &testSecretWatcher{}
+	multi := NewMultiSecretClient()
+	multi.Register("no-watch", &testSecretClient{})
+	multi.Register("watch", watcher)
+
+	manager := NewSecretManager(multi, cache, hclog.NewNullLogger())
+	cache.EXPECT().UpdateResource(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	require.NoError(t, manager.Watch(context.Background(), []string{"watch://one", "watch://two", "no-watch://three"}, "node"))
+	require.NoError(t, manager.Watch(context.Background(), []string{"watch://one"}, "other"))
+
+	// secrets are only unwatched with the client once no node references them
+	cache.EXPECT().DeleteResource("watch://two").Return(nil)
+	cache.EXPECT().DeleteResource("no-watch://three").Return(nil)
+	require.NoError(t, manager.UnwatchAll(context.Background(), "node"))
+	require.Equal(t, []string{"watch://two"}, watcher.unwatched)
+
+	cache.EXPECT().DeleteResource("watch://one").Return(nil)
+	require.NoError(t, manager.Unwatch(context.Background(), []string{"watch://one"}, "other"))
+	require.Equal(t, []string{"watch://two", "watch://one"}, watcher.unwatched)
+}
@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package file
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/envoy"
+	gwmetrics "github.com/hashicorp/consul-api-gateway/internal/metrics"
+)
+
+var _ envoy.SecretClient = (*SecretClient)(nil)
+var _ envoy.SecretWatcher = (*SecretClient)(nil)
+var _ envoy.SecretUnwatcher = (*SecretClient)(nil)
+
+// directoryRetryInterval is how long we wait before retrying to watch
+// directories that couldn't be watched, i.e. because they don't exist yet
+const directoryRetryInterval = 5 * time.Second
+
+// SecretClient acts as a secret fetcher for PEM encoded certificates on disk
+type SecretClient struct {
+	logger hclog.Logger
+
+	// secrets holds every secret that has been fetched so we know which
+	// directories to watch for rotation
+	secrets map[string]Secret
+	mutex   sync.RWMutex
+	// fetched signals the watcher that the set of watched secrets changed
+	fetched chan struct{}
+}
+
+// NewSecretClient initializes a SecretClient instance
+func NewSecretClient(logger hclog.Logger) *SecretClient {
+	return &SecretClient{
+		logger:  logger,
+		secrets: make(map[string]Secret),
+		fetched: make(chan struct{}, 1),
+	}
+}
+
+// FetchSecret reads a certificate described with the url name of
+// file:///path/to/tls.crt?privateKey=/path/to/tls.key
+func (c *SecretClient) FetchSecret(ctx context.Context, name string) (*tls.Secret, time.Time, error) {
+	secret, err := ParseSecret(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	c.logger.Trace("fetching SDS secret", "name", name)
+	gwmetrics.Registry.IncrCounterWithLabels(gwmetrics.SDSCertificateFetches, 1, []metrics.Label{{
+		Name:  "fetcher",
+		Value: "file",
+	}, {
+		Name:  "name",
+		Value: name,
+	}})
+
+	c.track(name, secret)
+
+	certificateChain, err := os.ReadFile(secret.Path)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("error reading certificate: %w", err)
+	}
+	certificatePrivateKey := certificateChain
+	if secret.PrivateKeyPath != "" {
+		if certificatePrivateKey, err = os.ReadFile(secret.PrivateKeyPath); err != nil {
+			return nil, time.Time{}, fmt.Errorf("error reading private key: %w", err)
+		}
+	}
+
+	block, rest := pem.Decode(certificateChain)
+	for block != nil && block.Type != "CERTIFICATE" {
+		// combined files may start with the private key
+		block, rest = pem.Decode(rest)
+	}
+	if block == nil {
+		return nil, time.Time{}, errors.New("failed to parse certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return &tls.Secret{
+		Type: &tls.Secret_TlsCertificate{
+			TlsCertificate: &tls.TlsCertificate{
+				CertificateChain: &core.DataSource{
+					Specifier: &core.DataSource_InlineBytes{
+						InlineBytes: certificateChain,
+					},
+				},
+				PrivateKey: &core.DataSource{
+					Specifier: &core.DataSource_InlineBytes{
+						InlineBytes: certificatePrivateKey,
+					},
+				},
+			},
+		},
+		Name: name,
+	}, cert.NotAfter, nil
+}
+
+// WatchSecrets watches the directories containing fetched certificates and sends
+// a change whenever a certificate or private key file is written or replaced.
+// Directories are watched rather than the files themselves so that rotations done
+// by atomically renaming or re-linking files, i.e. how Kubernetes updates mounted
+// secrets, are picked up.
+func (c *SecretClient) WatchSecrets(ctx context.Context, changes chan<- envoy.SecretChange) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]struct{})
+	// retry is set while there are directories that failed to be watched
+	var retry <-chan time.Time
+	watch := func() {
+		directories := c.directories()
+		for directory := range watched {
+			if _, ok := directories[directory]; ok {
+				continue
+			}
+			// no remaining secrets are read from the directory
+			if err := watcher.Remove(directory); err != nil {
+				c.logger.Warn("error unwatching certificate directory", "directory", directory, "error", err)
+			}
+			delete(watched, directory)
+		}
+
+		retry = nil
+		for directory := range directories {
+			if _, ok := watched[directory]; ok {
+				continue
+			}
+			if err := watcher.Add(directory); err != nil {
+				c.logger.Error("error watching certificate directory", "directory", directory, "error", err)
+				retry = time.After(directoryRetryInterval)
+				continue
+			}
+			watched[directory] = struct{}{}
+		}
+	}
+	watch()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.fetched:
+			watch()
+		case <-retry:
+			watch()
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+			for _, change := range c.changesFor(event.Name) {
+				c.logger.Trace("certificate file changed", "name", change.Name, "file", event.Name)
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return nil
+				}
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			c.logger.Error("error watching certificate files", "error", err)
+		}
+	}
+}
+
+func (c *SecretClient) track(name string, secret Secret) {
+	c.mutex.Lock()
+	_, ok := c.secrets[name]
+	c.secrets[name] = secret
+	c.mutex.Unlock()
+
+	if !ok {
+		c.notify()
+	}
+}
+
+// UnwatchSecret stops watching a secret that is no longer referenced, its
+// directories are unwatched once no other watched secrets are read from them
+func (c *SecretClient) UnwatchSecret(name string) {
+	c.mutex.Lock()
+	_, ok := c.secrets[name]
+	delete(c.secrets, name)
+	c.mutex.Unlock()
+
+	if ok {
+		c.notify()
+	}
+}
+
+func (c *SecretClient) notify() {
+	select {
+	case c.fetched <- struct{}{}:
+	default:
+	}
+}
+
+func (c *SecretClient) directories() map[string]struct{} {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	directories := make(map[string]struct{})
+	for _, secret := range c.secrets {
+		directories[filepath.Dir(secret.Path)] = struct{}{}
+		if secret.PrivateKeyPath != "" {
+			directories[filepath.Dir(secret.PrivateKeyPath)] = struct{}{}
+		}
+	}
+	return directories
+}
+
+// changesFor returns a change for every fetched secret that reads from the given file.
+// Kubernetes rotates mounted secrets by swapping the hidden "..data" symlink in the
+// mount directory, so changes to hidden ".." entries affect every file in a directory.
+func (c *SecretClient) changesFor(file string) []envoy.SecretChange {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	directory := filepath.Dir(file)
+	atomicWrite := strings.HasPrefix(filepath.Base(file), "..")
+
+	changes := []envoy.SecretChange{}
+	for name, secret := range c.secrets {
+		for _, path := range []string{secret.Path, secret.privateKeyPath()} {
+			if path == file || (atomicWrite && filepath.Dir(path) == directory) {
+				changes = append(changes, envoy.SecretChange{
+					Name:    name,
+					Changed: lastModified(path),
+				})
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// lastModified returns the modification time of a file, falling
+// back to the current time if the file can't be read.
+func lastModified(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Now()
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/consul-api-gateway/internal/envoy"
+	gwTesting "github.com/hashicorp/consul-api-gateway/internal/testing"
+)
+
+func TestSecretClient_FetchSecret(t *testing.T) {
+	t.Parallel()
+
+	_, server, _ := gwTesting.DefaultCertificates()
+	directory := t.TempDir()
+	certPath := filepath.Join(directory, "tls.crt")
+	keyPath := filepath.Join(directory, "tls.key")
+	combinedPath := filepath.Join(directory, "tls.pem")
+	require.NoError(t, os.WriteFile(certPath, server.CertBytes, 0600))
+	require.NoError(t, os.WriteFile(keyPath, server.PrivateKeyBytes, 0600))
+	require.NoError(t, os.WriteFile(combinedPath, append(append([]byte{}, server.PrivateKeyBytes...), server.CertBytes...), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "invalid.crt"), []byte("invalid"), 0600))
+
+	client := NewSecretClient(hclog.NewNullLogger())
+	ctx := context.Background()
+
+	name := NewSecret(certPath, keyPath).String()
+	secret, expires, err := client.FetchSecret(ctx, name)
+	require.NoError(t, err)
+	require.Equal(t, name, secret.Name)
+	require.Equal(t, server.CertBytes, secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+	require.Equal(t, server.PrivateKeyBytes, secret.GetTlsCertificate().PrivateKey.GetInlineBytes())
+	require.WithinDuration(t, server.Cert.NotAfter, expires, time.Second)
+
+	// the private key can be read from the certificate file
+	secret, expires, err = client.FetchSecret(ctx, NewSecret(combinedPath, "").String())
+	require.NoError(t, err)
+	require.Equal(t, secret.GetTlsCertificate().CertificateChain.GetInlineBytes(), secret.GetTlsCertificate().PrivateKey.GetInlineBytes())
+	require.WithinDuration(t, server.Cert.NotAfter, expires, time.Second)
+
+	_, _, err = client.FetchSecret(ctx, NewSecret(filepath.Join(directory, "missing.crt"), "").String())
+	require.Error(t, err)
+
+	_, _, err = client.FetchSecret(ctx, NewSecret(filepath.Join(directory, "invalid.crt"), keyPath).String())
+	require.EqualError(t, err, "failed to parse certificate PEM")
+
+	_, _, err = client.FetchSecret(ctx, "k8s://namespace/secret")
+	require.Equal(t, ErrInvalidSecret, err)
+}
+
+func TestSecretClient_WatchSecrets(t *testing.T) {
+	t.Parallel()
+
+	_, server, client := gwTesting.DefaultCertificates()
+	directory := t.TempDir()
+	certPath := filepath.Join(directory, "tls.crt")
+	keyPath := filepath.Join(directory, "tls.key")
+	require.NoError(t, os.WriteFile(certPath, server.CertBytes, 0600))
+	require.NoError(t, os.WriteFile(keyPath, server.PrivateKeyBytes, 0600))
+
+	// mimic how Kubernetes mounts secrets, with the files linked through a "..data" directory
+	mounted := t.TempDir()
+	writeMount := func(info *gwTesting.CertificateInfo, version string) {
+		versioned := filepath.Join(mounted, version)
+		require.NoError(t, os.Mkdir(versioned, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(versioned, "tls.crt"), info.CertBytes, 0600))
+		require.NoError(t, os.WriteFile(filepath.Join(versioned, "tls.key"), info.PrivateKeyBytes, 0600))
+		require.NoError(t, os.Symlink(version, filepath.Join(mounted, "..data_tmp")))
+		require.NoError(t, os.Rename(filepath.Join(mounted, "..data_tmp"), filepath.Join(mounted, "..data")))
+	}
+	writeMount(server, "..v1")
+	require.NoError(t, os.Symlink(filepath.Join("..data", "tls.crt"), filepath.Join(mounted, "tls.crt")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "tls.key"), filepath.Join(mounted, "tls.key")))
+
+	secretClient := NewSecretClient(hclog.NewNullLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan envoy.SecretChange)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- secretClient.WatchSecrets(ctx, changes)
+	}()
+
+	name := NewSecret(certPath, keyPath).String()
+	_, _, err := secretClient.FetchSecret(ctx, name)
+	require.NoError(t, err)
+	mountedName := NewSecret(filepath.Join(mounted, "tls.crt"), filepath.Join(mounted, "tls.key")).String()
+	_, _, err = secretClient.FetchSecret(ctx, mountedName)
+	require.NoError(t, err)
+
+	expectChange := func(name string, rotate func()) {
+		t.Helper()
+
+		// the watcher picks up newly fetched secrets asynchronously, so
+		// keep rotating until the change comes through
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		timeout := time.After(10 * time.Second)
+		rotate()
+		for {
+			select {
+			case change := <-changes:
+				if change.Name == name {
+					require.False(t, change.Changed.IsZero())
+					return
+				}
+			case <-ticker.C:
+				rotate()
+			case <-timeout:
+				t.Fatalf("timed out waiting for a change to %s", name)
+			}
+		}
+	}
+
+	expectChange(name, func() {
+		require.NoError(t, os.WriteFile(certPath, client.CertBytes, 0600))
+	})
+	secret, _, err := secretClient.FetchSecret(ctx, name)
+	require.NoError(t, err)
+	require.Equal(t, client.CertBytes, secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+
+	version := 1
+	expectChange(mountedName, func() {
+		version++
+		writeMount(client, fmt.Sprintf("..v%d", version))
+	})
+	secret, _, err = secretClient.FetchSecret(ctx, mountedName)
+	require.NoError(t, err)
+	require.Equal(t, client.CertBytes, secret.GetTlsCertificate().CertificateChain.GetInlineBytes())
+
+	// directories that don't exist yet are watched once they're created
+	pending := filepath.Join(t.TempDir(), "pending")
+	pendingName := NewSecret(filepath.Join(pending, "tls.crt"), "").String()
+	_, _, err = secretClient.FetchSecret(ctx, pendingName)
+	require.Error(t, err)
+	// give the watcher a chance to fail watching the directory so it's retried
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, os.Mkdir(pending, 0700))
+	expectChange(pendingName, func() {
+		require.NoError(t, os.WriteFile(filepath.Join(pending, "tls.crt"), append(append([]byte{}, client.PrivateKeyBytes...), client.CertBytes...), 0600))
+	})
+
+	// unwatched secrets no longer send changes
+	secretClient.UnwatchSecret(name)
+	require.NoError(t, os.WriteFile(certPath, server.CertBytes, 0600))
+	select {
+	case change := <-changes:
+		require.NotEqual(t, name, change.Name)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-watchErr)
+}
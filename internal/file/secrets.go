@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package file
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+)
+
+var (
+	ErrInvalidSecret = errors.New("invalid file secret")
+)
+
+const (
+	SecretScheme = "file"
+
+	queryParamPrivateKey = "privateKey"
+)
+
+// Secret describes a PEM encoded certificate chain and private key on disk. If no
+// separate private key file is given, the key is read from the certificate file.
+type Secret struct {
+	Path           string
+	PrivateKeyPath string
+}
+
+func NewSecret(path, privateKeyPath string) Secret {
+	return Secret{
+		Path:           path,
+		PrivateKeyPath: privateKeyPath,
+	}
+}
+
+func ParseSecret(ref string) (Secret, error) {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	if parsed.Scheme != SecretScheme {
+		return Secret{}, ErrInvalidSecret
+	}
+
+	if !filepath.IsAbs(parsed.Path) {
+		return Secret{}, ErrInvalidSecret
+	}
+
+	privateKeyPath := parsed.Query().Get(queryParamPrivateKey)
+	if privateKeyPath != "" && !filepath.IsAbs(privateKeyPath) {
+		return Secret{}, ErrInvalidSecret
+	}
+
+	return NewSecret(parsed.Path, privateKeyPath), nil
+}
+
+func (s Secret) String() string {
+	v := url.Values{}
+
+	if s.PrivateKeyPath != "" {
+		v.Add(queryParamPrivateKey, s.PrivateKeyPath)
+	}
+
+	return (&url.URL{
+		Scheme:   SecretScheme,
+		Path:     s.Path,
+		RawQuery: v.Encode(),
+	}).String()
+}
+
+// privateKeyPath returns the file that the private key is read from
+func (s Secret) privateKeyPath() string {
+	if s.PrivateKeyPath != "" {
+		return s.PrivateKeyPath
+	}
+	return s.Path
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecret(t *testing.T) {
+	// Test empty name
+	_, err := ParseSecret("")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+
+	// Test invalid scheme
+	_, err = ParseSecret("invalid:///etc/tls.crt")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+
+	// Test relative paths
+	_, err = ParseSecret("file://tls.crt")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+	_, err = ParseSecret("file:///etc/tls.crt?privateKey=tls.key")
+	assert.EqualError(t, ErrInvalidSecret, err.Error())
+
+	// Test combined file
+	secret, err := ParseSecret("file:///etc/tls.pem")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/tls.pem", secret.Path)
+	assert.Empty(t, secret.PrivateKeyPath)
+
+	// Test separate private key
+	secret, err = ParseSecret("file:///etc/tls.crt?privateKey=/etc/tls.key")
+	require.NoError(t, err)
+	assert.Equal(t, "/etc/tls.crt", secret.Path)
+	assert.Equal(t, "/etc/tls.key", secret.PrivateKeyPath)
+
+	// Test round trip
+	secret2, err := ParseSecret(secret.String())
+	require.NoError(t, err)
+	assert.Equal(t, secret, secret2)
+}
+
+func TestSecret_String(t *testing.T) {
+	secret := NewSecret("/etc/tls.pem", "")
+	assert.Equal(t, "file:///etc/tls.pem", secret.String())
+
+	secret.PrivateKeyPath = "/etc/tls.key"
+	assert.Equal(t, "file:///etc/tls.pem?privateKey=%2Fetc%2Ftls.key", secret.String())
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hashicorp/consul-api-gateway/internal/acme"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+)
+
+var _ acme.Storage = (*ACMEStorage)(nil)
+
+const (
+	acmeAccountKey = "key.pem"
+)
+
+// ACMEStorage stores the ACME account key and issued certificates in kubernetes secrets
+type ACMEStorage struct {
+	client  client.Client
+	account client.ObjectKey
+}
+
+// NewACMEStorage initializes an ACMEStorage instance that keeps the account key
+// in the secret with the given namespace and name
+func NewACMEStorage(config *rest.Config, accountNamespace, accountName string) (*ACMEStorage, error) {
+	apiClient, err := client.New(config, client.Options{
+		Scheme: scheme,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ACMEStorage{
+		client: apiClient,
+		account: client.ObjectKey{
+			Namespace: accountNamespace,
+			Name:      accountName,
+		},
+	}, nil
+}
+
+func (s *ACMEStorage) LoadAccountKey(ctx context.Context) ([]byte, error) {
+	secret, err := s.get(ctx, s.account)
+	if err != nil || secret == nil {
+		return nil, err
+	}
+	return secret.Data[acmeAccountKey], nil
+}
+
+func (s *ACMEStorage) StoreAccountKey(ctx context.Context, key []byte) error {
+	return s.store(ctx, s.account, corev1.SecretTypeOpaque, map[string][]byte{
+		acmeAccountKey: key,
+	})
+}
+
+func (s *ACMEStorage) LoadCertificate(ctx context.Context, namespace, name string) ([]byte, []byte, error) {
+	secret, err := s.get(ctx, client.ObjectKey{Namespace: namespace, Name: name})
+	if err != nil || secret == nil {
+		return nil, nil, err
+	}
+	return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], nil
+}
+
+func (s *ACMEStorage) StoreCertificate(ctx context.Context, namespace, name string, certificate, privateKey []byte) error {
+	return s.store(ctx, client.ObjectKey{Namespace: namespace, Name: name}, corev1.SecretTypeTLS, map[string][]byte{
+		corev1.TLSCertKey:       certificate,
+		corev1.TLSPrivateKeyKey: privateKey,
+	})
+}
+
+func (s *ACMEStorage) get(ctx context.Context, key client.ObjectKey) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := s.client.Get(ctx, key, secret); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+func (s *ACMEStorage) store(ctx context.Context, key client.ObjectKey, secretType corev1.SecretType, data map[string][]byte) error {
+	secret, err := s.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if secret == nil {
+		return s.client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: key.Namespace,
+				Name:      key.Name,
+				Labels: map[string]string{
+					utils.ACMEManagedByLabel: utils.ACMEManagedByValue,
+				},
+			},
+			Type: secretType,
+			Data: data,
+		})
+	}
+	// only ever overwrite the secrets we created, otherwise a listener
+	// could be used to clobber any secret that it can reference
+	if !utils.IsACMEManagedSecret(secret, secretType) {
+		return fmt.Errorf("secret %s is not a %s secret managed by ACME", key, secretType)
+	}
+	secret.Data = data
+	return s.client.Update(ctx, secret)
+}
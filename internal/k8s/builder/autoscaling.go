@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package builder
+
+import (
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	"github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
+)
+
+type GatewayHorizontalPodAutoscalerBuilder struct {
+	gateway  *gwv1beta1.Gateway
+	gwConfig *v1alpha1.GatewayClassConfig
+}
+
+func NewGatewayHorizontalPodAutoscaler(gw *gwv1beta1.Gateway) *GatewayHorizontalPodAutoscalerBuilder {
+	return &GatewayHorizontalPodAutoscalerBuilder{gateway: gw}
+}
+
+func (b *GatewayHorizontalPodAutoscalerBuilder) WithClassConfig(cfg v1alpha1.GatewayClassConfig) *GatewayHorizontalPodAutoscalerBuilder {
+	b.gwConfig = &cfg
+	return b
+}
+
+// Validate checks that the autoscaler's range of instances is valid and that
+// gateway pods request CPU when scaling on CPU utilization, which is measured
+// as a percentage of the requested CPU
+func (b *GatewayHorizontalPodAutoscalerBuilder) Validate() error {
+	deployment := b.gwConfig.Spec.DeploymentSpec
+	if deployment.Autoscaling == nil {
+		return nil
+	}
+
+	minReplicas, maxReplicas := b.replicas()
+	if minReplicas > maxReplicas {
+		return fmt.Errorf("autoscaling minInstances %d cannot be greater than maxInstances %d", minReplicas, maxReplicas)
+	}
+	if b.targetCPU() != nil && !b.requestsCPU() {
+		return fmt.Errorf("autoscaling on CPU utilization requires the pod template to request CPU")
+	}
+	return nil
+}
+
+// Build returns a HorizontalPodAutoscaler that scales the gateway's deployment
+// between its minimum and maximum number of instances, or nil if autoscaling
+// isn't configured.
+func (b *GatewayHorizontalPodAutoscalerBuilder) Build() *autoscalingv2.HorizontalPodAutoscaler {
+	deployment := b.gwConfig.Spec.DeploymentSpec
+	if deployment.Autoscaling == nil {
+		return nil
+	}
+
+	minReplicas, maxReplicas := b.replicas()
+
+	metrics := []autoscalingv2.MetricSpec{}
+	if targetCPU := b.targetCPU(); targetCPU != nil {
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: "cpu",
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: targetCPU,
+				},
+			},
+		})
+	}
+	metrics = append(metrics, deployment.Autoscaling.Metrics...)
+
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.gateway.Name,
+			Namespace: b.gateway.Namespace,
+			Labels:    utils.LabelsForGateway(b.gateway),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       b.gateway.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+func (b *GatewayHorizontalPodAutoscalerBuilder) replicas() (int32, int32) {
+	deployment := b.gwConfig.Spec.DeploymentSpec
+	minReplicas := defaultInstances
+	if deployment.MinInstances != nil {
+		minReplicas = *deployment.MinInstances
+	}
+	maxReplicas := defaultMaxInstances
+	if deployment.MaxInstances != nil {
+		maxReplicas = *deployment.MaxInstances
+	}
+	return minReplicas, maxReplicas
+}
+
+// targetCPU returns the CPU utilization to scale on, which defaults to
+// defaultTargetCPUUtilizationPercentage if no other metrics are configured
+func (b *GatewayHorizontalPodAutoscalerBuilder) targetCPU() *int32 {
+	autoscaling := b.gwConfig.Spec.DeploymentSpec.Autoscaling
+	if autoscaling.TargetCPUUtilizationPercentage == nil && len(autoscaling.Metrics) == 0 {
+		return pointer.Int32(defaultTargetCPUUtilizationPercentage)
+	}
+	return autoscaling.TargetCPUUtilizationPercentage
+}
+
+func (b *GatewayHorizontalPodAutoscalerBuilder) requestsCPU() bool {
+	template := b.gwConfig.Spec.PodTemplate
+	if template == nil || template.Resources == nil {
+		return false
+	}
+	_, ok := template.Resources.Requests[corev1.ResourceCPU]
+	return ok
+}
+
+type GatewayPodDisruptionBudgetBuilder struct {
+	gateway  *gwv1beta1.Gateway
+	gwConfig *v1alpha1.GatewayClassConfig
+}
+
+func NewGatewayPodDisruptionBudget(gw *gwv1beta1.Gateway) *GatewayPodDisruptionBudgetBuilder {
+	return &GatewayPodDisruptionBudgetBuilder{gateway: gw}
+}
+
+func (b *GatewayPodDisruptionBudgetBuilder) WithClassConfig(cfg v1alpha1.GatewayClassConfig) *GatewayPodDisruptionBudgetBuilder {
+	b.gwConfig = &cfg
+	return b
+}
+
+// Build returns a PodDisruptionBudget for the gateway's pods, or nil if
+// a disruption budget isn't configured.
+func (b *GatewayPodDisruptionBudgetBuilder) Build() *policyv1.PodDisruptionBudget {
+	budget := b.gwConfig.Spec.DeploymentSpec.DisruptionBudget
+	if budget == nil {
+		return nil
+	}
+
+	labels := utils.LabelsForGateway(b.gateway)
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: labels,
+		},
+	}
+	// a PodDisruptionBudget may only set one of these
+	switch {
+	case budget.MinAvailable != nil:
+		spec.MinAvailable = budget.MinAvailable
+	case budget.MaxUnavailable != nil:
+		spec.MaxUnavailable = budget.MaxUnavailable
+	default:
+		maxUnavailable := intstr.FromInt(1)
+		spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.gateway.Name,
+			Namespace: b.gateway.Namespace,
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+}
@@ -11,14 +11,17 @@ import (
 
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
 	"github.com/hashicorp/consul-api-gateway/internal/version"
+	"github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
 var (
 	defaultImage              string
 	defaultServiceAnnotations = []string{
-		"external-dns.alpha.kubernetes.io/hostname",
+		utils.ExternalDNSHostnameAnnotation,
 	}
 )
 
@@ -37,10 +40,18 @@ const (
 	defaultConsulHTTPPort       = "8500"
 	defaultConsulXDSPort        = "8502"
 	defaultInstances      int32 = 1
+	defaultMaxInstances   int32 = 8
+
+	defaultTargetCPUUtilizationPercentage int32 = 80
 
 	consulCALocalPath = "/consul/tls"
 	consulCAFilename  = "ca.pem"
 
+	projectedTokenVolume      = "token"
+	defaultProjectedTokenFile = "/var/run/secrets/consul-api-gateway/token"
+	// where Kubernetes mounts the pod's default service account token
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+
 	k8sHostnameTopologyKey = "kubernetes.io/hostname"
 )
 
@@ -55,6 +66,21 @@ type Builder interface {
 	Validate() error
 }
 
+// ValidateConfig validates everything in a GatewayClassConfig that
+// the builders apply to the resources they build for a gateway
+func ValidateConfig(gw *gwv1beta1.Gateway, cfg v1alpha1.GatewayClassConfig) error {
+	for _, builder := range []Builder{
+		NewGatewayDeployment(gw).WithClassConfig(cfg),
+		NewGatewayService(gw).WithClassConfig(cfg),
+		NewGatewayHorizontalPodAutoscaler(gw).WithClassConfig(cfg),
+	} {
+		if err := builder.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type DeploymentBuilder interface {
 	Builder
 	Build(*int32) *v1.Deployment
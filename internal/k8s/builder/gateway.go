@@ -5,7 +5,13 @@ package builder
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -40,13 +46,25 @@ func (b *GatewayServiceBuilder) Build() *corev1.Service {
 	if b.gwConfig.Spec.ServiceType == nil {
 		return nil
 	}
+	serviceType := *b.gwConfig.Spec.ServiceType
+	config := b.gwConfig.Spec.ServiceSpec
+	external := serviceType == corev1.ServiceTypeNodePort || serviceType == corev1.ServiceTypeLoadBalancer
+
+	nodePorts := make(map[int32]int32)
+	for _, mapping := range config.NodePorts {
+		nodePorts[mapping.Port] = mapping.NodePort
+	}
 	ports := []corev1.ServicePort{}
 	for _, listener := range b.gateway.Spec.Listeners {
-		ports = append(ports, corev1.ServicePort{
+		port := corev1.ServicePort{
 			Name:     string(listener.Name),
 			Protocol: "TCP",
 			Port:     int32(listener.Port),
-		})
+		}
+		if external {
+			port.NodePort = nodePorts[port.Port]
+		}
+		ports = append(ports, port)
 	}
 	labels := utils.LabelsForGateway(b.gateway)
 	allowedAnnotations := b.gwConfig.Spec.CopyAnnotations.Service
@@ -54,19 +72,156 @@ func (b *GatewayServiceBuilder) Build() *corev1.Service {
 		allowedAnnotations = defaultServiceAnnotations
 	}
 
+	spec := corev1.ServiceSpec{
+		Selector:        labels,
+		Type:            serviceType,
+		Ports:           ports,
+		IPFamilies:      config.IPFamilies,
+		IPFamilyPolicy:  config.IPFamilyPolicy,
+		SessionAffinity: corev1.ServiceAffinityNone,
+	}
+	// set anything Kubernetes would otherwise default so that
+	// removing a setting from the config reverts the service
+	if external {
+		spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+		if config.ExternalTrafficPolicy != "" {
+			spec.ExternalTrafficPolicy = config.ExternalTrafficPolicy
+		}
+	}
+	if serviceType == corev1.ServiceTypeLoadBalancer {
+		spec.LoadBalancerSourceRanges = config.LoadBalancerSourceRanges
+		spec.LoadBalancerClass = config.LoadBalancerClass
+	}
+	annotations := filterAnnotations(b.gateway.Annotations, allowedAnnotations)
+	b.requestAddresses(&spec, annotations)
+
+	if config.SessionAffinity == corev1.ServiceAffinityClientIP {
+		timeout := corev1.DefaultClientIPServiceAffinitySeconds
+		if config.SessionAffinityTimeoutSeconds != nil {
+			timeout = *config.SessionAffinityTimeoutSeconds
+		}
+		spec.SessionAffinity = corev1.ServiceAffinityClientIP
+		spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+			ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeout},
+		}
+	}
+
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        b.gateway.Name,
 			Namespace:   b.gateway.Namespace,
 			Labels:      labels,
-			Annotations: filterAnnotations(b.gateway.Annotations, allowedAnnotations),
-		},
-		Spec: corev1.ServiceSpec{
-			Selector: labels,
-			Type:     *b.gwConfig.Spec.ServiceType,
-			Ports:    ports,
+			Annotations: annotations,
 		},
+		Spec: spec,
+	}
+}
+
+// requestAddresses maps the addresses requested in the gateway's spec.addresses that
+// the GatewayClassConfig allows onto its service. IP addresses are requested from the load balancer of LoadBalancer
+// services and set as the external IPs of other services, hostnames are handed to
+// external-dns and so are only supported for LoadBalancer services.
+func (b *GatewayServiceBuilder) requestAddresses(spec *corev1.ServiceSpec, annotations map[string]string) {
+	ips, hostnames := b.allowedAddresses()
+	if spec.Type != corev1.ServiceTypeLoadBalancer {
+		spec.ExternalIPs = ips
+		return
+	}
+
+	if len(hostnames) > 0 {
+		annotations[utils.ExternalDNSHostnameAnnotation] = strings.Join(hostnames, ",")
+	}
+	if len(ips) == 0 {
+		return
+	}
+	if annotation := b.gwConfig.Spec.ServiceSpec.LoadBalancerIPAnnotation; annotation != "" {
+		annotations[annotation] = strings.Join(ips, ",")
+		return
+	}
+	// a service can only request a single load balancer IP this way,
+	// any others are reported as unassigned on the gateway
+	spec.LoadBalancerIP = ips[0]
+}
+
+// allowedAddresses returns the requested addresses allowed by the GatewayClassConfig,
+// disallowed addresses are reported as unassigned on the gateway
+func (b *GatewayServiceBuilder) allowedAddresses() (ips []string, hostnames []string) {
+	addresses := b.gwConfig.Spec.AddressesSpec
+	if addresses == nil {
+		return nil, nil
+	}
+	ips, hostnames, _ = utils.AllowedAddresses(b.gateway, addresses.AllowedIPRanges, addresses.AllowHostnames)
+	return ips, hostnames
+}
+
+// Validate checks that the service configuration of the GatewayClassConfig
+// only uses settings that apply to its service type.
+func (b *GatewayServiceBuilder) Validate() error {
+	if addresses := b.gwConfig.Spec.AddressesSpec; addresses != nil {
+		for _, cidr := range addresses.AllowedIPRanges {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("addresses allowedIPRanges contains invalid CIDR %q", cidr)
+			}
+		}
+	}
+	if b.gwConfig.Spec.ServiceType == nil {
+		return nil
+	}
+	serviceType := *b.gwConfig.Spec.ServiceType
+	config := b.gwConfig.Spec.ServiceSpec
+	external := serviceType == corev1.ServiceTypeNodePort || serviceType == corev1.ServiceTypeLoadBalancer
+
+	if !external {
+		if config.ExternalTrafficPolicy != "" {
+			return fmt.Errorf("service externalTrafficPolicy requires a NodePort or LoadBalancer service")
+		}
+		if len(config.NodePorts) > 0 {
+			return fmt.Errorf("service nodePorts require a NodePort or LoadBalancer service")
+		}
 	}
+	if serviceType != corev1.ServiceTypeLoadBalancer {
+		if len(config.LoadBalancerSourceRanges) > 0 || config.LoadBalancerClass != nil || config.LoadBalancerIPAnnotation != "" {
+			return fmt.Errorf("service loadBalancerSourceRanges, loadBalancerClass and loadBalancerIPAnnotation require a LoadBalancer service")
+		}
+	}
+	for _, cidr := range config.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("service loadBalancerSourceRanges contains invalid CIDR %q", cidr)
+		}
+	}
+
+	if len(config.IPFamilies) > 2 {
+		return fmt.Errorf("service ipFamilies may contain at most two families")
+	}
+	families := make(map[corev1.IPFamily]struct{})
+	for _, family := range config.IPFamilies {
+		if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+			return fmt.Errorf("service ipFamilies contains unknown family %q", family)
+		}
+		if _, ok := families[family]; ok {
+			return fmt.Errorf("service ipFamilies contains duplicate family %q", family)
+		}
+		families[family] = struct{}{}
+	}
+
+	ports := make(map[int32]struct{})
+	nodePorts := make(map[int32]struct{})
+	for _, mapping := range config.NodePorts {
+		if _, ok := ports[mapping.Port]; ok {
+			return fmt.Errorf("service nodePorts maps port %d more than once", mapping.Port)
+		}
+		if _, ok := nodePorts[mapping.NodePort]; ok {
+			return fmt.Errorf("service nodePorts uses node port %d more than once", mapping.NodePort)
+		}
+		ports[mapping.Port] = struct{}{}
+		nodePorts[mapping.NodePort] = struct{}{}
+	}
+
+	if config.SessionAffinityTimeoutSeconds != nil && config.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return fmt.Errorf("service sessionAffinityTimeoutSeconds requires ClientIP session affinity")
+	}
+
+	return nil
 }
 
 func filterAnnotations(annotations map[string]string, allowed []string) map[string]string {
@@ -121,27 +276,151 @@ func (b *GatewayDeploymentBuilder) WithPrimaryConsulDatacenter(datacenter string
 
 func (b *GatewayDeploymentBuilder) Build(currentReplicas *int32) *v1.Deployment {
 	labels := utils.LabelsForGateway(b.gateway)
+	template := b.podTemplate()
 
 	return &v1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      b.gateway.Name,
 			Namespace: b.gateway.Namespace,
 			Labels:    labels,
+			Annotations: map[string]string{
+				v1alpha1.ConfigHashAnnotation: configHash(template),
+			},
 		},
 		Spec: v1.DeploymentSpec{
 			Replicas: b.instances(currentReplicas),
+			Strategy: b.strategy(),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
-					Annotations: map[string]string{
-						"consul.hashicorp.com/connect-inject": "false",
-					},
+			Template: template,
+		},
+	}
+}
+
+// BuildDaemonSet returns a DaemonSet that runs an instance of the gateway on
+// the host network of every node matching the GatewayClassConfig's node selector
+func (b *GatewayDeploymentBuilder) BuildDaemonSet() *v1.DaemonSet {
+	labels := utils.LabelsForGateway(b.gateway)
+	// instances bind to the node's ports, so an old instance has
+	// to be stopped before its replacement can be started
+	maxUnavailable := intstr.FromInt(1)
+	template := b.podTemplate()
+
+	return &v1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      b.gateway.Name,
+			Namespace: b.gateway.Namespace,
+			Labels:    labels,
+			Annotations: map[string]string{
+				v1alpha1.ConfigHashAnnotation: configHash(template),
+			},
+		},
+		Spec: v1.DaemonSetSpec{
+			UpdateStrategy: v1.DaemonSetUpdateStrategy{
+				Type: v1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &v1.RollingUpdateDaemonSet{
+					MaxUnavailable: &maxUnavailable,
 				},
-				Spec: b.podSpec(),
 			},
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: template,
+		},
+	}
+}
+
+func (b *GatewayDeploymentBuilder) podTemplate() corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      b.podLabels(),
+			Annotations: b.podAnnotations(),
+		},
+		Spec: b.podSpec(),
+	}
+}
+
+// Validate checks that the GatewayClassConfig can be used to
+// build a deployment or daemonset for the gateway.
+func (b *GatewayDeploymentBuilder) Validate() error {
+	if b.daemonSet() {
+		if b.gwConfig.Spec.ServiceType != nil {
+			return fmt.Errorf("DaemonSet mode gateways use the host network and cannot have a serviceType")
+		}
+		if b.gwConfig.Spec.DeploymentSpec.Autoscaling != nil {
+			return fmt.Errorf("DaemonSet mode gateways run on every selected node and cannot be autoscaled")
+		}
+	}
+	if err := b.validateBearerTokenFile(); err != nil {
+		return err
+	}
+	return b.validatePodTemplate()
+}
+
+// validateBearerTokenFile checks that the token gateways log in to Consul
+// with can be read from, or projected to, its configured path
+func (b *GatewayDeploymentBuilder) validateBearerTokenFile() error {
+	file := b.bearerTokenFile()
+	if file == "" {
+		return nil
+	}
+	if !filepath.IsAbs(file) || strings.HasSuffix(file, "/") {
+		return fmt.Errorf("bearer token file %q must be an absolute file path", file)
+	}
+	if b.gwConfig.Spec.ConsulSpec.AuthSpec.ProjectedToken == nil {
+		return nil
+	}
+	switch dir := filepath.Dir(filepath.Clean(file)); dir {
+	case "/", "/bootstrap", "/certs", consulCALocalPath, serviceAccountTokenPath:
+		return fmt.Errorf("projected token cannot be mounted at reserved path %q", dir)
+	}
+	return nil
+}
+
+// bearerTokenFile returns the path of the token gateways log in to Consul
+// with, or an empty string when they use the pod's default service account token
+func (b *GatewayDeploymentBuilder) bearerTokenFile() string {
+	auth := b.gwConfig.Spec.ConsulSpec.AuthSpec
+	if auth.BearerTokenFile != "" {
+		return auth.BearerTokenFile
+	}
+	if auth.ProjectedToken != nil {
+		return defaultProjectedTokenFile
+	}
+	return ""
+}
+
+// daemonSet returns whether gateways are run as a DaemonSet rather than a Deployment
+func (b *GatewayDeploymentBuilder) daemonSet() bool {
+	return b.gwConfig.Spec.DeploymentSpec.Mode == v1alpha1.DeploymentModeDaemonSet
+}
+
+// configHash returns a hash of the pod template rendered from the GatewayClassConfig,
+// used to detect when the deployment needs to be rolled. Config changes that don't
+// affect the gateway's pods, such as to its service or autoscaling, don't change it
+func configHash(template corev1.PodTemplateSpec) string {
+	hash := fnv.New32a()
+	// the template always marshals, so we can ignore the error
+	data, _ := json.Marshal(template)
+	_, _ = hash.Write(data)
+	return strconv.FormatUint(uint64(hash.Sum32()), 16)
+}
+
+// strategy returns the rollout strategy for the deployment, new instances are brought
+// up before old ones are taken down so that a gateway never loses capacity, unless
+// host ports are used, in which case instances on the same node would conflict and
+// have to be replaced one at a time instead
+func (b *GatewayDeploymentBuilder) strategy() v1.DeploymentStrategy {
+	maxUnavailable, maxSurge := intstr.FromInt(0), intstr.FromInt(1)
+	if b.gwConfig.Spec.UseHostPorts {
+		maxUnavailable, maxSurge = intstr.FromInt(1), intstr.FromInt(0)
+	}
+	return v1.DeploymentStrategy{
+		Type: v1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &v1.RollingUpdateDeployment{
+			MaxUnavailable: &maxUnavailable,
+			MaxSurge:       &maxSurge,
 		},
 	}
 }
@@ -187,7 +466,7 @@ func (b *GatewayDeploymentBuilder) podSpec() corev1.PodSpec {
 
 	labels := utils.LabelsForGateway(b.gateway)
 
-	return corev1.PodSpec{
+	spec := corev1.PodSpec{
 		Affinity: &corev1.Affinity{
 			PodAntiAffinity: &corev1.PodAntiAffinity{
 				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
@@ -212,7 +491,7 @@ func (b *GatewayDeploymentBuilder) podSpec() corev1.PodSpec {
 
 		InitContainers: []corev1.Container{{
 			Image:        orDefault(b.gwConfig.Spec.ImageSpec.ConsulAPIGateway, defaultImage),
-			Name:         "consul-api-gateway-init",
+			Name:         gatewayInitContainerName,
 			VolumeMounts: mounts,
 			Command: []string{
 				"cp", "/bin/discover", "/bin/consul-api-gateway", "/bootstrap/",
@@ -220,7 +499,7 @@ func (b *GatewayDeploymentBuilder) podSpec() corev1.PodSpec {
 		}},
 		Containers: []corev1.Container{{
 			Image:        orDefault(b.gwConfig.Spec.ImageSpec.Envoy, defaultEnvoyImage),
-			Name:         "consul-api-gateway",
+			Name:         gatewayContainerName,
 			VolumeMounts: mounts,
 			Ports:        b.containerPorts(),
 			Env:          b.envVars(),
@@ -235,8 +514,24 @@ func (b *GatewayDeploymentBuilder) podSpec() corev1.PodSpec {
 				},
 			},
 		}},
-		Volumes: volumes,
+		Volumes:   volumes,
+		DNSPolicy: b.gwConfig.Spec.DeploymentSpec.DNSPolicy,
 	}
+	if b.gwConfig.Spec.ConsulSpec.AuthSpec.ProjectedToken != nil {
+		// gateways don't talk to Kubernetes, so there's no need to
+		// mount the long-lived default token alongside the projected one
+		spec.AutomountServiceAccountToken = pointer.Bool(false)
+	}
+	if b.daemonSet() {
+		spec.HostNetwork = true
+		if spec.DNSPolicy == "" {
+			spec.DNSPolicy = corev1.DNSClusterFirstWithHostNet
+		}
+	}
+	b.applySecurityDefaults(&spec)
+	b.applyPodTemplate(&spec)
+
+	return spec
 }
 
 // execArgs renders a template containing all necessary args for the container
@@ -245,6 +540,7 @@ func (b *GatewayDeploymentBuilder) podSpec() corev1.PodSpec {
 func (b *GatewayDeploymentBuilder) execArgs() []string {
 	data := gwContainerCommandData{
 		ACLAuthMethod:     b.gwConfig.Spec.ConsulSpec.AuthSpec.Method,
+		BearerTokenFile:   b.bearerTokenFile(),
 		ConsulHTTPAddr:    orDefault(b.gwConfig.Spec.ConsulSpec.Address, defaultConsulAddress),
 		ConsulHTTPPort:    orDefaultIntString(b.gwConfig.Spec.ConsulSpec.PortSpec.HTTP, defaultConsulHTTPPort),
 		ConsulGRPCPort:    orDefaultIntString(b.gwConfig.Spec.ConsulSpec.PortSpec.GRPC, defaultConsulXDSPort),
@@ -369,6 +665,28 @@ func (b *GatewayDeploymentBuilder) volumes() ([]corev1.Volume, []corev1.VolumeMo
 			MountPath: consulCALocalPath,
 		})
 	}
+	if projected := b.gwConfig.Spec.ConsulSpec.AuthSpec.ProjectedToken; projected != nil {
+		file := filepath.Clean(b.bearerTokenFile())
+		volumes = append(volumes, corev1.Volume{
+			Name: projectedTokenVolume,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{{
+						ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+							Audience:          projected.Audience,
+							ExpirationSeconds: projected.ExpirationSeconds,
+							Path:              filepath.Base(file),
+						},
+					}},
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      projectedTokenVolume,
+			MountPath: filepath.Dir(file),
+			ReadOnly:  true,
+		})
+	}
 	return volumes, mounts
 }
 
@@ -403,6 +721,7 @@ type gwContainerCommandData struct {
 	ConsulHTTPPort    string
 	ConsulGRPCPort    string
 	ACLAuthMethod     string
+	BearerTokenFile   string
 	LogLevel          string
 	GatewayHost       string
 	GatewayName       string
@@ -437,6 +756,10 @@ const gwContainerArgsTpl = `
 -acl-auth-method
 {{ .ACLAuthMethod }}
 {{- end }}
+{{- if .BearerTokenFile }}
+-acl-bearer-token-file
+{{ .BearerTokenFile }}
+{{- end }}
 {{- if .PrimaryDatacenter }}
 -consul-primary-datacenter
 {{ .PrimaryDatacenter }}
@@ -11,9 +11,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/utils/pointer"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
@@ -29,6 +33,17 @@ var (
 		"multiple-instances",
 		"max-instances",
 		"min-instances",
+		"autoscaling",
+		"pod-template",
+		"service-options",
+		"addresses",
+		"projected-token",
+	}
+	autoscalingFixtures = []string{
+		"autoscaling",
+	}
+	daemonSetFixtures = []string{
+		"daemonset",
 	}
 )
 
@@ -61,12 +76,33 @@ func (g *gatewayTestConfig) EncodeDeployment() runtime.Object {
 		Build(nil)
 }
 
+func (g *gatewayTestConfig) EncodeDaemonSet() runtime.Object {
+	return NewGatewayDeployment(g.gateway).
+		WithSDS("consul-api-gateway-controller.default.svc.cluster.local", 9090).
+		WithClassConfig(*g.gatewayClassConfig).
+		WithConsulCA("CONSUL_CA_MOCKED").
+		WithConsulGatewayNamespace("test").
+		BuildDaemonSet()
+}
+
 func (g *gatewayTestConfig) EncodeService() runtime.Object {
 	return NewGatewayService(g.gateway).
 		WithClassConfig(*g.gatewayClassConfig).
 		Build()
 }
 
+func (g *gatewayTestConfig) EncodeHorizontalPodAutoscaler() runtime.Object {
+	return NewGatewayHorizontalPodAutoscaler(g.gateway).
+		WithClassConfig(*g.gatewayClassConfig).
+		Build()
+}
+
+func (g *gatewayTestConfig) EncodePodDisruptionBudget() runtime.Object {
+	return NewGatewayPodDisruptionBudget(g.gateway).
+		WithClassConfig(*g.gatewayClassConfig).
+		Build()
+}
+
 func TestGatewayDeploymentBuilder(t *testing.T) {
 	t.Parallel()
 
@@ -80,6 +116,44 @@ func TestGatewayDeploymentBuilder(t *testing.T) {
 	}
 }
 
+func TestGatewayDeploymentBuilderConfigHash(t *testing.T) {
+	t.Parallel()
+
+	hash := func(spec v1alpha1.GatewayClassConfigSpec) string {
+		return NewGatewayDeployment(&gwv1beta1.Gateway{}).
+			WithClassConfig(v1alpha1.GatewayClassConfig{Spec: spec}).
+			Build(nil).Annotations[v1alpha1.ConfigHashAnnotation]
+	}
+	loadBalancer := corev1.ServiceTypeLoadBalancer
+
+	original := hash(v1alpha1.GatewayClassConfigSpec{})
+	require.NotEmpty(t, original)
+
+	// config that doesn't affect the gateway's pods doesn't roll them
+	require.Equal(t, original, hash(v1alpha1.GatewayClassConfigSpec{
+		ServiceType: &loadBalancer,
+		DeploymentSpec: v1alpha1.DeploymentSpec{
+			MaxInstances: pointer.Int32(4),
+			Autoscaling:  &v1alpha1.AutoscalingSpec{},
+		},
+	}))
+
+	require.NotEqual(t, original, hash(v1alpha1.GatewayClassConfigSpec{LogLevel: "debug"}))
+}
+
+func TestGatewayDaemonSetBuilder(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range daemonSetFixtures {
+		t.Run(name, func(t *testing.T) {
+			config := newGatewayTestConfig()
+			fixtureTest(t, name, "daemonset", config, func() runtime.Object {
+				return config.EncodeDaemonSet()
+			})
+		})
+	}
+}
+
 func TestGatewayServiceBuilder(t *testing.T) {
 	t.Parallel()
 
@@ -93,6 +167,114 @@ func TestGatewayServiceBuilder(t *testing.T) {
 	}
 }
 
+func TestGatewayHorizontalPodAutoscalerBuilder(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range autoscalingFixtures {
+		t.Run(name, func(t *testing.T) {
+			config := newGatewayTestConfig()
+			fixtureTest(t, name, "autoscaler", config, func() runtime.Object {
+				return config.EncodeHorizontalPodAutoscaler()
+			})
+		})
+	}
+}
+
+func TestGatewayPodDisruptionBudgetBuilder(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range autoscalingFixtures {
+		t.Run(name, func(t *testing.T) {
+			config := newGatewayTestConfig()
+			fixtureTest(t, name, "budget", config, func() runtime.Object {
+				return config.EncodePodDisruptionBudget()
+			})
+		})
+	}
+}
+
+func TestGatewayHorizontalPodAutoscalerBuilderValidate(t *testing.T) {
+	t.Parallel()
+
+	requestsCPU := &v1alpha1.PodTemplateSpec{
+		Resources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		},
+	}
+	metrics := []autoscalingv2.MetricSpec{{Type: autoscalingv2.PodsMetricSourceType}}
+
+	for _, test := range []struct {
+		name        string
+		deployment  v1alpha1.DeploymentSpec
+		podTemplate *v1alpha1.PodTemplateSpec
+		expected    string
+	}{{
+		name: "not autoscaled",
+		deployment: v1alpha1.DeploymentSpec{
+			MinInstances: pointer.Int32(4),
+			MaxInstances: pointer.Int32(2),
+		},
+	}, {
+		name:        "default cpu target",
+		deployment:  v1alpha1.DeploymentSpec{Autoscaling: &v1alpha1.AutoscalingSpec{}},
+		podTemplate: requestsCPU,
+	}, {
+		name:       "default cpu target without cpu requests",
+		deployment: v1alpha1.DeploymentSpec{Autoscaling: &v1alpha1.AutoscalingSpec{}},
+		expected:   "autoscaling on CPU utilization requires the pod template to request CPU",
+	}, {
+		name: "cpu target without cpu requests",
+		deployment: v1alpha1.DeploymentSpec{Autoscaling: &v1alpha1.AutoscalingSpec{
+			TargetCPUUtilizationPercentage: pointer.Int32(50),
+			Metrics:                        metrics,
+		}},
+		podTemplate: &v1alpha1.PodTemplateSpec{Resources: &corev1.ResourceRequirements{}},
+		expected:    "autoscaling on CPU utilization requires the pod template to request CPU",
+	}, {
+		name:       "other metrics without cpu requests",
+		deployment: v1alpha1.DeploymentSpec{Autoscaling: &v1alpha1.AutoscalingSpec{Metrics: metrics}},
+	}, {
+		name: "min instances over default max instances",
+		deployment: v1alpha1.DeploymentSpec{
+			MinInstances: pointer.Int32(9),
+			Autoscaling:  &v1alpha1.AutoscalingSpec{Metrics: metrics},
+		},
+		expected: "autoscaling minInstances 9 cannot be greater than maxInstances 8",
+	}, {
+		name: "min instances over max instances",
+		deployment: v1alpha1.DeploymentSpec{
+			MinInstances: pointer.Int32(4),
+			MaxInstances: pointer.Int32(2),
+			Autoscaling:  &v1alpha1.AutoscalingSpec{Metrics: metrics},
+		},
+		expected: "autoscaling minInstances 4 cannot be greater than maxInstances 2",
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewGatewayHorizontalPodAutoscaler(&gwv1beta1.Gateway{}).
+				WithClassConfig(v1alpha1.GatewayClassConfig{
+					Spec: v1alpha1.GatewayClassConfigSpec{
+						DeploymentSpec: test.deployment,
+						PodTemplate:    test.podTemplate,
+					},
+				}).
+				Validate()
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestGatewayAutoscalingNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	config := newGatewayTestConfig()
+	require.Nil(t, config.EncodeHorizontalPodAutoscaler())
+	require.Nil(t, config.EncodePodDisruptionBudget())
+}
+
 func fixtureTest(t *testing.T, name, suffix string, into *gatewayTestConfig, encode func() runtime.Object) {
 	t.Helper()
 
@@ -137,3 +319,115 @@ func fixtureTest(t *testing.T, name, suffix string, into *gatewayTestConfig, enc
 
 	require.Equal(t, expected, buffer.String())
 }
+
+func TestGatewayServiceBuilderValidate(t *testing.T) {
+	t.Parallel()
+
+	nodePort := corev1.ServiceTypeNodePort
+	clusterIP := corev1.ServiceTypeClusterIP
+	loadBalancer := corev1.ServiceTypeLoadBalancer
+
+	for _, test := range []struct {
+		name        string
+		serviceType *corev1.ServiceType
+		service     v1alpha1.ServiceSpec
+		addresses   *v1alpha1.AddressesSpec
+		expected    string
+	}{{
+		name: "no service",
+		service: v1alpha1.ServiceSpec{
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+		},
+	}, {
+		name:        "valid options",
+		serviceType: &loadBalancer,
+		service: v1alpha1.ServiceSpec{
+			ExternalTrafficPolicy:         corev1.ServiceExternalTrafficPolicyTypeLocal,
+			LoadBalancerSourceRanges:      []string{"10.0.0.0/8", "fd00::/8"},
+			LoadBalancerClass:             pointer.String("internal"),
+			IPFamilies:                    []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			NodePorts:                     []v1alpha1.ServiceNodePort{{Port: 80, NodePort: 30080}, {Port: 443, NodePort: 30443}},
+			SessionAffinity:               corev1.ServiceAffinityClientIP,
+			SessionAffinityTimeoutSeconds: pointer.Int32(60),
+		},
+	}, {
+		name:        "external traffic policy on cluster IP",
+		serviceType: &clusterIP,
+		service: v1alpha1.ServiceSpec{
+			ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicyTypeLocal,
+		},
+		expected: "service externalTrafficPolicy requires a NodePort or LoadBalancer service",
+	}, {
+		name:        "node ports on cluster IP",
+		serviceType: &clusterIP,
+		service: v1alpha1.ServiceSpec{
+			NodePorts: []v1alpha1.ServiceNodePort{{Port: 80, NodePort: 30080}},
+		},
+		expected: "service nodePorts require a NodePort or LoadBalancer service",
+	}, {
+		name:        "load balancer class on node port",
+		serviceType: &nodePort,
+		service: v1alpha1.ServiceSpec{
+			LoadBalancerClass: pointer.String("internal"),
+		},
+		expected: "service loadBalancerSourceRanges, loadBalancerClass and loadBalancerIPAnnotation require a LoadBalancer service",
+	}, {
+		name:        "invalid source range",
+		serviceType: &loadBalancer,
+		service: v1alpha1.ServiceSpec{
+			LoadBalancerSourceRanges: []string{"10.0.0.1"},
+		},
+		expected: `service loadBalancerSourceRanges contains invalid CIDR "10.0.0.1"`,
+	}, {
+		name: "invalid allowed IP range",
+		addresses: &v1alpha1.AddressesSpec{
+			AllowedIPRanges: []string{"10.0.0.0/8", "10.0.0.1"},
+		},
+		expected: `addresses allowedIPRanges contains invalid CIDR "10.0.0.1"`,
+	}, {
+		name:        "duplicate IP family",
+		serviceType: &clusterIP,
+		service: v1alpha1.ServiceSpec{
+			IPFamilies: []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv4Protocol},
+		},
+		expected: `service ipFamilies contains duplicate family "IPv4"`,
+	}, {
+		name:        "duplicate port",
+		serviceType: &nodePort,
+		service: v1alpha1.ServiceSpec{
+			NodePorts: []v1alpha1.ServiceNodePort{{Port: 80, NodePort: 30080}, {Port: 80, NodePort: 30081}},
+		},
+		expected: "service nodePorts maps port 80 more than once",
+	}, {
+		name:        "duplicate node port",
+		serviceType: &nodePort,
+		service: v1alpha1.ServiceSpec{
+			NodePorts: []v1alpha1.ServiceNodePort{{Port: 80, NodePort: 30080}, {Port: 443, NodePort: 30080}},
+		},
+		expected: "service nodePorts uses node port 30080 more than once",
+	}, {
+		name:        "timeout without client IP affinity",
+		serviceType: &clusterIP,
+		service: v1alpha1.ServiceSpec{
+			SessionAffinityTimeoutSeconds: pointer.Int32(60),
+		},
+		expected: "service sessionAffinityTimeoutSeconds requires ClientIP session affinity",
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewGatewayService(&gwv1beta1.Gateway{}).
+				WithClassConfig(v1alpha1.GatewayClassConfig{
+					Spec: v1alpha1.GatewayClassConfigSpec{
+						ServiceType:   test.serviceType,
+						ServiceSpec:   test.service,
+						AddressesSpec: test.addresses,
+					},
+				}).
+				Validate()
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package builder
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+)
+
+const (
+	gatewayContainerName     = "consul-api-gateway"
+	gatewayInitContainerName = "consul-api-gateway-init"
+
+	connectInjectAnnotation = "consul.hashicorp.com/connect-inject"
+
+	// defaultUserID is the user the consul-api-gateway image runs as, the gateway
+	// container runs the binary copied from it rather than anything in its own image
+	defaultUserID = 100
+)
+
+// validatePodTemplate checks that the pod template overrides of the GatewayClassConfig
+// can be applied to the deployment without clobbering anything the gateway relies on.
+func (b *GatewayDeploymentBuilder) validatePodTemplate() error {
+	override := b.gwConfig.Spec.PodTemplate
+	if override == nil {
+		return nil
+	}
+
+	for key := range utils.LabelsForGateway(b.gateway) {
+		if _, ok := override.Labels[key]; ok {
+			return fmt.Errorf("pod template cannot override label %q", key)
+		}
+	}
+	if _, ok := override.Annotations[connectInjectAnnotation]; ok {
+		return fmt.Errorf("pod template cannot override annotation %q", connectInjectAnnotation)
+	}
+
+	if override.Resources != nil {
+		for name, request := range override.Resources.Requests {
+			if limit, ok := override.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+				return fmt.Errorf("pod template %s request must be less than or equal to its limit", name)
+			}
+		}
+	}
+
+	reservedEnv := map[string]struct{}{api.HTTPCAFile: {}}
+	for _, env := range b.envVars() {
+		reservedEnv[env.Name] = struct{}{}
+	}
+	for _, env := range override.Env {
+		if _, ok := reservedEnv[env.Name]; ok {
+			return fmt.Errorf("pod template environment variable %q is reserved or duplicated", env.Name)
+		}
+		reservedEnv[env.Name] = struct{}{}
+	}
+
+	// the CA and token volumes are only added when connecting to Consul over TLS
+	// or with a projected token, but reserve them regardless so that changing
+	// either doesn't break things
+	volumes := map[string]struct{}{"ca": {}, projectedTokenVolume: {}}
+	reservedPaths := map[string]struct{}{consulCALocalPath: {}}
+	_, mounts := b.volumes()
+	for _, mount := range mounts {
+		volumes[mount.Name] = struct{}{}
+		reservedPaths[mount.MountPath] = struct{}{}
+	}
+	for _, volume := range override.Volumes {
+		if _, ok := volumes[volume.Name]; ok {
+			return fmt.Errorf("pod template volume %q is reserved or duplicated", volume.Name)
+		}
+		volumes[volume.Name] = struct{}{}
+	}
+	for _, mount := range override.VolumeMounts {
+		if _, ok := volumes[mount.Name]; !ok {
+			return fmt.Errorf("pod template volume mount references unknown volume %q", mount.Name)
+		}
+		if _, ok := reservedPaths[mount.MountPath]; ok {
+			return fmt.Errorf("pod template volume mount path %q is reserved or duplicated", mount.MountPath)
+		}
+		reservedPaths[mount.MountPath] = struct{}{}
+	}
+
+	containers := map[string]struct{}{
+		gatewayContainerName:     {},
+		gatewayInitContainerName: {},
+	}
+	for _, container := range override.Containers {
+		if container.Name == "" || container.Image == "" {
+			return fmt.Errorf("pod template containers must have a name and image")
+		}
+		if _, ok := containers[container.Name]; ok {
+			return fmt.Errorf("pod template container %q is reserved or duplicated", container.Name)
+		}
+		containers[container.Name] = struct{}{}
+	}
+
+	return nil
+}
+
+// podLabels returns the labels for gateway pods, the labels used
+// to select gateway pods always take precedence over any overrides
+func (b *GatewayDeploymentBuilder) podLabels() map[string]string {
+	labels := map[string]string{}
+	if override := b.gwConfig.Spec.PodTemplate; override != nil {
+		for key, value := range override.Labels {
+			labels[key] = value
+		}
+	}
+	for key, value := range utils.LabelsForGateway(b.gateway) {
+		labels[key] = value
+	}
+	return labels
+}
+
+// podAnnotations returns the annotations for gateway pods, gateways
+// must never have a Connect sidecar injected into them
+func (b *GatewayDeploymentBuilder) podAnnotations() map[string]string {
+	annotations := map[string]string{}
+	if override := b.gwConfig.Spec.PodTemplate; override != nil {
+		for key, value := range override.Annotations {
+			annotations[key] = value
+		}
+	}
+	annotations[connectInjectAnnotation] = "false"
+	return annotations
+}
+
+// applySecurityDefaults sets security contexts that meet the restricted Pod Security Standard
+// so that gateways can run in namespaces enforcing it, pod template overrides replace them
+func (b *GatewayDeploymentBuilder) applySecurityDefaults(spec *corev1.PodSpec) {
+	podContext := &corev1.PodSecurityContext{
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	// pods on the host network can't meet the restricted standard regardless, so they run as
+	// the image's user, which can be granted the capability to bind privileged ports on the node
+	if !spec.HostNetwork {
+		podContext.RunAsNonRoot = pointer.Bool(true)
+		// OpenShift assigns users from the namespace's range and rejects any others
+		if b.gwConfig.Spec.ConsulSpec.AuthSpec.SecurityContextConstraints == "" {
+			podContext.RunAsUser = pointer.Int64(defaultUserID)
+		}
+		if b.bindsPrivilegedPorts() {
+			podContext.Sysctls = []corev1.Sysctl{{Name: "net.ipv4.ip_unprivileged_port_start", Value: "0"}}
+		}
+	}
+	spec.SecurityContext = podContext
+
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		containers[0].SecurityContext = &corev1.SecurityContext{
+			AllowPrivilegeEscalation: pointer.Bool(false),
+			ReadOnlyRootFilesystem:   pointer.Bool(true),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		}
+	}
+	if spec.HostNetwork {
+		spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"NET_BIND_SERVICE"}
+	}
+}
+
+// bindsPrivilegedPorts returns whether any of the gateway's listeners use a port below 1024
+func (b *GatewayDeploymentBuilder) bindsPrivilegedPorts() bool {
+	for _, listener := range b.gateway.Spec.Listeners {
+		if listener.Port < 1024 {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPodTemplate applies the pod template overrides of the GatewayClassConfig to a gateway pod spec
+func (b *GatewayDeploymentBuilder) applyPodTemplate(spec *corev1.PodSpec) {
+	override := b.gwConfig.Spec.PodTemplate
+	if override == nil {
+		return
+	}
+
+	// resources and security contexts also apply to the init container so that
+	// pods are admitted in namespaces with resource quotas or pod security standards
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		container := &containers[0]
+		if override.Resources != nil {
+			container.Resources = *override.Resources
+		}
+		if override.SecurityContext != nil {
+			container.SecurityContext = override.SecurityContext
+		}
+	}
+
+	gateway := &spec.Containers[0]
+	gateway.Env = append(gateway.Env, override.Env...)
+	gateway.VolumeMounts = append(gateway.VolumeMounts, override.VolumeMounts...)
+
+	if override.Affinity != nil {
+		spec.Affinity = override.Affinity
+	}
+	if override.PodSecurityContext != nil {
+		spec.SecurityContext = override.PodSecurityContext
+	}
+	spec.PriorityClassName = override.PriorityClassName
+	spec.TopologySpreadConstraints = override.TopologySpreadConstraints
+	spec.Volumes = append(spec.Volumes, override.Volumes...)
+	spec.Containers = append(spec.Containers, override.Containers...)
+}
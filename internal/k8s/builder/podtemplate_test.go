@@ -0,0 +1,293 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
+)
+
+func TestGatewayDeploymentBuilderSecurityDefaults(t *testing.T) {
+	t.Parallel()
+
+	gateway := func(port gwv1beta1.PortNumber) *gwv1beta1.Gateway {
+		return &gwv1beta1.Gateway{
+			Spec: gwv1beta1.GatewaySpec{
+				Listeners: []gwv1beta1.Listener{{Name: "listener", Port: port}},
+			},
+		}
+	}
+
+	t.Run("restricted", func(t *testing.T) {
+		spec := NewGatewayDeployment(gateway(8080)).
+			WithClassConfig(v1alpha1.GatewayClassConfig{}).
+			Build(nil).Spec.Template.Spec
+		require.True(t, *spec.SecurityContext.RunAsNonRoot)
+		require.Equal(t, int64(defaultUserID), *spec.SecurityContext.RunAsUser)
+		require.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, spec.SecurityContext.SeccompProfile.Type)
+		require.Empty(t, spec.SecurityContext.Sysctls)
+		for _, container := range append(spec.InitContainers, spec.Containers...) {
+			require.False(t, *container.SecurityContext.AllowPrivilegeEscalation)
+			require.True(t, *container.SecurityContext.ReadOnlyRootFilesystem)
+			require.Equal(t, []corev1.Capability{"ALL"}, container.SecurityContext.Capabilities.Drop)
+			require.Empty(t, container.SecurityContext.Capabilities.Add)
+		}
+	})
+
+	t.Run("privileged ports", func(t *testing.T) {
+		spec := NewGatewayDeployment(gateway(443)).
+			WithClassConfig(v1alpha1.GatewayClassConfig{}).
+			Build(nil).Spec.Template.Spec
+		require.Equal(t, []corev1.Sysctl{{Name: "net.ipv4.ip_unprivileged_port_start", Value: "0"}}, spec.SecurityContext.Sysctls)
+	})
+
+	t.Run("security context constraints", func(t *testing.T) {
+		spec := NewGatewayDeployment(gateway(8080)).
+			WithClassConfig(v1alpha1.GatewayClassConfig{
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					ConsulSpec: v1alpha1.ConsulSpec{
+						AuthSpec: v1alpha1.AuthSpec{Managed: true, SecurityContextConstraints: "restricted-v2"},
+					},
+				},
+			}).
+			Build(nil).Spec.Template.Spec
+		require.True(t, *spec.SecurityContext.RunAsNonRoot)
+		require.Nil(t, spec.SecurityContext.RunAsUser)
+	})
+
+	t.Run("host network", func(t *testing.T) {
+		spec := NewGatewayDeployment(gateway(443)).
+			WithClassConfig(v1alpha1.GatewayClassConfig{
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					DeploymentSpec: v1alpha1.DeploymentSpec{Mode: v1alpha1.DeploymentModeDaemonSet},
+				},
+			}).
+			BuildDaemonSet().Spec.Template.Spec
+		require.Nil(t, spec.SecurityContext.RunAsNonRoot)
+		require.Empty(t, spec.SecurityContext.Sysctls)
+		require.Equal(t, []corev1.Capability{"NET_BIND_SERVICE"}, spec.Containers[0].SecurityContext.Capabilities.Add)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		spec := NewGatewayDeployment(gateway(8080)).
+			WithClassConfig(v1alpha1.GatewayClassConfig{
+				Spec: v1alpha1.GatewayClassConfigSpec{
+					PodTemplate: &v1alpha1.PodTemplateSpec{
+						PodSecurityContext: &corev1.PodSecurityContext{},
+					},
+				},
+			}).
+			Build(nil).Spec.Template.Spec
+		require.Equal(t, &corev1.PodSecurityContext{}, spec.SecurityContext)
+		require.NotNil(t, spec.Containers[0].SecurityContext)
+	})
+}
+
+func TestGatewayDeploymentBuilderValidateMode(t *testing.T) {
+	t.Parallel()
+
+	clusterIP := corev1.ServiceTypeClusterIP
+	daemonSet := v1alpha1.DeploymentSpec{Mode: v1alpha1.DeploymentModeDaemonSet}
+
+	for _, test := range []struct {
+		name     string
+		spec     v1alpha1.GatewayClassConfigSpec
+		expected string
+	}{{
+		name: "daemonset",
+		spec: v1alpha1.GatewayClassConfigSpec{DeploymentSpec: daemonSet},
+	}, {
+		name: "deployment with service",
+		spec: v1alpha1.GatewayClassConfigSpec{
+			ServiceType:    &clusterIP,
+			DeploymentSpec: v1alpha1.DeploymentSpec{Mode: v1alpha1.DeploymentModeDeployment},
+		},
+	}, {
+		name: "daemonset with service",
+		spec: v1alpha1.GatewayClassConfigSpec{
+			ServiceType:    &clusterIP,
+			DeploymentSpec: daemonSet,
+		},
+		expected: "DaemonSet mode gateways use the host network and cannot have a serviceType",
+	}, {
+		name: "daemonset with autoscaling",
+		spec: v1alpha1.GatewayClassConfigSpec{
+			DeploymentSpec: v1alpha1.DeploymentSpec{
+				Mode:        v1alpha1.DeploymentModeDaemonSet,
+				Autoscaling: &v1alpha1.AutoscalingSpec{},
+			},
+		},
+		expected: "DaemonSet mode gateways run on every selected node and cannot be autoscaled",
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewGatewayDeployment(&gwv1beta1.Gateway{}).
+				WithClassConfig(v1alpha1.GatewayClassConfig{Spec: test.spec}).
+				Validate()
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestGatewayDeploymentBuilderValidateBearerTokenFile(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		auth     v1alpha1.AuthSpec
+		expected string
+	}{{
+		name: "default token",
+	}, {
+		name: "projected token",
+		auth: v1alpha1.AuthSpec{ProjectedToken: &v1alpha1.ProjectedTokenSpec{Audience: "consul"}},
+	}, {
+		name: "projected token at custom path",
+		auth: v1alpha1.AuthSpec{
+			BearerTokenFile: "/var/run/secrets/consul/jwt",
+			ProjectedToken:  &v1alpha1.ProjectedTokenSpec{},
+		},
+	}, {
+		name: "custom path",
+		auth: v1alpha1.AuthSpec{BearerTokenFile: "/extra/token"},
+	}, {
+		name:     "relative path",
+		auth:     v1alpha1.AuthSpec{BearerTokenFile: "token"},
+		expected: `bearer token file "token" must be an absolute file path`,
+	}, {
+		name:     "directory path",
+		auth:     v1alpha1.AuthSpec{BearerTokenFile: "/var/run/secrets/consul/"},
+		expected: `bearer token file "/var/run/secrets/consul/" must be an absolute file path`,
+	}, {
+		name: "projected token at reserved path",
+		auth: v1alpha1.AuthSpec{
+			BearerTokenFile: "/bootstrap/token",
+			ProjectedToken:  &v1alpha1.ProjectedTokenSpec{},
+		},
+		expected: `projected token cannot be mounted at reserved path "/bootstrap"`,
+	}, {
+		name: "projected token at default token path",
+		auth: v1alpha1.AuthSpec{
+			BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			ProjectedToken:  &v1alpha1.ProjectedTokenSpec{},
+		},
+		expected: `projected token cannot be mounted at reserved path "/var/run/secrets/kubernetes.io/serviceaccount"`,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewGatewayDeployment(&gwv1beta1.Gateway{}).
+				WithClassConfig(v1alpha1.GatewayClassConfig{
+					Spec: v1alpha1.GatewayClassConfigSpec{
+						ConsulSpec: v1alpha1.ConsulSpec{AuthSpec: test.auth},
+					},
+				}).
+				Validate()
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestGatewayDeploymentBuilderValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		template *v1alpha1.PodTemplateSpec
+		expected string
+	}{{
+		name: "no overrides",
+	}, {
+		name: "valid overrides",
+		template: &v1alpha1.PodTemplateSpec{
+			Labels:       map[string]string{"team": "edge"},
+			Env:          []corev1.EnvVar{{Name: "EXTRA"}},
+			Volumes:      []corev1.Volume{{Name: "extra"}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/extra"}, {Name: "certs", MountPath: "/more-certs"}},
+			Containers:   []corev1.Container{{Name: "sidecar", Image: "image"}},
+		},
+	}, {
+		name: "managed label",
+		template: &v1alpha1.PodTemplateSpec{
+			Labels: map[string]string{"api-gateway.consul.hashicorp.com/managed": "false"},
+		},
+		expected: `pod template cannot override label "api-gateway.consul.hashicorp.com/managed"`,
+	}, {
+		name: "connect inject annotation",
+		template: &v1alpha1.PodTemplateSpec{
+			Annotations: map[string]string{"consul.hashicorp.com/connect-inject": "true"},
+		},
+		expected: `pod template cannot override annotation "consul.hashicorp.com/connect-inject"`,
+	}, {
+		name: "request over limit",
+		template: &v1alpha1.PodTemplateSpec{
+			Resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		},
+		expected: "pod template memory request must be less than or equal to its limit",
+	}, {
+		name: "reserved environment variable",
+		template: &v1alpha1.PodTemplateSpec{
+			Env: []corev1.EnvVar{{Name: "HOST_IP"}},
+		},
+		expected: `pod template environment variable "HOST_IP" is reserved or duplicated`,
+	}, {
+		name: "reserved volume",
+		template: &v1alpha1.PodTemplateSpec{
+			Volumes: []corev1.Volume{{Name: "ca"}},
+		},
+		expected: `pod template volume "ca" is reserved or duplicated`,
+	}, {
+		name: "unknown volume mount",
+		template: &v1alpha1.PodTemplateSpec{
+			VolumeMounts: []corev1.VolumeMount{{Name: "missing", MountPath: "/missing"}},
+		},
+		expected: `pod template volume mount references unknown volume "missing"`,
+	}, {
+		name: "reserved mount path",
+		template: &v1alpha1.PodTemplateSpec{
+			Volumes:      []corev1.Volume{{Name: "extra"}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "extra", MountPath: "/bootstrap"}},
+		},
+		expected: `pod template volume mount path "/bootstrap" is reserved or duplicated`,
+	}, {
+		name: "reserved container",
+		template: &v1alpha1.PodTemplateSpec{
+			Containers: []corev1.Container{{Name: "consul-api-gateway-init", Image: "image"}},
+		},
+		expected: `pod template container "consul-api-gateway-init" is reserved or duplicated`,
+	}, {
+		name: "container without image",
+		template: &v1alpha1.PodTemplateSpec{
+			Containers: []corev1.Container{{Name: "sidecar"}},
+		},
+		expected: "pod template containers must have a name and image",
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewGatewayDeployment(&gwv1beta1.Gateway{}).
+				WithClassConfig(v1alpha1.GatewayClassConfig{
+					Spec: v1alpha1.GatewayClassConfigSpec{PodTemplate: test.template},
+				}).
+				Validate()
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -9,12 +9,17 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	tls "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/armon/go-metrics"
@@ -27,11 +32,28 @@ import (
 )
 
 var _ envoy.SecretClient = (*K8sSecretClient)(nil)
+var _ envoy.SecretWatcher = (*K8sSecretClient)(nil)
+var _ envoy.SecretUnwatcher = (*K8sSecretClient)(nil)
+
+// secretResyncPeriod is how often the secret informer replays its cache, we
+// ignore resyncs since they don't represent actual changes
+const secretResyncPeriod = 10 * time.Minute
 
 // K8sSecretClient acts as a secret fetcher for kubernetes secrets
 type K8sSecretClient struct {
-	logger hclog.Logger
-	client client.Client
+	logger    hclog.Logger
+	client    client.Client
+	clientset kubernetes.Interface
+
+	// watches holds every secret that has been fetched and not yet unwatched,
+	// keyed by its SDS name
+	watches map[string]utils.K8sSecret
+	// informers holds the cancel function of the secret informer shared by
+	// all of the watched secrets in each namespace
+	informers map[string]context.CancelFunc
+	mutex     sync.Mutex
+	// fetched signals the watcher that a new namespace may need watching
+	fetched chan struct{}
 }
 
 // NewK8sSecretClient initializes a K8sSecretClient instance
@@ -42,9 +64,17 @@ func NewK8sSecretClient(logger hclog.Logger, config *rest.Config) (*K8sSecretCli
 	if err != nil {
 		return nil, err
 	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
 	return &K8sSecretClient{
-		logger: logger,
-		client: apiClient,
+		logger:    logger,
+		client:    apiClient,
+		clientset: clientset,
+		watches:   make(map[string]utils.K8sSecret),
+		informers: make(map[string]context.CancelFunc),
+		fetched:   make(chan struct{}, 1),
 	}, nil
 }
 
@@ -63,6 +93,10 @@ func (c *K8sSecretClient) FetchSecret(ctx context.Context, fullName string) (*tl
 		Name:  "name",
 		Value: fullName,
 	}})
+
+	// track the secret even if it doesn't exist yet so that we pick up its creation
+	c.track(fullName, k8sSecret)
+
 	secret := &corev1.Secret{}
 	err = c.client.Get(ctx, client.ObjectKey{
 		Namespace: k8sSecret.Namespace,
@@ -102,3 +136,170 @@ func (c *K8sSecretClient) FetchSecret(ctx context.Context, fullName string) (*tl
 		Name: fullName,
 	}, cert.NotAfter, nil
 }
+
+// WatchSecrets watches the namespaces of every fetched secret with an informer and
+// sends a change whenever the certificate or private key of a watched secret changes.
+func (c *K8sSecretClient) WatchSecrets(ctx context.Context, changes chan<- envoy.SecretChange) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	start := func() {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		for _, secret := range c.watches {
+			if _, ok := c.informers[secret.Namespace]; ok {
+				continue
+			}
+			watchCtx, stop := context.WithCancel(ctx)
+			c.informers[secret.Namespace] = stop
+
+			wg.Add(1)
+			go func(namespace string) {
+				defer wg.Done()
+				c.watchNamespace(watchCtx, namespace, changes)
+			}(secret.Namespace)
+		}
+	}
+	start()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.fetched:
+			start()
+		}
+	}
+}
+
+// UnwatchSecret stops watching a secret that is no longer referenced, the informer
+// of its namespace is stopped once none of the secrets in it are watched
+func (c *K8sSecretClient) UnwatchSecret(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	secret, ok := c.watches[name]
+	if !ok {
+		return
+	}
+	delete(c.watches, name)
+
+	for _, watched := range c.watches {
+		if watched.Namespace == secret.Namespace {
+			return
+		}
+	}
+	if stop, ok := c.informers[secret.Namespace]; ok {
+		stop()
+		delete(c.informers, secret.Namespace)
+	}
+}
+
+// watchNamespace runs a secret informer for a namespace until the context is canceled,
+// dispatching changes to the watches of the secrets that changed
+func (c *K8sSecretClient) watchNamespace(ctx context.Context, namespace string, changes chan<- envoy.SecretChange) {
+	factory := informers.NewSharedInformerFactoryWithOptions(c.clientset, secretResyncPeriod,
+		informers.WithNamespace(namespace),
+	)
+	informer := factory.Core().V1().Secrets().Informer()
+
+	notify := func(obj interface{}) {
+		changed, ok := obj.(*corev1.Secret)
+		if !ok {
+			return
+		}
+		for _, name := range c.watching(changed.Namespace, changed.Name) {
+			c.logger.Trace("kubernetes secret changed", "name", name)
+			select {
+			case changes <- envoy.SecretChange{Name: name, Changed: lastModified(changed)}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			// secrets that are re-created after being deleted, the initial
+			// listing is ignored since watched secrets were already fetched directly
+			if informer.HasSynced() {
+				notify(obj)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSecret, ok := oldObj.(*corev1.Secret)
+			if !ok {
+				return
+			}
+			newSecret, ok := newObj.(*corev1.Secret)
+			if !ok {
+				return
+			}
+			if equality.Semantic.DeepEqual(oldSecret.Data, newSecret.Data) {
+				// resync or metadata-only update
+				return
+			}
+			notify(newSecret)
+		},
+	}); err != nil {
+		c.logger.Error("error watching kubernetes secrets", "namespace", namespace, "error", err)
+		return
+	}
+
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		if ctx.Err() == nil {
+			c.logger.Error("failed to sync kubernetes secret informer", "namespace", namespace)
+		}
+		return
+	}
+	<-ctx.Done()
+}
+
+// watching returns the names of the watches for a secret
+func (c *K8sSecretClient) watching(namespace, name string) []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	names := []string{}
+	for watchName, secret := range c.watches {
+		if secret.Namespace == namespace && secret.Name == name {
+			names = append(names, watchName)
+		}
+	}
+	return names
+}
+
+func (c *K8sSecretClient) track(name string, secret utils.K8sSecret) {
+	c.mutex.Lock()
+	_, ok := c.watches[name]
+	if !ok {
+		c.watches[name] = secret
+	}
+	c.mutex.Unlock()
+
+	if !ok {
+		select {
+		case c.fetched <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// lastModified returns the last time that a secret was written to, falling back
+// to the current time if we can't tell from its managed fields.
+func lastModified(secret *corev1.Secret) time.Time {
+	modified := time.Time{}
+	for _, entry := range secret.ManagedFields {
+		if entry.Time != nil && entry.Time.After(modified) {
+			modified = entry.Time.Time
+		}
+	}
+	if modified.IsZero() {
+		return time.Now()
+	}
+	return modified
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/envoy"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	gwTesting "github.com/hashicorp/consul-api-gateway/internal/testing"
+)
+
+func TestK8sSecretClientWatchSecrets(t *testing.T) {
+	t.Parallel()
+
+	certificate, err := gwTesting.GenerateSignedCertificate(gwTesting.GenerateCertificateOptions{})
+	require.NoError(t, err)
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       certificate.CertBytes,
+				corev1.TLSPrivateKeyKey: certificate.PrivateKeyBytes,
+			},
+		}
+	}
+	watched, unwatched := newSecret("watched"), newSecret("unwatched")
+
+	clientset := fake.NewSimpleClientset(watched, unwatched)
+	c := &K8sSecretClient{
+		logger:    hclog.NewNullLogger(),
+		client:    fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(watched, unwatched).Build(),
+		clientset: clientset,
+		watches:   make(map[string]utils.K8sSecret),
+		informers: make(map[string]context.CancelFunc),
+		fetched:   make(chan struct{}, 1),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan envoy.SecretChange)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- c.WatchSecrets(ctx, changes)
+	}()
+
+	name := utils.NewK8sSecret(watched.Namespace, watched.Name).String()
+	_, _, err = c.FetchSecret(ctx, name)
+	require.NoError(t, err)
+
+	rotations := 0
+	update := func(secret *corev1.Secret) {
+		rotations++
+		secret = secret.DeepCopy()
+		secret.Data[corev1.TLSPrivateKeyKey] = []byte(fmt.Sprintf("rotated-%d", rotations))
+		_, err := clientset.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+
+	// only fetched secrets are watched, retry until the informer has synced
+	require.Eventually(t, func() bool {
+		update(unwatched)
+		update(watched)
+		select {
+		case change := <-changes:
+			require.Equal(t, name, change.Name)
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// secrets in the same namespace share an informer
+	other := utils.NewK8sSecret(unwatched.Namespace, unwatched.Name).String()
+	_, _, err = c.FetchSecret(ctx, other)
+	require.NoError(t, err)
+	c.mutex.Lock()
+	require.Len(t, c.informers, 1)
+	c.mutex.Unlock()
+
+	c.UnwatchSecret(name)
+	c.mutex.Lock()
+	require.Len(t, c.informers, 1)
+	c.mutex.Unlock()
+
+	c.UnwatchSecret(other)
+	c.mutex.Lock()
+	require.Empty(t, c.watches)
+	require.Empty(t, c.informers)
+	c.mutex.Unlock()
+
+	cancel()
+	require.NoError(t, <-errs)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package k8s
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// configSigningKeySecret holds the key the controller signs the config it
+	// snapshots onto gateways with
+	configSigningKeySecret = "consul-api-gateway-config-signing-key"
+	configSigningKey       = "key"
+	configSigningKeySize   = 32
+)
+
+// loadConfigSigningKey returns the key stored in the given secret, creating it if it
+// doesn't exist yet so that every controller replica signs with the same key
+func loadConfigSigningKey(ctx context.Context, reader client.Reader, writer client.Writer, key client.ObjectKey) ([]byte, error) {
+	secret := &corev1.Secret{}
+	err := reader.Get(ctx, key, secret)
+	if err == nil {
+		if len(secret.Data[configSigningKey]) == 0 {
+			return nil, fmt.Errorf("secret %s has no config signing key", key)
+		}
+		return secret.Data[configSigningKey], nil
+	}
+	if !k8serrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	signingKey := make([]byte, configSigningKeySize)
+	if _, err := rand.Read(signingKey); err != nil {
+		return nil, err
+	}
+	err = writer.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			configSigningKey: signingKey,
+		},
+	})
+	if k8serrors.IsAlreadyExists(err) {
+		// another replica created it first
+		return loadConfigSigningKey(ctx, reader, writer, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return signingKey, nil
+}
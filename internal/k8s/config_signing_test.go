@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLoadConfigSigningKey(t *testing.T) {
+	t.Parallel()
+
+	key := client.ObjectKey{Namespace: "default", Name: configSigningKeySecret}
+	apiClient := fakeclient.NewClientBuilder().WithScheme(scheme).Build()
+
+	// created on first load and reused after
+	created, err := loadConfigSigningKey(context.Background(), apiClient, apiClient, key)
+	require.NoError(t, err)
+	require.Len(t, created, configSigningKeySize)
+
+	loaded, err := loadConfigSigningKey(context.Background(), apiClient, apiClient, key)
+	require.NoError(t, err)
+	require.Equal(t, created, loaded)
+
+	// an existing secret without a key isn't silently replaced
+	empty := client.ObjectKey{Namespace: "default", Name: "empty"}
+	require.NoError(t, apiClient.Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: empty.Namespace, Name: empty.Name},
+	}))
+	_, err = loadConfigSigningKey(context.Background(), apiClient, apiClient, empty)
+	require.Error(t, err)
+}
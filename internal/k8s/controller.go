@@ -7,12 +7,16 @@ import (
 	"context"
 	"fmt"
 
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	klogv2 "k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -23,7 +27,10 @@ import (
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/controllers"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
 	"github.com/hashicorp/consul-api-gateway/internal/store"
+	"github.com/hashicorp/consul-api-gateway/internal/vault"
 	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
@@ -33,6 +40,9 @@ import (
 //+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=create;get;list;update
 //+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=meshservices,verbs=get;list;watch
+//+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=externalservices,verbs=get;list;watch
+//+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=vaultcertificates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=gatewayconfigs,verbs=get;list;watch
 
 var scheme = runtime.NewScheme()
 
@@ -92,6 +102,9 @@ type Config struct {
 
 	// ConsulNamespaceConfig
 	ConsulNamespaceConfig ConsulNamespaceConfig
+
+	// VaultCertificatePolicy restricts what VaultCertificates can reference
+	VaultCertificatePolicy vault.CertificatePolicy
 }
 
 func Defaults() *Config {
@@ -165,6 +178,50 @@ func (k *Kubernetes) Start(ctx context.Context) error {
 
 	gwClient := k.Client()
 
+	httpRouteEvents := make(chan event.GenericEvent)
+	tcpRouteEvents := make(chan event.GenericEvent)
+	catalogWatcher := consul.NewCatalogWatcher(ctx, k.logger.Named("CatalogWatcher"), k.consul, func(id string) {
+		kind, name, ok := reconciler.ParseRouteID(id)
+		if !ok {
+			return
+		}
+		var routeEvent event.GenericEvent
+		var events chan event.GenericEvent
+		switch kind {
+		case "HTTPRoute":
+			routeEvent.Object = &gwv1alpha2.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}
+			events = httpRouteEvents
+		case "TCPRoute":
+			routeEvent.Object = &gwv1alpha2.TCPRoute{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}
+			events = tcpRouteEvents
+		}
+		select {
+		case <-ctx.Done():
+		case events <- routeEvent:
+		}
+	})
+
+	gatewayEvents := make(chan event.GenericEvent)
+	requeueGateway := func(name types.NamespacedName) {
+		select {
+		case <-ctx.Done():
+		case gatewayEvents <- event.GenericEvent{Object: &gwv1beta1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace}}}:
+		}
+	}
+
+	signingKeyNamespace := "default"
+	if k.config.Namespace != "" {
+		signingKeyNamespace = k.config.Namespace
+	}
+	// the cache isn't running yet, so read the key straight from the API server
+	signingKey, err := loadConfigSigningKey(ctx, k.k8sManager.GetAPIReader(), k.k8sManager.GetClient(), types.NamespacedName{
+		Namespace: signingKeyNamespace,
+		Name:      configSigningKeySecret,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load config signing key: %w", err)
+	}
+
 	reconcileManager := reconciler.NewReconcileManager(reconciler.ManagerConfig{
 		ControllerName:           ControllerName,
 		Client:                   gwClient,
@@ -177,12 +234,18 @@ func (k *Kubernetes) Start(ctx context.Context) error {
 		Store:                    k.store,
 		ConsulNamespaceMapper:    k.config.ConsulNamespaceConfig.Namespace,
 		ConsulNamespaceMirroring: k.config.ConsulNamespaceConfig.MirrorKubernetesNamespaces,
+		CatalogWatcher:           catalogWatcher,
+		RequeueGateway:           requeueGateway,
+		VaultCertificatePolicy:   k.config.VaultCertificatePolicy,
+		ConfigSigningKey:         signingKey,
 	})
 
-	err := (&controllers.GatewayClassConfigReconciler{
+	err = (&controllers.GatewayClassConfigReconciler{
+		Context: ctx,
 		Client:  gwClient,
 		Log:     k.logger.Named("GatewayClassConfig"),
 		Manager: reconcileManager,
+		Consul:  k.consul,
 	}).SetupWithManager(k.k8sManager)
 	if err != nil {
 		return fmt.Errorf("failed to create gateway class config controller: %w", err)
@@ -198,23 +261,47 @@ func (k *Kubernetes) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to create gateway class controller: %w", err)
 	}
 
+	// cert-manager is optional, so only watch the Certificates we create if
+	// its CRDs are installed, otherwise the watch would fail to start
+	certManagerInstalled := true
+	if _, err := k.k8sManager.GetRESTMapper().RESTMapping(utils.CertManagerCertificateGVK.GroupKind(), utils.CertManagerCertificateGVK.Version); err != nil {
+		if !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to look up cert-manager certificates: %w", err)
+		}
+		certManagerInstalled = false
+	}
+
 	err = (&controllers.GatewayReconciler{
-		Context:        ctx,
-		Client:         gwClient,
-		Log:            k.logger.Named("Gateway"),
-		Manager:        reconcileManager,
-		ControllerName: ControllerName,
+		Context:                      ctx,
+		Client:                       gwClient,
+		Log:                          k.logger.Named("Gateway"),
+		Manager:                      reconcileManager,
+		ControllerName:               ControllerName,
+		WatchCertManagerCertificates: certManagerInstalled,
+		CertificateEvents:            gatewayEvents,
 	}).SetupWithManager(k.k8sManager)
 	if err != nil {
 		return fmt.Errorf("failed to create gateway controller: %w", err)
 	}
 
+	err = (&controllers.MeshServiceReconciler{
+		Client:                gwClient,
+		Log:                   k.logger.Named("MeshService"),
+		Resolver:              service.NewBackendResolver(k.logger.Named("MeshService"), k.config.ConsulNamespaceConfig.Namespace, gwClient, k.consul),
+		ConsulNamespaceMapper: k.config.ConsulNamespaceConfig.Namespace,
+		ConsulPartition:       k.config.ConsulNamespaceConfig.PartitionInfo.PartitionName,
+	}).SetupWithManager(k.k8sManager)
+	if err != nil {
+		return fmt.Errorf("failed to create mesh service controller: %w", err)
+	}
+
 	err = (&controllers.HTTPRouteReconciler{
 		Context:        ctx,
 		Client:         gwClient,
 		Log:            k.logger.Named("HTTPRoute"),
 		Manager:        reconcileManager,
 		ControllerName: ControllerName,
+		CatalogEvents:  httpRouteEvents,
 	}).SetupWithManager(k.k8sManager)
 	if err != nil {
 		return fmt.Errorf("failed to create http route controller: %w", err)
@@ -226,6 +313,7 @@ func (k *Kubernetes) Start(ctx context.Context) error {
 		Log:            k.logger.Named("TCPRoute"),
 		Manager:        reconcileManager,
 		ControllerName: ControllerName,
+		CatalogEvents:  tcpRouteEvents,
 	}).SetupWithManager(k.k8sManager)
 	if err != nil {
 		return fmt.Errorf("failed to create tcp route controller: %w", err)
@@ -5,10 +5,32 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/hashicorp/consul/api"
+
+	"github.com/hashicorp/consul-api-gateway/internal/consul"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/builder"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler"
 	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
@@ -19,15 +41,41 @@ const (
 	gatewayClassConfigFinalizer = "gateway-class-exists-finalizer.api-gateway.consul.hashicorp.com"
 )
 
+// imageReference matches container image references: an optional registry, a
+// repository path, and an optional tag and digest
+var imageReference = regexp.MustCompile(`^` +
+	`(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[\w][\w.-]{0,127})?` +
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?` +
+	`$`)
+
 // GatewayClassConfigReconciler reconciles a GatewayClassConfig object
 type GatewayClassConfigReconciler struct {
+	Context context.Context
 	Client  gatewayclient.Client
 	Log     hclog.Logger
 	Manager reconciler.ReconcileManager
+
+	// Consul is used to check that the auth method a config references
+	// exists, the check is skipped if it isn't set
+	Consul consul.Client
+
+	authMethodsMutex sync.Mutex
+	// the results of auth method checks, keyed by config name, so that
+	// Consul is only queried again when a config changes
+	authMethods map[string]authMethodCheck
+}
+
+type authMethodCheck struct {
+	generation int64
+	problem    string
 }
 
 //+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=gatewayclassconfigs,verbs=get;update;list;watch
 //+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=gatewayclassconfigs/finalizers,verbs=update
+//+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=gatewayclassconfigs/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=policy,resources=podsecuritypolicies,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -68,12 +116,16 @@ func (r *GatewayClassConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 
 	// we're creating or updating
 
+	using, err := r.Client.GatewayClassesUsingConfig(ctx, gcc)
+	if err != nil {
+		logger.Error("failed to get gateway classes using config", "error", err)
+		return ctrl.Result{}, err
+	}
+
 	// evict any class that's referencing an updated config from cache
-	if using, err := r.Client.GatewayClassesUsingConfig(ctx, gcc); err == nil && len(using.Items) > 0 {
-		for _, gc := range using.Items {
-			if err := r.Manager.DeleteGatewayClass(ctx, gc.Name); err != nil {
-				logger.Warn("error evicting cached gateway class referencing config", "error", err)
-			}
+	for _, gc := range using.Items {
+		if err := r.Manager.DeleteGatewayClass(ctx, gc.Name); err != nil {
+			logger.Warn("error evicting cached gateway class referencing config", "error", err)
 		}
 	}
 
@@ -81,12 +133,222 @@ func (r *GatewayClassConfigReconciler) Reconcile(ctx context.Context, req ctrl.R
 		logger.Error("error adding gateway class config finalizer", "error", err)
 		return ctrl.Result{}, err
 	}
+
+	if err := r.syncStatus(ctx, gcc, using.Items); err != nil {
+		logger.Error("error updating gateway class config status", "error", err)
+		return ctrl.Result{}, err
+	}
 	return ctrl.Result{}, nil
 }
 
+// syncStatus records whether the GatewayClassConfig is valid and how many
+// GatewayClasses and Gateways are using it on its status.
+func (r *GatewayClassConfigReconciler) syncStatus(ctx context.Context, gcc *apigwv1alpha1.GatewayClassConfig, classes []gwv1beta1.GatewayClass) error {
+	classNames := make(map[string]struct{})
+	for _, gc := range classes {
+		classNames[gc.Name] = struct{}{}
+	}
+
+	gateways, err := r.Client.GetGatewaysInNamespace(ctx, "")
+	if err != nil {
+		return err
+	}
+	var gatewayCount int32
+	for _, gw := range gateways {
+		if _, ok := classNames[string(gw.Spec.GatewayClassName)]; ok {
+			gatewayCount++
+		}
+	}
+
+	problems, err := r.validate(ctx, gcc)
+	if err != nil {
+		return err
+	}
+
+	condition := metav1.Condition{
+		Type:               apigwv1alpha1.GatewayClassConfigConditionValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             apigwv1alpha1.GatewayClassConfigReasonValid,
+		Message:            "GatewayClassConfig is valid",
+		ObservedGeneration: gcc.Generation,
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = apigwv1alpha1.GatewayClassConfigReasonInvalid
+		condition.Message = strings.Join(problems, "; ")
+	}
+
+	updated := gcc.DeepCopy()
+	updated.Status.GatewayClasses = int32(len(classes))
+	updated.Status.Gateways = gatewayCount
+	meta.SetStatusCondition(&updated.Status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(gcc.Status, updated.Status) {
+		return nil
+	}
+	return r.Client.UpdateStatus(ctx, updated)
+}
+
+// validate returns the problems with a GatewayClassConfig that would keep
+// gateways using it from being deployed.
+func (r *GatewayClassConfigReconciler) validate(ctx context.Context, gcc *apigwv1alpha1.GatewayClassConfig) ([]string, error) {
+	problems := []string{}
+
+	if err := builder.ValidateConfig(&gwv1beta1.Gateway{}, *gcc); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	for _, image := range []string{gcc.Spec.ImageSpec.ConsulAPIGateway, gcc.Spec.ImageSpec.Envoy} {
+		if image != "" && !imageReference.MatchString(image) {
+			problems = append(problems, fmt.Sprintf("invalid image %q", image))
+		}
+	}
+
+	auth := gcc.Spec.ConsulSpec.AuthSpec
+	if problem := r.checkAuthMethod(gcc); problem != "" {
+		problems = append(problems, problem)
+	}
+
+	if auth.Managed && auth.PodSecurityPolicy != "" {
+		psp, err := r.Client.GetPodSecurityPolicy(ctx, auth.PodSecurityPolicy)
+		switch {
+		case errors.Is(err, gatewayclient.ErrPodSecurityPolicyNotSupported):
+			problems = append(problems, fmt.Sprintf("PodSecurityPolicy %q can't be used: %v", auth.PodSecurityPolicy, err))
+		case err != nil:
+			return nil, err
+		case psp == nil:
+			problems = append(problems, fmt.Sprintf("PodSecurityPolicy %q not found", auth.PodSecurityPolicy))
+		}
+	}
+
+	return problems, nil
+}
+
+// checkAuthMethod returns a problem if the Consul auth method a GatewayClassConfig
+// references doesn't exist. Results are cached for each generation of the config
+// since the config is reconciled whenever a gateway using it changes.
+func (r *GatewayClassConfigReconciler) checkAuthMethod(gcc *apigwv1alpha1.GatewayClassConfig) string {
+	auth := gcc.Spec.ConsulSpec.AuthSpec
+	if auth.Method == "" || r.Consul == nil {
+		return ""
+	}
+
+	r.authMethodsMutex.Lock()
+	defer r.authMethodsMutex.Unlock()
+
+	if check, ok := r.authMethods[gcc.Name]; ok && check.generation == gcc.Generation {
+		return check.problem
+	}
+
+	method, _, err := r.Consul.ACL().AuthMethodRead(auth.Method, &api.QueryOptions{Namespace: auth.Namespace})
+	if err != nil {
+		// we may not be allowed to read auth methods, so don't mark
+		// the config as invalid when we can't tell
+		r.Log.Debug("unable to read Consul auth method", "method", auth.Method, "error", err)
+		return ""
+	}
+
+	check := authMethodCheck{generation: gcc.Generation}
+	if method == nil {
+		check.problem = fmt.Sprintf("Consul auth method %q not found", auth.Method)
+	}
+	if r.authMethods == nil {
+		r.authMethods = make(map[string]authMethodCheck)
+	}
+	r.authMethods[gcc.Name] = check
+	return check.problem
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *GatewayClassConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apigwv1alpha1.GatewayClassConfig{}).
+		Watches(
+			&source.Kind{Type: &gwv1beta1.GatewayClass{}},
+			handler.EnqueueRequestsFromMapFunc(gatewayClassToConfigRequests),
+		).
+		Watches(
+			&source.Kind{Type: &gwv1beta1.Gateway{}},
+			r.gatewayConfigHandler(),
+			// only the number of gateways using a config is tracked
+			ctrlbuilder.WithPredicates(gatewayClassChangedPredicate),
+		).
 		Complete(gatewayclient.NewRequeueingMiddleware(r.Log, r))
 }
+
+// gatewayClassChangedPredicate filters gateway events down to those that
+// can change which GatewayClassConfig a gateway uses
+var gatewayClassChangedPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldGateway, ok := e.ObjectOld.(*gwv1beta1.Gateway)
+		if !ok {
+			return false
+		}
+		newGateway, ok := e.ObjectNew.(*gwv1beta1.Gateway)
+		if !ok {
+			return false
+		}
+		return oldGateway.Spec.GatewayClassName != newGateway.Spec.GatewayClassName
+	},
+	GenericFunc: func(event.GenericEvent) bool {
+		return false
+	},
+}
+
+// gatewayConfigHandler enqueues the GatewayClassConfig used by a Gateway's class, when
+// a Gateway moves to another class the config of its previous class is enqueued as
+// well so that its gateway count drops
+func (r *GatewayClassConfigReconciler) gatewayConfigHandler() handler.EventHandler {
+	enqueue := func(queue workqueue.RateLimitingInterface, objects ...client.Object) {
+		for _, object := range objects {
+			for _, request := range r.gatewayToConfigRequests(object) {
+				queue.Add(request)
+			}
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(e event.CreateEvent, queue workqueue.RateLimitingInterface) {
+			enqueue(queue, e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent, queue workqueue.RateLimitingInterface) {
+			enqueue(queue, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent, queue workqueue.RateLimitingInterface) {
+			enqueue(queue, e.Object)
+		},
+	}
+}
+
+// gatewayClassToConfigRequests returns a request for the GatewayClassConfig a
+// GatewayClass references so that the number of classes using it stays current.
+func gatewayClassToConfigRequests(object client.Object) []reconcile.Request {
+	gc, ok := object.(*gwv1beta1.GatewayClass)
+	if !ok {
+		return nil
+	}
+	ref := gc.Spec.ParametersRef
+	if ref == nil || ref.Group != apigwv1alpha1.Group || ref.Kind != apigwv1alpha1.GatewayClassConfigKind {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: ref.Name},
+	}}
+}
+
+// gatewayToConfigRequests returns a request for the GatewayClassConfig used by a
+// Gateway's class so that the number of gateways using it stays current.
+func (r *GatewayClassConfigReconciler) gatewayToConfigRequests(object client.Object) []reconcile.Request {
+	gw, ok := object.(*gwv1beta1.Gateway)
+	if !ok {
+		return nil
+	}
+	gc, err := r.Client.GetGatewayClass(r.Context, types.NamespacedName{Name: string(gw.Spec.GatewayClassName)})
+	if err != nil {
+		r.Log.Error("error fetching gateway class", "error", err)
+		return nil
+	}
+	if gc == nil {
+		return nil
+	}
+	return gatewayClassToConfigRequests(gc)
+}
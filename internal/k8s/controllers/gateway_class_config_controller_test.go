@@ -6,6 +6,11 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,12 +18,17 @@ import (
 	"github.com/stretchr/testify/require"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
+	"github.com/hashicorp/consul/api"
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/hashicorp/consul-api-gateway/internal/consul"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
 	reconcilerMocks "github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/mocks"
 	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
@@ -36,6 +46,31 @@ func TestGatewayClassConfigSetup(t *testing.T) {
 	require.Error(t, (&GatewayClassConfigReconciler{}).SetupWithManager(nil))
 }
 
+// expectConfigStatus expects the status of a GatewayClassConfig to be updated
+// to the given counts and Valid condition, failing the reconcile otherwise
+func expectConfigStatus(client *mocks.MockClient, classes, gateways int32, status meta.ConditionStatus, reason, message string) {
+	client.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, object interface{}) error {
+		actual := object.(*apigwv1alpha1.GatewayClassConfig).Status
+		for i := range actual.Conditions {
+			actual.Conditions[i].LastTransitionTime = meta.Time{}
+		}
+		expected := apigwv1alpha1.GatewayClassConfigStatus{
+			GatewayClasses: classes,
+			Gateways:       gateways,
+			Conditions: []meta.Condition{{
+				Type:    apigwv1alpha1.GatewayClassConfigConditionValid,
+				Status:  status,
+				Reason:  reason,
+				Message: message,
+			}},
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("unexpected status %+v", actual)
+		}
+		return nil
+	})
+}
+
 func TestGatewayClassConfig(t *testing.T) {
 	t.Parallel()
 
@@ -112,12 +147,83 @@ func TestGatewayClassConfig(t *testing.T) {
 			client.EXPECT().GatewayClassesUsingConfig(gomock.Any(), gomock.Any()).Return(&gwv1beta1.GatewayClassList{}, nil)
 			client.EXPECT().EnsureFinalizer(gomock.Any(), gomock.Any(), gatewayClassConfigFinalizer).Return(false, errExpected)
 		},
+	}, {
+		name: "using-config-error",
+		err:  errExpected,
+		expectationCB: func(client *mocks.MockClient, reconciler *reconcilerMocks.MockReconcileManager) {
+			client.EXPECT().GetGatewayClassConfig(gomock.Any(), classConfigName).Return(&apigwv1alpha1.GatewayClassConfig{}, nil)
+			client.EXPECT().GatewayClassesUsingConfig(gomock.Any(), gomock.Any()).Return(nil, errExpected)
+		},
 	}, {
 		name: "create",
 		expectationCB: func(client *mocks.MockClient, reconciler *reconcilerMocks.MockReconcileManager) {
 			client.EXPECT().GetGatewayClassConfig(gomock.Any(), classConfigName).Return(&apigwv1alpha1.GatewayClassConfig{}, nil)
 			client.EXPECT().GatewayClassesUsingConfig(gomock.Any(), gomock.Any()).Return(&gwv1beta1.GatewayClassList{}, nil)
 			client.EXPECT().EnsureFinalizer(gomock.Any(), gomock.Any(), gatewayClassConfigFinalizer).Return(true, nil)
+			client.EXPECT().GetGatewaysInNamespace(gomock.Any(), "").Return(nil, nil)
+			expectConfigStatus(client, 0, 0, meta.ConditionTrue, apigwv1alpha1.GatewayClassConfigReasonValid, "GatewayClassConfig is valid")
+		},
+	}, {
+		name: "status-error",
+		err:  errExpected,
+		expectationCB: func(client *mocks.MockClient, reconciler *reconcilerMocks.MockReconcileManager) {
+			client.EXPECT().GetGatewayClassConfig(gomock.Any(), classConfigName).Return(&apigwv1alpha1.GatewayClassConfig{}, nil)
+			client.EXPECT().GatewayClassesUsingConfig(gomock.Any(), gomock.Any()).Return(&gwv1beta1.GatewayClassList{}, nil)
+			client.EXPECT().EnsureFinalizer(gomock.Any(), gomock.Any(), gatewayClassConfigFinalizer).Return(true, nil)
+			client.EXPECT().GetGatewaysInNamespace(gomock.Any(), "").Return(nil, nil)
+			client.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Return(errExpected)
+		},
+	}, {
+		name: "status-unchanged",
+		expectationCB: func(client *mocks.MockClient, reconciler *reconcilerMocks.MockReconcileManager) {
+			client.EXPECT().GetGatewayClassConfig(gomock.Any(), classConfigName).Return(&apigwv1alpha1.GatewayClassConfig{
+				Status: apigwv1alpha1.GatewayClassConfigStatus{
+					Conditions: []meta.Condition{{
+						Type:    apigwv1alpha1.GatewayClassConfigConditionValid,
+						Status:  meta.ConditionTrue,
+						Reason:  apigwv1alpha1.GatewayClassConfigReasonValid,
+						Message: "GatewayClassConfig is valid",
+					}},
+				},
+			}, nil)
+			client.EXPECT().GatewayClassesUsingConfig(gomock.Any(), gomock.Any()).Return(&gwv1beta1.GatewayClassList{}, nil)
+			client.EXPECT().EnsureFinalizer(gomock.Any(), gomock.Any(), gatewayClassConfigFinalizer).Return(true, nil)
+			client.EXPECT().GetGatewaysInNamespace(gomock.Any(), "").Return(nil, nil)
+		},
+	}, {
+		name: "invalid",
+		expectationCB: func(client *mocks.MockClient, reconciler *reconcilerMocks.MockReconcileManager) {
+			client.EXPECT().GetGatewayClassConfig(gomock.Any(), classConfigName).Return(&apigwv1alpha1.GatewayClassConfig{
+				Spec: apigwv1alpha1.GatewayClassConfigSpec{
+					ImageSpec: apigwv1alpha1.ImageSpec{Envoy: "not an image!"},
+					ConsulSpec: apigwv1alpha1.ConsulSpec{
+						AuthSpec: apigwv1alpha1.AuthSpec{Managed: true, PodSecurityPolicy: "psp"},
+					},
+				},
+			}, nil)
+			client.EXPECT().GatewayClassesUsingConfig(gomock.Any(), gomock.Any()).Return(&gwv1beta1.GatewayClassList{}, nil)
+			client.EXPECT().EnsureFinalizer(gomock.Any(), gomock.Any(), gatewayClassConfigFinalizer).Return(true, nil)
+			client.EXPECT().GetGatewaysInNamespace(gomock.Any(), "").Return(nil, nil)
+			client.EXPECT().GetPodSecurityPolicy(gomock.Any(), "psp").Return(nil, nil)
+			expectConfigStatus(client, 0, 0, meta.ConditionFalse, apigwv1alpha1.GatewayClassConfigReasonInvalid,
+				`invalid image "not an image!"; PodSecurityPolicy "psp" not found`)
+		},
+	}, {
+		name: "psp-not-supported",
+		expectationCB: func(client *mocks.MockClient, reconciler *reconcilerMocks.MockReconcileManager) {
+			client.EXPECT().GetGatewayClassConfig(gomock.Any(), classConfigName).Return(&apigwv1alpha1.GatewayClassConfig{
+				Spec: apigwv1alpha1.GatewayClassConfigSpec{
+					ConsulSpec: apigwv1alpha1.ConsulSpec{
+						AuthSpec: apigwv1alpha1.AuthSpec{Managed: true, PodSecurityPolicy: "psp"},
+					},
+				},
+			}, nil)
+			client.EXPECT().GatewayClassesUsingConfig(gomock.Any(), gomock.Any()).Return(&gwv1beta1.GatewayClassList{}, nil)
+			client.EXPECT().EnsureFinalizer(gomock.Any(), gomock.Any(), gatewayClassConfigFinalizer).Return(true, nil)
+			client.EXPECT().GetGatewaysInNamespace(gomock.Any(), "").Return(nil, nil)
+			client.EXPECT().GetPodSecurityPolicy(gomock.Any(), "psp").Return(nil, gatewayclient.ErrPodSecurityPolicyNotSupported)
+			expectConfigStatus(client, 0, 0, meta.ConditionFalse, apigwv1alpha1.GatewayClassConfigReasonInvalid,
+				`PodSecurityPolicy "psp" can't be used: PodSecurityPolicies are not supported by the cluster`)
 		},
 	}, {
 		name: "update-in-use",
@@ -139,6 +245,12 @@ func TestGatewayClassConfig(t *testing.T) {
 			}, nil)
 			reconciler.EXPECT().DeleteGatewayClass(gomock.Any(), gcUsing.Name).Return(nil)
 			client.EXPECT().EnsureFinalizer(gomock.Any(), gomock.Any(), gatewayClassConfigFinalizer).Return(true, nil)
+			client.EXPECT().GetGatewaysInNamespace(gomock.Any(), "").Return([]gwv1beta1.Gateway{{
+				Spec: gwv1beta1.GatewaySpec{GatewayClassName: "class"},
+			}, {
+				Spec: gwv1beta1.GatewaySpec{GatewayClassName: "other-class"},
+			}}, nil)
+			expectConfigStatus(client, 1, 1, meta.ConditionTrue, apigwv1alpha1.GatewayClassConfigReasonValid, "GatewayClassConfig is valid")
 		},
 	}} {
 		t.Run(test.name, func(t *testing.T) {
@@ -169,3 +281,143 @@ func TestGatewayClassConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGatewayClassToConfigRequests(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, gatewayClassToConfigRequests(&gwv1beta1.GatewayClass{}))
+	require.Empty(t, gatewayClassToConfigRequests(&gwv1beta1.GatewayClass{
+		Spec: gwv1beta1.GatewayClassSpec{
+			ParametersRef: &gwv1beta1.ParametersReference{Group: "other", Kind: "Config", Name: "config"},
+		},
+	}))
+	require.Equal(t, []reconcile.Request{{
+		NamespacedName: types.NamespacedName{Name: "config"},
+	}}, gatewayClassToConfigRequests(&gwv1beta1.GatewayClass{
+		Spec: gwv1beta1.GatewayClassSpec{
+			ParametersRef: &gwv1beta1.ParametersReference{
+				Group: apigwv1alpha1.Group,
+				Kind:  apigwv1alpha1.GatewayClassConfigKind,
+				Name:  "config",
+			},
+		},
+	}))
+}
+
+func TestImageReference(t *testing.T) {
+	t.Parallel()
+
+	for _, image := range []string{
+		"envoyproxy/envoy:v1.24-latest",
+		"hashicorp/consul-api-gateway",
+		"registry.example.com:5000/team/consul-api-gateway:0.6.0",
+		"envoy@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+	} {
+		require.True(t, imageReference.MatchString(image), image)
+	}
+	for _, image := range []string{
+		"not an image!",
+		"Envoy",
+		"envoy:",
+		"envoy@sha256:beef",
+	} {
+		require.False(t, imageReference.MatchString(image), image)
+	}
+}
+
+func TestGatewayClassChangedPredicate(t *testing.T) {
+	t.Parallel()
+
+	gateway := func(class string) *gwv1beta1.Gateway {
+		return &gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{GatewayClassName: gwv1beta1.ObjectName(class)}}
+	}
+
+	require.True(t, gatewayClassChangedPredicate.Create(event.CreateEvent{Object: gateway("class")}))
+	require.True(t, gatewayClassChangedPredicate.Delete(event.DeleteEvent{Object: gateway("class")}))
+	require.False(t, gatewayClassChangedPredicate.Generic(event.GenericEvent{Object: gateway("class")}))
+	require.False(t, gatewayClassChangedPredicate.Update(event.UpdateEvent{ObjectOld: gateway("class"), ObjectNew: gateway("class")}))
+	require.True(t, gatewayClassChangedPredicate.Update(event.UpdateEvent{ObjectOld: gateway("class"), ObjectNew: gateway("other")}))
+}
+
+func TestGatewayClassConfigGatewayHandler(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockClient(ctrl)
+	class := func(name string) *gwv1beta1.GatewayClass {
+		return &gwv1beta1.GatewayClass{
+			Spec: gwv1beta1.GatewayClassSpec{
+				ParametersRef: &gwv1beta1.ParametersReference{
+					Group: apigwv1alpha1.Group,
+					Kind:  apigwv1alpha1.GatewayClassConfigKind,
+					Name:  name + "-config",
+				},
+			},
+		}
+	}
+	client.EXPECT().GetGatewayClass(gomock.Any(), types.NamespacedName{Name: "old"}).Return(class("old"), nil)
+	client.EXPECT().GetGatewayClass(gomock.Any(), types.NamespacedName{Name: "new"}).Return(class("new"), nil)
+
+	controller := &GatewayClassConfigReconciler{
+		Context: context.Background(),
+		Client:  client,
+		Log:     hclog.NewNullLogger(),
+	}
+	gateway := func(class string) *gwv1beta1.Gateway {
+		return &gwv1beta1.Gateway{Spec: gwv1beta1.GatewaySpec{GatewayClassName: gwv1beta1.ObjectName(class)}}
+	}
+
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	// the configs of both the previous and the new class are enqueued
+	controller.gatewayConfigHandler().Update(event.UpdateEvent{ObjectOld: gateway("old"), ObjectNew: gateway("new")}, queue)
+	require.Equal(t, 2, queue.Len())
+	enqueued := []interface{}{}
+	for queue.Len() > 0 {
+		request, _ := queue.Get()
+		enqueued = append(enqueued, request)
+		queue.Done(request)
+	}
+	require.ElementsMatch(t, []interface{}{
+		reconcile.Request{NamespacedName: types.NamespacedName{Name: "old-config"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Name: "new-config"}},
+	}, enqueued)
+}
+
+func TestGatewayClassConfigCheckAuthMethod(t *testing.T) {
+	t.Parallel()
+
+	var reads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&reads, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	consulClient, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+	controller := &GatewayClassConfigReconciler{
+		Log:    hclog.NewNullLogger(),
+		Consul: consul.NewTestClient(consulClient),
+	}
+
+	gcc := &apigwv1alpha1.GatewayClassConfig{
+		ObjectMeta: meta.ObjectMeta{Name: "config", Generation: 1},
+		Spec: apigwv1alpha1.GatewayClassConfigSpec{
+			ConsulSpec: apigwv1alpha1.ConsulSpec{
+				AuthSpec: apigwv1alpha1.AuthSpec{Method: "method"},
+			},
+		},
+	}
+	require.Equal(t, `Consul auth method "method" not found`, controller.checkAuthMethod(gcc))
+	require.Equal(t, `Consul auth method "method" not found`, controller.checkAuthMethod(gcc))
+	require.Equal(t, int32(1), atomic.LoadInt32(&reads))
+
+	// a new generation of the config is checked again
+	gcc.Generation = 2
+	require.Equal(t, `Consul auth method "method" not found`, controller.checkAuthMethod(gcc))
+	require.Equal(t, int32(2), atomic.LoadInt32(&reads))
+}
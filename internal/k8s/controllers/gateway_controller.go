@@ -7,12 +7,16 @@ import (
 	"context"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -25,6 +29,7 @@ import (
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
 // var ErrPodNotCreated = errors.New("pod not yet created for gateway")
@@ -36,6 +41,13 @@ type GatewayReconciler struct {
 	Log            hclog.Logger
 	ControllerName string
 	Manager        reconciler.ReconcileManager
+
+	// WatchCertManagerCertificates watches the cert-manager Certificates
+	// owned by Gateways, it should only be set if cert-manager is installed
+	WatchCertManagerCertificates bool
+	// CertificateEvents, if set, enqueues gateways whose listener certificates
+	// are nearing or have reached their expiry
+	CertificateEvents <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;create;update;patch;delete
@@ -46,12 +58,17 @@ type GatewayReconciler struct {
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=list;watch
-//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=list;get;create;update;watch
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=list;get;create;update;watch;delete
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=list;get;create;update;watch;delete
 //+kubebuilder:rbac:groups=core,resources=services,verbs=list;get;create;update;watch
 //+kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=list;get;create;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=create;update;get;list;watch
-//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=list;get;create;watch
+//+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=list;get;create;update;watch
 //+kubebuilder:rbac:groups=policy,resources=podsecuritypolicies,verbs=use
+//+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=use
+//+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=list;get;create;update;delete;watch
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=list;get;create;update;delete;watch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=list;get;create;update;delete;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -85,22 +102,40 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	predicate, _ := predicate.LabelSelectorPredicate(
+	managedPredicate, _ := predicate.LabelSelectorPredicate(
 		*metav1.SetAsLabelSelector(map[string]string{
 			utils.ManagedLabel: "true",
 		}),
 	)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	controller := ctrl.NewControllerManagedBy(mgr).
 		For(&gwv1beta1.Gateway{}).
 		Owns(&appsv1.Deployment{}).
+		Owns(&appsv1.DaemonSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ServiceAccount{}).
 		Owns(&corev1.Secret{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&policyv1.PodDisruptionBudget{})
+
+	if r.WatchCertManagerCertificates {
+		certificate := &unstructured.Unstructured{}
+		certificate.SetGroupVersionKind(utils.CertManagerCertificateGVK)
+		controller = controller.Owns(certificate)
+	}
+
+	if r.CertificateEvents != nil {
+		controller = controller.Watches(
+			&source.Channel{Source: r.CertificateEvents},
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+
+	return controller.
 		Watches(
 			&source.Kind{Type: &corev1.Pod{}},
 			handler.EnqueueRequestsFromMapFunc(podToGatewayRequest),
-			builder.WithPredicates(predicate),
+			builder.WithPredicates(managedPredicate),
 		).
 		Watches(
 			&source.Kind{Type: &gwv1alpha2.ReferenceGrant{}},
@@ -110,6 +145,20 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&source.Kind{Type: &gwv1alpha2.ReferencePolicy{}},
 			handler.EnqueueRequestsFromMapFunc(r.referencePolicyToGatewayRequests),
 		).
+		Watches(
+			&source.Kind{Type: &apigwv1alpha1.VaultCertificate{}},
+			handler.EnqueueRequestsFromMapFunc(r.vaultCertificateToGatewayRequests),
+		).
+		Watches(
+			&source.Kind{Type: &apigwv1alpha1.GatewayClassConfig{}},
+			handler.EnqueueRequestsFromMapFunc(r.gatewayClassConfigToGatewayRequests),
+			// status updates don't change the config gateways run
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
+		Watches(
+			&source.Kind{Type: &apigwv1alpha1.GatewayConfig{}},
+			handler.EnqueueRequestsFromMapFunc(r.gatewayConfigToGatewayRequests),
+		).
 		Complete(gatewayclient.NewRequeueingMiddleware(r.Log, r))
 }
 
@@ -173,3 +222,115 @@ func (r *GatewayReconciler) getGatewaysAffectedByReferenceGrant(refGrant *gwv1al
 
 	return matches
 }
+
+// vaultCertificateToGatewayRequests returns a request for every Gateway with a
+// listener that references the given VaultCertificate.
+func (r *GatewayReconciler) vaultCertificateToGatewayRequests(object client.Object) []reconcile.Request {
+	gateways, err := r.Client.GetGatewaysInNamespace(r.Context, "")
+	if err != nil {
+		r.Log.Error("error fetching gateways", "error", err)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, gw := range gateways {
+		if gatewayReferencesVaultCertificate(gw, object) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      gw.Name,
+					Namespace: gw.Namespace,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// gatewayClassConfigToGatewayRequests returns a request for every Gateway with a
+// class that uses the given GatewayClassConfig, so that their statuses reflect
+// the latest config and they can be re-rendered from it.
+func (r *GatewayReconciler) gatewayClassConfigToGatewayRequests(object client.Object) []reconcile.Request {
+	config, ok := object.(*apigwv1alpha1.GatewayClassConfig)
+	if !ok {
+		return nil
+	}
+
+	classes, err := r.Client.GatewayClassesUsingConfig(r.Context, config)
+	if err != nil {
+		r.Log.Error("error fetching gateway classes", "error", err)
+		return nil
+	}
+	classNames := make(map[string]struct{})
+	for _, class := range classes.Items {
+		classNames[class.Name] = struct{}{}
+	}
+	if len(classNames) == 0 {
+		return nil
+	}
+
+	gateways, err := r.Client.GetGatewaysInNamespace(r.Context, "")
+	if err != nil {
+		r.Log.Error("error fetching gateways", "error", err)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, gw := range gateways {
+		if _, ok := classNames[string(gw.Spec.GatewayClassName)]; ok {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      gw.Name,
+					Namespace: gw.Namespace,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+// gatewayConfigToGatewayRequests returns a request for every Gateway
+// that overrides its class config with the given GatewayConfig.
+func (r *GatewayReconciler) gatewayConfigToGatewayRequests(object client.Object) []reconcile.Request {
+	gateways, err := r.Client.GetGatewaysInNamespace(r.Context, object.GetNamespace())
+	if err != nil {
+		r.Log.Error("error fetching gateways", "error", err)
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, gw := range gateways {
+		if gw.Annotations[apigwv1alpha1.GatewayConfigAnnotation] == object.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      gw.Name,
+					Namespace: gw.Namespace,
+				},
+			})
+		}
+	}
+	return requests
+}
+
+func gatewayReferencesVaultCertificate(gateway gwv1beta1.Gateway, certificate client.Object) bool {
+	for _, listener := range gateway.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			if ref.Group == nil || string(*ref.Group) != apigwv1alpha1.Group {
+				continue
+			}
+			if ref.Kind == nil || string(*ref.Kind) != apigwv1alpha1.VaultCertificateKind {
+				continue
+			}
+			namespace := gateway.Namespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			if string(ref.Name) == certificate.GetName() && namespace == certificate.GetNamespace() {
+				return true
+			}
+		}
+	}
+	return false
+}
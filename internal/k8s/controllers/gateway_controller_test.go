@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
 	reconcilerMocks "github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/mocks"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 	"github.com/hashicorp/go-hclog"
 )
 
@@ -228,3 +229,181 @@ func TestGatewayReferencePolicyToGatewayRequests(t *testing.T) {
 		},
 	}}, requests)
 }
+
+func TestGatewayVaultCertificateToGatewayRequests(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	group := gwv1beta1.Group(apigwv1alpha1.Group)
+	kind := gwv1beta1.Kind(apigwv1alpha1.VaultCertificateKind)
+	certificateNamespace := gwv1beta1.Namespace("namespace1")
+
+	gatewayFor := func(namespace string, ref gwv1beta1.SecretObjectReference) *gwv1beta1.Gateway {
+		return &gwv1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway",
+				Namespace: namespace,
+			},
+			Spec: gwv1beta1.GatewaySpec{
+				Listeners: []gwv1beta1.Listener{{
+					TLS: &gwv1beta1.GatewayTLSConfig{
+						CertificateRefs: []gwv1beta1.SecretObjectReference{ref},
+					},
+				}},
+			},
+		}
+	}
+
+	certificate := &apigwv1alpha1.VaultCertificate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "certificate",
+			Namespace: "namespace1",
+		},
+	}
+
+	client := gatewayclient.NewTestClient(
+		nil,
+		// same namespace reference
+		gatewayFor("namespace1", gwv1beta1.SecretObjectReference{Group: &group, Kind: &kind, Name: "certificate"}),
+		// cross-namespace reference
+		gatewayFor("namespace2", gwv1beta1.SecretObjectReference{Group: &group, Kind: &kind, Name: "certificate", Namespace: &certificateNamespace}),
+		// reference to a secret with the same name
+		gatewayFor("namespace3", gwv1beta1.SecretObjectReference{Name: "certificate", Namespace: &certificateNamespace}),
+		certificate,
+	)
+
+	controller := &GatewayReconciler{
+		Context:        context.Background(),
+		Client:         client,
+		Log:            hclog.NewNullLogger(),
+		ControllerName: mockControllerName,
+		Manager:        reconcilerMocks.NewMockReconcileManager(ctrl),
+	}
+
+	requests := controller.vaultCertificateToGatewayRequests(certificate)
+
+	assert.ElementsMatch(t, []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      "gateway",
+			Namespace: "namespace1",
+		},
+	}, {
+		NamespacedName: types.NamespacedName{
+			Name:      "gateway",
+			Namespace: "namespace2",
+		},
+	}}, requests)
+}
+
+func TestGatewayClassConfigToGatewayRequests(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gatewayFor := func(namespace, className string) *gwv1beta1.Gateway {
+		return &gwv1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway",
+				Namespace: namespace,
+			},
+			Spec: gwv1beta1.GatewaySpec{
+				GatewayClassName: gwv1beta1.ObjectName(className),
+			},
+		}
+	}
+	classFor := func(name, configName string) *gwv1beta1.GatewayClass {
+		return &gwv1beta1.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gwv1beta1.GatewayClassSpec{
+				ControllerName: mockControllerName,
+				ParametersRef: &gwv1beta1.ParametersReference{
+					Group: apigwv1alpha1.Group,
+					Kind:  apigwv1alpha1.GatewayClassConfigKind,
+					Name:  configName,
+				},
+			},
+		}
+	}
+
+	config := &apigwv1alpha1.GatewayClassConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "config"},
+	}
+
+	client := gatewayclient.NewTestClient(
+		nil,
+		classFor("class", "config"),
+		classFor("other-class", "other-config"),
+		gatewayFor("namespace1", "class"),
+		gatewayFor("namespace2", "other-class"),
+		config,
+	)
+
+	controller := &GatewayReconciler{
+		Context:        context.Background(),
+		Client:         client,
+		Log:            hclog.NewNullLogger(),
+		ControllerName: mockControllerName,
+		Manager:        reconcilerMocks.NewMockReconcileManager(ctrl),
+	}
+
+	requests := controller.gatewayClassConfigToGatewayRequests(config)
+
+	assert.Equal(t, []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      "gateway",
+			Namespace: "namespace1",
+		},
+	}}, requests)
+}
+
+func TestGatewayConfigToGatewayRequests(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gatewayFor := func(name, namespace, configName string) *gwv1beta1.Gateway {
+		return &gwv1beta1.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Annotations: map[string]string{apigwv1alpha1.GatewayConfigAnnotation: configName},
+			},
+		}
+	}
+
+	config := &apigwv1alpha1.GatewayConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "config",
+			Namespace: "namespace1",
+		},
+	}
+
+	client := gatewayclient.NewTestClient(
+		nil,
+		gatewayFor("gateway", "namespace1", "config"),
+		gatewayFor("other-config", "namespace1", "other-config"),
+		gatewayFor("other-namespace", "namespace2", "config"),
+		config,
+	)
+
+	controller := &GatewayReconciler{
+		Context:        context.Background(),
+		Client:         client,
+		Log:            hclog.NewNullLogger(),
+		ControllerName: mockControllerName,
+		Manager:        reconcilerMocks.NewMockReconcileManager(ctrl),
+	}
+
+	requests := controller.gatewayConfigToGatewayRequests(config)
+
+	assert.Equal(t, []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      "gateway",
+			Namespace: "namespace1",
+		},
+	}}, requests)
+}
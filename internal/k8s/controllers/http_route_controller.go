@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
 // HTTPRouteReconciler reconciles a HTTPRoute object
@@ -28,11 +30,14 @@ type HTTPRouteReconciler struct {
 	Log            hclog.Logger
 	ControllerName string
 	Manager        reconciler.ReconcileManager
+	// CatalogEvents, if set, enqueues routes whose Consul services changed
+	CatalogEvents <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=endpoints,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -62,7 +67,7 @@ func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&gwv1alpha2.HTTPRoute{}).
 		Watches(
 			&source.Kind{Type: &gwv1alpha2.ReferenceGrant{}},
@@ -76,14 +81,26 @@ func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&source.Kind{Type: &corev1.Service{}},
 			handler.EnqueueRequestsFromMapFunc(r.serviceToRouteRequests),
 		).
-		Complete(gatewayclient.NewRequeueingMiddleware(r.Log, r))
+		Watches(
+			&source.Kind{Type: &apigwv1alpha1.MeshService{}},
+			handler.EnqueueRequestsFromMapFunc(r.serviceToRouteRequests),
+		).
+		Watches(
+			&source.Kind{Type: &apigwv1alpha1.ExternalService{}},
+			handler.EnqueueRequestsFromMapFunc(r.serviceToRouteRequests),
+		)
+	if r.CatalogEvents != nil {
+		builder = builder.Watches(
+			&source.Channel{Source: r.CatalogEvents},
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+	return builder.Complete(gatewayclient.NewRequeueingMiddleware(r.Log, r))
 }
 
 // serviceToRouteRequests builds a list of HTTPRoutes that need to be reconciled
-// based on changes to a Service
-func (r *HTTPRouteReconciler) serviceToRouteRequests(object client.Object) []reconcile.Request {
-	service := object.(*corev1.Service)
-
+// based on changes to a Service, MeshService or ExternalService
+func (r *HTTPRouteReconciler) serviceToRouteRequests(service client.Object) []reconcile.Request {
 	routes := r.getRoutesAffectedByService(service)
 	var requests []reconcile.Request
 
@@ -101,8 +118,8 @@ func (r *HTTPRouteReconciler) serviceToRouteRequests(object client.Object) []rec
 
 // getRoutesAffectedByService retrieves all HTTPRoutes potentially impacted
 // by the Service being modified. This is done by filtering to HTTPRoutes that
-// have a backendRef matching the Service's namespace and name.
-func (r *HTTPRouteReconciler) getRoutesAffectedByService(service *corev1.Service) []gwv1alpha2.HTTPRoute {
+// have a backendRef matching the Service's kind, namespace and name.
+func (r *HTTPRouteReconciler) getRoutesAffectedByService(service client.Object) []gwv1alpha2.HTTPRoute {
 	var matches []gwv1alpha2.HTTPRoute
 
 	routes, err := r.Client.GetHTTPRoutes(r.Context)
@@ -116,15 +133,9 @@ func (r *HTTPRouteReconciler) getRoutesAffectedByService(service *corev1.Service
 	nextRoute:
 		for _, rule := range route.Spec.Rules {
 			for _, ref := range rule.BackendRefs {
-				// The BackendRef may or may not specify a namespace, defaults to route's namespace
-				refNamespace := route.Namespace
-				if ref.Namespace != nil {
-					refNamespace = string(*ref.Namespace)
-				}
-
-				// If this BackendRef matches the service namespace + name, then this HTTPRoute
+				// If this BackendRef matches the service kind, namespace + name, then this HTTPRoute
 				// is affected. No need to check other refs, skip ahead to next HTTPRoute.
-				if refNamespace == service.Namespace && ref.Name == gwv1alpha2.ObjectName(service.Name) {
+				if backendRefMatches(route.Namespace, ref.BackendObjectReference, service) {
 					matches = append(matches, route)
 					break nextRoute
 				}
@@ -135,6 +146,40 @@ func (r *HTTPRouteReconciler) getRoutesAffectedByService(service *corev1.Service
 	return matches
 }
 
+// backendRefMatches returns whether a route's backendRef references the given
+// Service, MeshService or ExternalService, defaulting to a Service in the route's namespace
+func backendRefMatches(routeNamespace string, ref gwv1alpha2.BackendObjectReference, object client.Object) bool {
+	group, kind := corev1.GroupName, "Service"
+	if ref.Group != nil {
+		group = string(*ref.Group)
+	}
+	if ref.Kind != nil {
+		kind = string(*ref.Kind)
+	}
+	namespace := routeNamespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+
+	switch object.(type) {
+	case *corev1.Service:
+		if group != corev1.GroupName || kind != "Service" {
+			return false
+		}
+	case *apigwv1alpha1.MeshService:
+		if group != apigwv1alpha1.GroupVersion.Group || kind != apigwv1alpha1.MeshServiceKind {
+			return false
+		}
+	case *apigwv1alpha1.ExternalService:
+		if group != apigwv1alpha1.GroupVersion.Group || kind != apigwv1alpha1.ExternalServiceKind {
+			return false
+		}
+	default:
+		return false
+	}
+	return namespace == object.GetNamespace() && string(ref.Name) == object.GetName()
+}
+
 func (r *HTTPRouteReconciler) referenceGrantToRouteRequests(object client.Object) []reconcile.Request {
 	return r.getRouteRequestsFromReferenceGrant(object.(*gwv1alpha2.ReferenceGrant))
 }
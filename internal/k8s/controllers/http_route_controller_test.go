@@ -21,6 +21,7 @@ import (
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
 	reconcilerMocks "github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/mocks"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
 var (
@@ -168,6 +169,35 @@ func TestHTTPRouteServiceToRouteRequests(t *testing.T) {
 	}, requests)
 }
 
+func TestBackendRefMatches(t *testing.T) {
+	t.Parallel()
+
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "echo"}}
+	meshService := &apigwv1alpha1.MeshService{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "echo"}}
+	meshServiceGroup := gwv1alpha2.Group(apigwv1alpha1.GroupVersion.Group)
+	meshServiceKind := gwv1alpha2.Kind(apigwv1alpha1.MeshServiceKind)
+	otherNamespace := gwv1alpha2.Namespace("other")
+
+	serviceRef := gwv1alpha2.BackendObjectReference{Name: "echo"}
+	meshServiceRef := gwv1alpha2.BackendObjectReference{Group: &meshServiceGroup, Kind: &meshServiceKind, Name: "echo"}
+
+	require.True(t, backendRefMatches("namespace", serviceRef, service))
+	require.False(t, backendRefMatches("other", serviceRef, service))
+	require.False(t, backendRefMatches("namespace", serviceRef, meshService))
+	require.True(t, backendRefMatches("namespace", meshServiceRef, meshService))
+	require.False(t, backendRefMatches("namespace", meshServiceRef, service))
+
+	externalService := &apigwv1alpha1.ExternalService{ObjectMeta: metav1.ObjectMeta{Namespace: "namespace", Name: "echo"}}
+	externalServiceKind := gwv1alpha2.Kind(apigwv1alpha1.ExternalServiceKind)
+	externalServiceRef := gwv1alpha2.BackendObjectReference{Group: &meshServiceGroup, Kind: &externalServiceKind, Name: "echo"}
+	require.True(t, backendRefMatches("namespace", externalServiceRef, externalService))
+	require.False(t, backendRefMatches("namespace", externalServiceRef, meshService))
+	require.False(t, backendRefMatches("namespace", meshServiceRef, externalService))
+
+	meshServiceRef.Namespace = &otherNamespace
+	require.False(t, backendRefMatches("namespace", meshServiceRef, meshService))
+}
+
 func TestHTTPRouteReferenceGrantToRouteRequests(t *testing.T) {
 	t.Parallel()
 
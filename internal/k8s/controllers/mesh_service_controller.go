@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/common"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
+)
+
+const (
+	// meshServiceResolveInterval is how often we retry resolving a MeshService
+	// whose Consul service could not be found
+	meshServiceResolveInterval = 30 * time.Second
+)
+
+// MeshServiceReconciler reconciles a MeshService object
+type MeshServiceReconciler struct {
+	Client                gatewayclient.Client
+	Log                   hclog.Logger
+	Resolver              service.BackendResolver
+	ConsulNamespaceMapper common.ConsulNamespaceMapper
+	ConsulPartition       string
+}
+
+//+kubebuilder:rbac:groups=api-gateway.consul.hashicorp.com,resources=meshservices/status,verbs=get;update;patch
+
+// Reconcile resolves the Consul service referenced by a MeshService and records
+// the result on the MeshService's status.
+func (r *MeshServiceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.With("mesh-service", req.NamespacedName)
+
+	meshService, err := r.Client.GetMeshService(ctx, req.NamespacedName)
+	if err != nil {
+		logger.Error("failed to get mesh service", "error", err)
+		return ctrl.Result{}, err
+	}
+
+	if meshService == nil {
+		// we've been deleted, no-op
+		return ctrl.Result{}, nil
+	}
+
+	result := ctrl.Result{}
+	condition := metav1.Condition{
+		Type:               apigwv1alpha1.MeshServiceConditionResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             apigwv1alpha1.MeshServiceReasonResolved,
+		Message:            "Resolved Consul service",
+		ObservedGeneration: meshService.Generation,
+	}
+
+	// record the Consul namespace the service was resolved in, falling back to
+	// the one the MeshService's namespace maps to when it can't be found
+	consulNamespace := r.ConsulNamespaceMapper(meshService.Namespace)
+	resolved, err := r.Resolver.ResolveMeshService(ctx, meshService)
+	if err != nil {
+		var resolutionError service.ResolutionError
+		if !errors.As(err, &resolutionError) {
+			logger.Error("failed to resolve mesh service", "error", err)
+			return ctrl.Result{}, err
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = apigwv1alpha1.MeshServiceReasonNotFound
+		condition.Message = resolutionError.Error()
+		// the service may still get registered, check back in a bit
+		result.RequeueAfter = meshServiceResolveInterval
+	} else if resolved.Consul != nil {
+		consulNamespace = resolved.Consul.Namespace
+	}
+
+	updated := meshService.DeepCopy()
+	updated.Status.ConsulNamespace = consulNamespace
+	updated.Status.ConsulPartition = r.ConsulPartition
+	meta.SetStatusCondition(&updated.Status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(meshService.Status, updated.Status) {
+		return result, nil
+	}
+
+	if err := r.Client.UpdateStatus(ctx, updated); err != nil {
+		logger.Error("error updating mesh service status", "error", err)
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *MeshServiceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apigwv1alpha1.MeshService{}).
+		Complete(gatewayclient.NewRequeueingMiddleware(r.Log, r))
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
+	serviceMocks "github.com/hashicorp/consul-api-gateway/internal/k8s/service/mocks"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
+)
+
+var meshServiceName = types.NamespacedName{
+	Name:      "mesh-service",
+	Namespace: "default",
+}
+
+func TestMeshServiceSetup(t *testing.T) {
+	require.Error(t, (&MeshServiceReconciler{}).SetupWithManager(nil))
+}
+
+func TestMeshService(t *testing.T) {
+	t.Parallel()
+
+	resolvedStatus := apigwv1alpha1.MeshServiceStatus{
+		ConsulNamespace: "consul-default",
+		ConsulPartition: "partition",
+	}
+	meta.SetStatusCondition(&resolvedStatus.Conditions, metav1.Condition{
+		Type:    apigwv1alpha1.MeshServiceConditionResolved,
+		Status:  metav1.ConditionTrue,
+		Reason:  apigwv1alpha1.MeshServiceReasonResolved,
+		Message: "Resolved Consul service",
+	})
+
+	expectNamespacedStatus := func(namespace string, status metav1.ConditionStatus, reason string) func(ctx context.Context, object client.Object) error {
+		return func(ctx context.Context, object client.Object) error {
+			meshService := object.(*apigwv1alpha1.MeshService)
+			require.Equal(t, namespace, meshService.Status.ConsulNamespace)
+			require.Equal(t, "partition", meshService.Status.ConsulPartition)
+			condition := meta.FindStatusCondition(meshService.Status.Conditions, apigwv1alpha1.MeshServiceConditionResolved)
+			require.NotNil(t, condition)
+			require.Equal(t, status, condition.Status)
+			require.Equal(t, reason, condition.Reason)
+			return nil
+		}
+	}
+	expectStatus := func(status metav1.ConditionStatus, reason string) func(ctx context.Context, object client.Object) error {
+		return expectNamespacedStatus("consul-default", status, reason)
+	}
+
+	for _, test := range []struct {
+		name          string
+		err           error
+		result        reconcile.Result
+		expectationCB func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver)
+	}{{
+		name: "get-error",
+		err:  errExpected,
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(nil, errExpected)
+		},
+	}, {
+		name: "deleted",
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(nil, nil)
+		},
+	}, {
+		name: "resolve-error",
+		err:  errExpected,
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(&apigwv1alpha1.MeshService{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}, nil)
+			resolver.EXPECT().ResolveMeshService(gomock.Any(), gomock.Any()).Return(nil, errExpected)
+		},
+	}, {
+		name: "resolved",
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(&apigwv1alpha1.MeshService{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}, nil)
+			resolver.EXPECT().ResolveMeshService(gomock.Any(), gomock.Any()).Return(&service.ResolvedReference{}, nil)
+			client.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(expectStatus(metav1.ConditionTrue, apigwv1alpha1.MeshServiceReasonResolved))
+		},
+	}, {
+		name: "resolved-namespace",
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(&apigwv1alpha1.MeshService{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}, nil)
+			resolver.EXPECT().ResolveMeshService(gomock.Any(), gomock.Any()).Return(service.NewConsulServiceReference(&service.ConsulService{
+				Namespace: "resolved",
+				Name:      "mesh-service",
+			}), nil)
+			client.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(expectNamespacedStatus("resolved", metav1.ConditionTrue, apigwv1alpha1.MeshServiceReasonResolved))
+		},
+	}, {
+		name: "resolved-unchanged",
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(&apigwv1alpha1.MeshService{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				Status:     resolvedStatus,
+			}, nil)
+			resolver.EXPECT().ResolveMeshService(gomock.Any(), gomock.Any()).Return(&service.ResolvedReference{}, nil)
+		},
+	}, {
+		name:   "not-found",
+		result: ctrl.Result{RequeueAfter: meshServiceResolveInterval},
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(&apigwv1alpha1.MeshService{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+				Status:     resolvedStatus,
+			}, nil)
+			resolver.EXPECT().ResolveMeshService(gomock.Any(), gomock.Any()).Return(nil, service.NewBackendNotFoundError("not found"))
+			client.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).DoAndReturn(expectStatus(metav1.ConditionFalse, apigwv1alpha1.MeshServiceReasonNotFound))
+		},
+	}, {
+		name: "status-error",
+		err:  errExpected,
+		expectationCB: func(client *mocks.MockClient, resolver *serviceMocks.MockBackendResolver) {
+			client.EXPECT().GetMeshService(gomock.Any(), meshServiceName).Return(&apigwv1alpha1.MeshService{ObjectMeta: metav1.ObjectMeta{Namespace: "default"}}, nil)
+			resolver.EXPECT().ResolveMeshService(gomock.Any(), gomock.Any()).Return(&service.ResolvedReference{}, nil)
+			client.EXPECT().UpdateStatus(gomock.Any(), gomock.Any()).Return(errExpected)
+		},
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := mocks.NewMockClient(ctrl)
+			resolver := serviceMocks.NewMockBackendResolver(ctrl)
+			if test.expectationCB != nil {
+				test.expectationCB(client, resolver)
+			}
+
+			controller := &MeshServiceReconciler{
+				Client:                client,
+				Log:                   hclog.NewNullLogger(),
+				Resolver:              resolver,
+				ConsulNamespaceMapper: func(namespace string) string { return "consul-" + namespace },
+				ConsulPartition:       "partition",
+			}
+			result, err := controller.Reconcile(context.Background(), reconcile.Request{
+				NamespacedName: meshServiceName,
+			})
+			if test.err != nil {
+				require.Error(t, err)
+				require.ErrorIs(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, test.result, result)
+		})
+	}
+}
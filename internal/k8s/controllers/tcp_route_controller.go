@@ -10,6 +10,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
 // TCPRouteReconciler reconciles a TCPRoute object
@@ -28,6 +30,8 @@ type TCPRouteReconciler struct {
 	Log            hclog.Logger
 	ControllerName string
 	Manager        reconciler.ReconcileManager
+	// CatalogEvents, if set, enqueues routes whose Consul services changed
+	CatalogEvents <-chan event.GenericEvent
 }
 
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=tcproutes,verbs=get;list;watch;create;update;patch;delete
@@ -62,7 +66,7 @@ func (r *TCPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *TCPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&gwv1alpha2.TCPRoute{}).
 		Watches(
 			&source.Kind{Type: &gwv1alpha2.ReferenceGrant{}},
@@ -76,14 +80,26 @@ func (r *TCPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			&source.Kind{Type: &corev1.Service{}},
 			handler.EnqueueRequestsFromMapFunc(r.serviceToRouteRequests),
 		).
-		Complete(gatewayclient.NewRequeueingMiddleware(r.Log, r))
+		Watches(
+			&source.Kind{Type: &apigwv1alpha1.MeshService{}},
+			handler.EnqueueRequestsFromMapFunc(r.serviceToRouteRequests),
+		).
+		Watches(
+			&source.Kind{Type: &apigwv1alpha1.ExternalService{}},
+			handler.EnqueueRequestsFromMapFunc(r.serviceToRouteRequests),
+		)
+	if r.CatalogEvents != nil {
+		builder = builder.Watches(
+			&source.Channel{Source: r.CatalogEvents},
+			&handler.EnqueueRequestForObject{},
+		)
+	}
+	return builder.Complete(gatewayclient.NewRequeueingMiddleware(r.Log, r))
 }
 
 // serviceToRouteRequests builds a list of TCPRoutes that need to be reconciled
-// based on changes to a Service
-func (r *TCPRouteReconciler) serviceToRouteRequests(object client.Object) []reconcile.Request {
-	service := object.(*corev1.Service)
-
+// based on changes to a Service, MeshService or ExternalService
+func (r *TCPRouteReconciler) serviceToRouteRequests(service client.Object) []reconcile.Request {
 	routes := r.getRoutesAffectedByService(service)
 	var requests []reconcile.Request
 
@@ -101,8 +117,8 @@ func (r *TCPRouteReconciler) serviceToRouteRequests(object client.Object) []reco
 
 // getRoutesAffectedByService retrieves all TCPRoutes potentially impacted
 // by the Service being modified. This is done by filtering to TCPRoutes that
-// have a backendRef matching the Service's namespace and name.
-func (r *TCPRouteReconciler) getRoutesAffectedByService(service *corev1.Service) []gwv1alpha2.TCPRoute {
+// have a backendRef matching the Service's kind, namespace and name.
+func (r *TCPRouteReconciler) getRoutesAffectedByService(service client.Object) []gwv1alpha2.TCPRoute {
 	var matches []gwv1alpha2.TCPRoute
 
 	routes, err := r.Client.GetTCPRoutes(r.Context)
@@ -116,15 +132,9 @@ func (r *TCPRouteReconciler) getRoutesAffectedByService(service *corev1.Service)
 	nextRoute:
 		for _, rule := range route.Spec.Rules {
 			for _, ref := range rule.BackendRefs {
-				// The BackendRef may or may not specify a namespace, defaults to route's namespace
-				refNamespace := route.Namespace
-				if ref.Namespace != nil {
-					refNamespace = string(*ref.Namespace)
-				}
-
-				// If this BackendRef matches the service namespace + name, then this TCPRoute
+				// If this BackendRef matches the service kind, namespace + name, then this TCPRoute
 				// is affected. No need to check other refs, skip ahead to next TCPRoute.
-				if refNamespace == service.Namespace && ref.Name == gwv1alpha2.ObjectName(service.Name) {
+				if backendRefMatches(route.Namespace, ref.BackendObjectReference, service) {
 					matches = append(matches, route)
 					break nextRoute
 				}
@@ -3,6 +3,16 @@
 
 package gatewayclient
 
+import "errors"
+
+// ErrCertManagerNotInstalled is returned when reading cert-manager
+// resources from a cluster that doesn't have the cert-manager CRDs
+var ErrCertManagerNotInstalled = errors.New("cert-manager is not installed")
+
+// ErrPodSecurityPolicyNotSupported is returned when reading PodSecurityPolicies
+// from a cluster that no longer serves them
+var ErrPodSecurityPolicyNotSupported = errors.New("PodSecurityPolicies are not supported by the cluster")
+
 // K8sError is an error type that should wrap any Kubernetes API
 // errors that the gatewayclient returns -- they're caught in
 // the requeueing middleware to be retried immediately rather
@@ -8,8 +8,14 @@ import (
 	"errors"
 
 	apps "k8s.io/api/apps/v1"
+	autoscaling "k8s.io/api/autoscaling/v2"
 	core "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -37,6 +43,7 @@ type Client interface {
 	GetGatewaysInNamespace(ctx context.Context, ns string) ([]gwv1beta1.Gateway, error)
 	GetSecret(ctx context.Context, key types.NamespacedName) (*core.Secret, error)
 	GetService(ctx context.Context, key types.NamespacedName) (*core.Service, error)
+	GetEndpoints(ctx context.Context, key types.NamespacedName) (*core.Endpoints, error)
 	GetHTTPRoute(ctx context.Context, key types.NamespacedName) (*gwv1alpha2.HTTPRoute, error)
 	GetHTTPRoutes(ctx context.Context) ([]gwv1alpha2.HTTPRoute, error)
 	GetHTTPRoutesInNamespace(ctx context.Context, ns string) ([]gwv1alpha2.HTTPRoute, error)
@@ -44,8 +51,17 @@ type Client interface {
 	GetTCPRoutes(ctx context.Context) ([]gwv1alpha2.TCPRoute, error)
 	GetTCPRoutesInNamespace(ctx context.Context, ns string) ([]gwv1alpha2.TCPRoute, error)
 	GetMeshService(ctx context.Context, key types.NamespacedName) (*apigwv1alpha1.MeshService, error)
+	GetExternalService(ctx context.Context, key types.NamespacedName) (*apigwv1alpha1.ExternalService, error)
+	GetVaultCertificate(ctx context.Context, key types.NamespacedName) (*apigwv1alpha1.VaultCertificate, error)
+	GetGatewayConfig(ctx context.Context, key types.NamespacedName) (*apigwv1alpha1.GatewayConfig, error)
+	GetPodSecurityPolicy(ctx context.Context, name string) (*policyv1beta1.PodSecurityPolicy, error)
+	GetCertManagerCertificate(ctx context.Context, key types.NamespacedName) (*unstructured.Unstructured, error)
+	GetCertManagerCertificatesForGateway(ctx context.Context, gw *gwv1beta1.Gateway) ([]unstructured.Unstructured, error)
 	GetNamespace(ctx context.Context, key types.NamespacedName) (*core.Namespace, error)
 	GetDeployment(ctx context.Context, key types.NamespacedName) (*apps.Deployment, error)
+	GetDaemonSet(ctx context.Context, key types.NamespacedName) (*apps.DaemonSet, error)
+	GetHorizontalPodAutoscaler(ctx context.Context, key types.NamespacedName) (*autoscaling.HorizontalPodAutoscaler, error)
+	GetPodDisruptionBudget(ctx context.Context, key types.NamespacedName) (*policy.PodDisruptionBudget, error)
 
 	// finalizer helpers
 
@@ -78,9 +94,15 @@ type Client interface {
 	// deployments
 
 	CreateOrUpdateDeployment(ctx context.Context, deployment *apps.Deployment, mutators ...func() error) (bool, error)
+	CreateOrUpdateDaemonSet(ctx context.Context, daemonSet *apps.DaemonSet, mutators ...func() error) (bool, error)
 	CreateOrUpdateSecret(ctx context.Context, secret *core.Secret, mutators ...func() error) (bool, error)
 	CreateOrUpdateService(ctx context.Context, service *core.Service, mutators ...func() error) (bool, error)
 	DeleteService(ctx context.Context, service *core.Service) error
+	DeleteDeployment(ctx context.Context, deployment *apps.Deployment) error
+	DeleteDaemonSet(ctx context.Context, daemonSet *apps.DaemonSet) error
+	DeleteCertManagerCertificate(ctx context.Context, certificate *unstructured.Unstructured) error
+	DeleteHorizontalPodAutoscaler(ctx context.Context, autoscaler *autoscaling.HorizontalPodAutoscaler) error
+	DeletePodDisruptionBudget(ctx context.Context, budget *policy.PodDisruptionBudget) error
 	EnsureExists(ctx context.Context, obj client.Object, mutators ...func() error) (bool, error)
 	EnsureServiceAccount(ctx context.Context, owner *gwv1beta1.Gateway, serviceAccount *core.ServiceAccount) error
 
@@ -244,6 +266,17 @@ func (g *gatewayClient) GetService(ctx context.Context, key types.NamespacedName
 	return svc, nil
 }
 
+func (g *gatewayClient) GetEndpoints(ctx context.Context, key types.NamespacedName) (*core.Endpoints, error) {
+	endpoints := &core.Endpoints{}
+	if err := g.Client.Get(ctx, key, endpoints); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewK8sError(err)
+	}
+	return endpoints, nil
+}
+
 func (g *gatewayClient) GetDeployment(ctx context.Context, key types.NamespacedName) (*apps.Deployment, error) {
 	depl := &apps.Deployment{}
 	if err := g.Client.Get(ctx, key, depl); err != nil {
@@ -255,6 +288,39 @@ func (g *gatewayClient) GetDeployment(ctx context.Context, key types.NamespacedN
 	return depl, nil
 }
 
+func (g *gatewayClient) GetDaemonSet(ctx context.Context, key types.NamespacedName) (*apps.DaemonSet, error) {
+	daemonSet := &apps.DaemonSet{}
+	if err := g.Client.Get(ctx, key, daemonSet); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewK8sError(err)
+	}
+	return daemonSet, nil
+}
+
+func (g *gatewayClient) GetHorizontalPodAutoscaler(ctx context.Context, key types.NamespacedName) (*autoscaling.HorizontalPodAutoscaler, error) {
+	autoscaler := &autoscaling.HorizontalPodAutoscaler{}
+	if err := g.Client.Get(ctx, key, autoscaler); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewK8sError(err)
+	}
+	return autoscaler, nil
+}
+
+func (g *gatewayClient) GetPodDisruptionBudget(ctx context.Context, key types.NamespacedName) (*policy.PodDisruptionBudget, error) {
+	budget := &policy.PodDisruptionBudget{}
+	if err := g.Client.Get(ctx, key, budget); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewK8sError(err)
+	}
+	return budget, nil
+}
+
 func (g *gatewayClient) GetSecret(ctx context.Context, key types.NamespacedName) (*core.Secret, error) {
 	secret := &core.Secret{}
 	if err := g.Client.Get(ctx, key, secret); err != nil {
@@ -332,6 +398,93 @@ func (g *gatewayClient) GetMeshService(ctx context.Context, key types.Namespaced
 	return service, nil
 }
 
+func (g *gatewayClient) GetExternalService(ctx context.Context, key types.NamespacedName) (*apigwv1alpha1.ExternalService, error) {
+	service := &apigwv1alpha1.ExternalService{}
+	if err := g.Client.Get(ctx, key, service); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewK8sError(err)
+	}
+	return service, nil
+}
+
+func (g *gatewayClient) GetVaultCertificate(ctx context.Context, key types.NamespacedName) (*apigwv1alpha1.VaultCertificate, error) {
+	certificate := &apigwv1alpha1.VaultCertificate{}
+	if err := g.Client.Get(ctx, key, certificate); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewK8sError(err)
+	}
+	return certificate, nil
+}
+
+func (g *gatewayClient) GetGatewayConfig(ctx context.Context, key types.NamespacedName) (*apigwv1alpha1.GatewayConfig, error) {
+	config := &apigwv1alpha1.GatewayConfig{}
+	if err := g.Client.Get(ctx, key, config); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, NewK8sError(err)
+	}
+	return config, nil
+}
+
+// GetPodSecurityPolicy returns the PodSecurityPolicy with the given name, or
+// ErrPodSecurityPolicyNotSupported if the cluster no longer serves them.
+func (g *gatewayClient) GetPodSecurityPolicy(ctx context.Context, name string) (*policyv1beta1.PodSecurityPolicy, error) {
+	psp := &policyv1beta1.PodSecurityPolicy{}
+	if err := g.Client.Get(ctx, types.NamespacedName{Name: name}, psp); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if meta.IsNoMatchError(err) {
+			return nil, ErrPodSecurityPolicyNotSupported
+		}
+		return nil, NewK8sError(err)
+	}
+	return psp, nil
+}
+
+// GetCertManagerCertificate returns the cert-manager Certificate with the given name,
+// or ErrCertManagerNotInstalled if the cert-manager CRDs aren't installed.
+func (g *gatewayClient) GetCertManagerCertificate(ctx context.Context, key types.NamespacedName) (*unstructured.Unstructured, error) {
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(utils.CertManagerCertificateGVK)
+	if err := g.Client.Get(ctx, key, certificate); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if meta.IsNoMatchError(err) {
+			return nil, ErrCertManagerNotInstalled
+		}
+		return nil, NewK8sError(err)
+	}
+	return certificate, nil
+}
+
+// GetCertManagerCertificatesForGateway returns the cert-manager Certificates created for
+// a Gateway, or ErrCertManagerNotInstalled if the cert-manager CRDs aren't installed.
+func (g *gatewayClient) GetCertManagerCertificatesForGateway(ctx context.Context, gw *gwv1beta1.Gateway) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(utils.CertManagerCertificateListGVK)
+	if err := g.Client.List(ctx, list, client.InNamespace(gw.Namespace), client.MatchingLabels(utils.LabelsForGateway(gw))); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, ErrCertManagerNotInstalled
+		}
+		return nil, NewK8sError(err)
+	}
+
+	certificates := []unstructured.Unstructured{}
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], gw) {
+			certificates = append(certificates, list.Items[i])
+		}
+	}
+	return certificates, nil
+}
+
 func (g *gatewayClient) GetNamespace(ctx context.Context, key types.NamespacedName) (*core.Namespace, error) {
 	namespace := &core.Namespace{}
 	if err := g.Client.Get(ctx, key, namespace); err != nil {
@@ -415,6 +568,17 @@ func (g *gatewayClient) CreateOrUpdateDeployment(ctx context.Context, deployment
 	return operation != controllerutil.OperationResultNone, nil
 }
 
+func (g *gatewayClient) CreateOrUpdateDaemonSet(ctx context.Context, daemonSet *apps.DaemonSet, mutators ...func() error) (bool, error) {
+	operation, err := controllerutil.CreateOrUpdate(ctx, g.Client, daemonSet, multiMutatorFn(mutators))
+	if err != nil {
+		return false, NewK8sError(err)
+	}
+	if operation == controllerutil.OperationResultCreated {
+		metrics.Registry.IncrCounter(metrics.K8sNewGatewayDeployments, 1)
+	}
+	return operation != controllerutil.OperationResultNone, nil
+}
+
 func (g *gatewayClient) CreateOrUpdateSecret(ctx context.Context, secret *core.Secret, mutators ...func() error) (bool, error) {
 	op, err := controllerutil.CreateOrUpdate(ctx, g.Client, secret, multiMutatorFn(mutators))
 	if err != nil {
@@ -441,6 +605,56 @@ func (g *gatewayClient) DeleteService(ctx context.Context, service *core.Service
 	return nil
 }
 
+func (g *gatewayClient) DeleteCertManagerCertificate(ctx context.Context, certificate *unstructured.Unstructured) error {
+	if err := g.Delete(ctx, certificate); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return NewK8sError(err)
+	}
+	return nil
+}
+
+func (g *gatewayClient) DeleteDeployment(ctx context.Context, deployment *apps.Deployment) error {
+	if err := g.Delete(ctx, deployment); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return NewK8sError(err)
+	}
+	return nil
+}
+
+func (g *gatewayClient) DeleteDaemonSet(ctx context.Context, daemonSet *apps.DaemonSet) error {
+	if err := g.Delete(ctx, daemonSet); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return NewK8sError(err)
+	}
+	return nil
+}
+
+func (g *gatewayClient) DeleteHorizontalPodAutoscaler(ctx context.Context, autoscaler *autoscaling.HorizontalPodAutoscaler) error {
+	if err := g.Delete(ctx, autoscaler); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return NewK8sError(err)
+	}
+	return nil
+}
+
+func (g *gatewayClient) DeletePodDisruptionBudget(ctx context.Context, budget *policy.PodDisruptionBudget) error {
+	if err := g.Delete(ctx, budget); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return NewK8sError(err)
+	}
+	return nil
+}
+
 func (g *gatewayClient) EnsureExists(ctx context.Context, obj client.Object, mutators ...func() error) (bool, error) {
 	op, err := controllerutil.CreateOrUpdate(ctx, g.Client, obj, multiMutatorFn(mutators))
 	if err != nil {
@@ -519,19 +733,30 @@ func (g *gatewayClient) GetConfigForGatewayClassName(ctx context.Context, name s
 
 // TODO: this likely needs to support gwv1alpha2.ParentReference too
 func (g *gatewayClient) IsManagedRoute(ctx context.Context, namespace string, parents []gwv1alpha2.ParentReference) (bool, error) {
-	// we look up a list of deployments that are managed by us, and try and check our references based on them.
-	list := &apps.DeploymentList{}
-	if err := g.Client.List(ctx, list, client.MatchingLabels(map[string]string{
+	// we look up a list of deployments and daemonsets that are managed by us, and try and check our references based on them.
+	managed := client.MatchingLabels(map[string]string{
 		utils.ManagedLabel: "true",
-	})); err != nil {
+	})
+	list := &apps.DeploymentList{}
+	if err := g.Client.List(ctx, list, managed); err != nil {
 		return false, NewK8sError(err)
 	}
+	daemonSets := &apps.DaemonSetList{}
+	if err := g.Client.List(ctx, daemonSets, managed); err != nil {
+		return false, NewK8sError(err)
+	}
+	gateways := make([]types.NamespacedName, 0, len(list.Items)+len(daemonSets.Items))
+	for i := range list.Items {
+		gateways = append(gateways, utils.GatewayByLabels(&list.Items[i]))
+	}
+	for i := range daemonSets.Items {
+		gateways = append(gateways, utils.GatewayByLabels(&daemonSets.Items[i]))
+	}
 	for _, ref := range parents {
 		name, isGateway := utils.ReferencesGateway(namespace, ref)
 		if isGateway {
-			for _, deployment := range list.Items {
-				deployment := &deployment
-				if name == utils.GatewayByLabels(deployment) {
+			for _, gateway := range gateways {
+				if name == gateway {
 					return true, nil
 				}
 			}
@@ -11,11 +11,15 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	v1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 	v1 "k8s.io/api/apps/v1"
+	v2 "k8s.io/api/autoscaling/v2"
 	v10 "k8s.io/api/core/v1"
+	v11 "k8s.io/api/policy/v1"
+	v1beta1 "k8s.io/api/policy/v1beta1"
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	types "k8s.io/apimachinery/pkg/types"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
 	v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
-	v1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	v1beta10 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // MockClient is a mock of Client interface.
@@ -41,6 +45,26 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CreateOrUpdateDaemonSet mocks base method.
+func (m *MockClient) CreateOrUpdateDaemonSet(ctx context.Context, daemonSet *v1.DaemonSet, mutators ...func() error) (bool, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, daemonSet}
+	for _, a := range mutators {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateOrUpdateDaemonSet", varargs...)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateDaemonSet indicates an expected call of CreateOrUpdateDaemonSet.
+func (mr *MockClientMockRecorder) CreateOrUpdateDaemonSet(ctx, daemonSet interface{}, mutators ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, daemonSet}, mutators...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateDaemonSet", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateDaemonSet), varargs...)
+}
+
 // CreateOrUpdateDeployment mocks base method.
 func (m *MockClient) CreateOrUpdateDeployment(ctx context.Context, deployment *v1.Deployment, mutators ...func() error) (bool, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +125,76 @@ func (mr *MockClientMockRecorder) CreateOrUpdateService(ctx, service interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateService", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateService), varargs...)
 }
 
+// DeleteCertManagerCertificate mocks base method.
+func (m *MockClient) DeleteCertManagerCertificate(ctx context.Context, certificate *unstructured.Unstructured) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCertManagerCertificate", ctx, certificate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCertManagerCertificate indicates an expected call of DeleteCertManagerCertificate.
+func (mr *MockClientMockRecorder) DeleteCertManagerCertificate(ctx, certificate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCertManagerCertificate", reflect.TypeOf((*MockClient)(nil).DeleteCertManagerCertificate), ctx, certificate)
+}
+
+// DeleteDaemonSet mocks base method.
+func (m *MockClient) DeleteDaemonSet(ctx context.Context, daemonSet *v1.DaemonSet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDaemonSet", ctx, daemonSet)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDaemonSet indicates an expected call of DeleteDaemonSet.
+func (mr *MockClientMockRecorder) DeleteDaemonSet(ctx, daemonSet interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDaemonSet", reflect.TypeOf((*MockClient)(nil).DeleteDaemonSet), ctx, daemonSet)
+}
+
+// DeleteDeployment mocks base method.
+func (m *MockClient) DeleteDeployment(ctx context.Context, deployment *v1.Deployment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeployment", ctx, deployment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDeployment indicates an expected call of DeleteDeployment.
+func (mr *MockClientMockRecorder) DeleteDeployment(ctx, deployment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeployment", reflect.TypeOf((*MockClient)(nil).DeleteDeployment), ctx, deployment)
+}
+
+// DeleteHorizontalPodAutoscaler mocks base method.
+func (m *MockClient) DeleteHorizontalPodAutoscaler(ctx context.Context, autoscaler *v2.HorizontalPodAutoscaler) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHorizontalPodAutoscaler", ctx, autoscaler)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHorizontalPodAutoscaler indicates an expected call of DeleteHorizontalPodAutoscaler.
+func (mr *MockClientMockRecorder) DeleteHorizontalPodAutoscaler(ctx, autoscaler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHorizontalPodAutoscaler", reflect.TypeOf((*MockClient)(nil).DeleteHorizontalPodAutoscaler), ctx, autoscaler)
+}
+
+// DeletePodDisruptionBudget mocks base method.
+func (m *MockClient) DeletePodDisruptionBudget(ctx context.Context, budget *v11.PodDisruptionBudget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePodDisruptionBudget", ctx, budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePodDisruptionBudget indicates an expected call of DeletePodDisruptionBudget.
+func (mr *MockClientMockRecorder) DeletePodDisruptionBudget(ctx, budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePodDisruptionBudget", reflect.TypeOf((*MockClient)(nil).DeletePodDisruptionBudget), ctx, budget)
+}
+
 // DeleteService mocks base method.
 func (m *MockClient) DeleteService(ctx context.Context, service *v10.Service) error {
 	m.ctrl.T.Helper()
@@ -116,7 +210,7 @@ func (mr *MockClientMockRecorder) DeleteService(ctx, service interface{}) *gomoc
 }
 
 // DeploymentForGateway mocks base method.
-func (m *MockClient) DeploymentForGateway(ctx context.Context, gw *v1beta1.Gateway) (*v1.Deployment, error) {
+func (m *MockClient) DeploymentForGateway(ctx context.Context, gw *v1beta10.Gateway) (*v1.Deployment, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeploymentForGateway", ctx, gw)
 	ret0, _ := ret[0].(*v1.Deployment)
@@ -166,7 +260,7 @@ func (mr *MockClientMockRecorder) EnsureFinalizer(ctx, object, finalizer interfa
 }
 
 // EnsureServiceAccount mocks base method.
-func (m *MockClient) EnsureServiceAccount(ctx context.Context, owner *v1beta1.Gateway, serviceAccount *v10.ServiceAccount) error {
+func (m *MockClient) EnsureServiceAccount(ctx context.Context, owner *v1beta10.Gateway, serviceAccount *v10.ServiceAccount) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "EnsureServiceAccount", ctx, owner, serviceAccount)
 	ret0, _ := ret[0].(error)
@@ -195,7 +289,7 @@ func (mr *MockClientMockRecorder) GatewayClassConfigInUse(ctx, gcc interface{})
 }
 
 // GatewayClassInUse mocks base method.
-func (m *MockClient) GatewayClassInUse(ctx context.Context, gc *v1beta1.GatewayClass) (bool, error) {
+func (m *MockClient) GatewayClassInUse(ctx context.Context, gc *v1beta10.GatewayClass) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GatewayClassInUse", ctx, gc)
 	ret0, _ := ret[0].(bool)
@@ -210,10 +304,10 @@ func (mr *MockClientMockRecorder) GatewayClassInUse(ctx, gc interface{}) *gomock
 }
 
 // GatewayClassesUsingConfig mocks base method.
-func (m *MockClient) GatewayClassesUsingConfig(ctx context.Context, gcc *v1alpha1.GatewayClassConfig) (*v1beta1.GatewayClassList, error) {
+func (m *MockClient) GatewayClassesUsingConfig(ctx context.Context, gcc *v1alpha1.GatewayClassConfig) (*v1beta10.GatewayClassList, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GatewayClassesUsingConfig", ctx, gcc)
-	ret0, _ := ret[0].(*v1beta1.GatewayClassList)
+	ret0, _ := ret[0].(*v1beta10.GatewayClassList)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -224,6 +318,36 @@ func (mr *MockClientMockRecorder) GatewayClassesUsingConfig(ctx, gcc interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GatewayClassesUsingConfig", reflect.TypeOf((*MockClient)(nil).GatewayClassesUsingConfig), ctx, gcc)
 }
 
+// GetCertManagerCertificate mocks base method.
+func (m *MockClient) GetCertManagerCertificate(ctx context.Context, key types.NamespacedName) (*unstructured.Unstructured, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCertManagerCertificate", ctx, key)
+	ret0, _ := ret[0].(*unstructured.Unstructured)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCertManagerCertificate indicates an expected call of GetCertManagerCertificate.
+func (mr *MockClientMockRecorder) GetCertManagerCertificate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCertManagerCertificate", reflect.TypeOf((*MockClient)(nil).GetCertManagerCertificate), ctx, key)
+}
+
+// GetCertManagerCertificatesForGateway mocks base method.
+func (m *MockClient) GetCertManagerCertificatesForGateway(ctx context.Context, gw *v1beta10.Gateway) ([]unstructured.Unstructured, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCertManagerCertificatesForGateway", ctx, gw)
+	ret0, _ := ret[0].([]unstructured.Unstructured)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCertManagerCertificatesForGateway indicates an expected call of GetCertManagerCertificatesForGateway.
+func (mr *MockClientMockRecorder) GetCertManagerCertificatesForGateway(ctx, gw interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCertManagerCertificatesForGateway", reflect.TypeOf((*MockClient)(nil).GetCertManagerCertificatesForGateway), ctx, gw)
+}
+
 // GetConfigForGatewayClassName mocks base method.
 func (m *MockClient) GetConfigForGatewayClassName(ctx context.Context, name string) (v1alpha1.GatewayClassConfig, bool, error) {
 	m.ctrl.T.Helper()
@@ -240,6 +364,21 @@ func (mr *MockClientMockRecorder) GetConfigForGatewayClassName(ctx, name interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigForGatewayClassName", reflect.TypeOf((*MockClient)(nil).GetConfigForGatewayClassName), ctx, name)
 }
 
+// GetDaemonSet mocks base method.
+func (m *MockClient) GetDaemonSet(ctx context.Context, key types.NamespacedName) (*v1.DaemonSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDaemonSet", ctx, key)
+	ret0, _ := ret[0].(*v1.DaemonSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDaemonSet indicates an expected call of GetDaemonSet.
+func (mr *MockClientMockRecorder) GetDaemonSet(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDaemonSet", reflect.TypeOf((*MockClient)(nil).GetDaemonSet), ctx, key)
+}
+
 // GetDeployment mocks base method.
 func (m *MockClient) GetDeployment(ctx context.Context, key types.NamespacedName) (*v1.Deployment, error) {
 	m.ctrl.T.Helper()
@@ -255,11 +394,41 @@ func (mr *MockClientMockRecorder) GetDeployment(ctx, key interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeployment", reflect.TypeOf((*MockClient)(nil).GetDeployment), ctx, key)
 }
 
+// GetEndpoints mocks base method.
+func (m *MockClient) GetEndpoints(ctx context.Context, key types.NamespacedName) (*v10.Endpoints, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEndpoints", ctx, key)
+	ret0, _ := ret[0].(*v10.Endpoints)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEndpoints indicates an expected call of GetEndpoints.
+func (mr *MockClientMockRecorder) GetEndpoints(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEndpoints", reflect.TypeOf((*MockClient)(nil).GetEndpoints), ctx, key)
+}
+
+// GetExternalService mocks base method.
+func (m *MockClient) GetExternalService(ctx context.Context, key types.NamespacedName) (*v1alpha1.ExternalService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalService", ctx, key)
+	ret0, _ := ret[0].(*v1alpha1.ExternalService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalService indicates an expected call of GetExternalService.
+func (mr *MockClientMockRecorder) GetExternalService(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalService", reflect.TypeOf((*MockClient)(nil).GetExternalService), ctx, key)
+}
+
 // GetGateway mocks base method.
-func (m *MockClient) GetGateway(ctx context.Context, key types.NamespacedName) (*v1beta1.Gateway, error) {
+func (m *MockClient) GetGateway(ctx context.Context, key types.NamespacedName) (*v1beta10.Gateway, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetGateway", ctx, key)
-	ret0, _ := ret[0].(*v1beta1.Gateway)
+	ret0, _ := ret[0].(*v1beta10.Gateway)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -271,10 +440,10 @@ func (mr *MockClientMockRecorder) GetGateway(ctx, key interface{}) *gomock.Call
 }
 
 // GetGatewayClass mocks base method.
-func (m *MockClient) GetGatewayClass(ctx context.Context, key types.NamespacedName) (*v1beta1.GatewayClass, error) {
+func (m *MockClient) GetGatewayClass(ctx context.Context, key types.NamespacedName) (*v1beta10.GatewayClass, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetGatewayClass", ctx, key)
-	ret0, _ := ret[0].(*v1beta1.GatewayClass)
+	ret0, _ := ret[0].(*v1beta10.GatewayClass)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -300,11 +469,26 @@ func (mr *MockClientMockRecorder) GetGatewayClassConfig(ctx, key interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGatewayClassConfig", reflect.TypeOf((*MockClient)(nil).GetGatewayClassConfig), ctx, key)
 }
 
+// GetGatewayConfig mocks base method.
+func (m *MockClient) GetGatewayConfig(ctx context.Context, key types.NamespacedName) (*v1alpha1.GatewayConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGatewayConfig", ctx, key)
+	ret0, _ := ret[0].(*v1alpha1.GatewayConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGatewayConfig indicates an expected call of GetGatewayConfig.
+func (mr *MockClientMockRecorder) GetGatewayConfig(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGatewayConfig", reflect.TypeOf((*MockClient)(nil).GetGatewayConfig), ctx, key)
+}
+
 // GetGatewaysInNamespace mocks base method.
-func (m *MockClient) GetGatewaysInNamespace(ctx context.Context, ns string) ([]v1beta1.Gateway, error) {
+func (m *MockClient) GetGatewaysInNamespace(ctx context.Context, ns string) ([]v1beta10.Gateway, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetGatewaysInNamespace", ctx, ns)
-	ret0, _ := ret[0].([]v1beta1.Gateway)
+	ret0, _ := ret[0].([]v1beta10.Gateway)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -360,6 +544,21 @@ func (mr *MockClientMockRecorder) GetHTTPRoutesInNamespace(ctx, ns interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHTTPRoutesInNamespace", reflect.TypeOf((*MockClient)(nil).GetHTTPRoutesInNamespace), ctx, ns)
 }
 
+// GetHorizontalPodAutoscaler mocks base method.
+func (m *MockClient) GetHorizontalPodAutoscaler(ctx context.Context, key types.NamespacedName) (*v2.HorizontalPodAutoscaler, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHorizontalPodAutoscaler", ctx, key)
+	ret0, _ := ret[0].(*v2.HorizontalPodAutoscaler)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHorizontalPodAutoscaler indicates an expected call of GetHorizontalPodAutoscaler.
+func (mr *MockClientMockRecorder) GetHorizontalPodAutoscaler(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHorizontalPodAutoscaler", reflect.TypeOf((*MockClient)(nil).GetHorizontalPodAutoscaler), ctx, key)
+}
+
 // GetMeshService mocks base method.
 func (m *MockClient) GetMeshService(ctx context.Context, key types.NamespacedName) (*v1alpha1.MeshService, error) {
 	m.ctrl.T.Helper()
@@ -390,6 +589,36 @@ func (mr *MockClientMockRecorder) GetNamespace(ctx, key interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespace", reflect.TypeOf((*MockClient)(nil).GetNamespace), ctx, key)
 }
 
+// GetPodDisruptionBudget mocks base method.
+func (m *MockClient) GetPodDisruptionBudget(ctx context.Context, key types.NamespacedName) (*v11.PodDisruptionBudget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPodDisruptionBudget", ctx, key)
+	ret0, _ := ret[0].(*v11.PodDisruptionBudget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPodDisruptionBudget indicates an expected call of GetPodDisruptionBudget.
+func (mr *MockClientMockRecorder) GetPodDisruptionBudget(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodDisruptionBudget", reflect.TypeOf((*MockClient)(nil).GetPodDisruptionBudget), ctx, key)
+}
+
+// GetPodSecurityPolicy mocks base method.
+func (m *MockClient) GetPodSecurityPolicy(ctx context.Context, name string) (*v1beta1.PodSecurityPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPodSecurityPolicy", ctx, name)
+	ret0, _ := ret[0].(*v1beta1.PodSecurityPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPodSecurityPolicy indicates an expected call of GetPodSecurityPolicy.
+func (mr *MockClientMockRecorder) GetPodSecurityPolicy(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPodSecurityPolicy", reflect.TypeOf((*MockClient)(nil).GetPodSecurityPolicy), ctx, name)
+}
+
 // GetReferenceGrantsInNamespace mocks base method.
 func (m *MockClient) GetReferenceGrantsInNamespace(ctx context.Context, namespace string) ([]v1alpha2.ReferenceGrant, error) {
 	m.ctrl.T.Helper()
@@ -480,8 +709,23 @@ func (mr *MockClientMockRecorder) GetTCPRoutesInNamespace(ctx, ns interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTCPRoutesInNamespace", reflect.TypeOf((*MockClient)(nil).GetTCPRoutesInNamespace), ctx, ns)
 }
 
+// GetVaultCertificate mocks base method.
+func (m *MockClient) GetVaultCertificate(ctx context.Context, key types.NamespacedName) (*v1alpha1.VaultCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVaultCertificate", ctx, key)
+	ret0, _ := ret[0].(*v1alpha1.VaultCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVaultCertificate indicates an expected call of GetVaultCertificate.
+func (mr *MockClientMockRecorder) GetVaultCertificate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVaultCertificate", reflect.TypeOf((*MockClient)(nil).GetVaultCertificate), ctx, key)
+}
+
 // HasManagedDeployment mocks base method.
-func (m *MockClient) HasManagedDeployment(ctx context.Context, gw *v1beta1.Gateway) (bool, error) {
+func (m *MockClient) HasManagedDeployment(ctx context.Context, gw *v1beta10.Gateway) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "HasManagedDeployment", ctx, gw)
 	ret0, _ := ret[0].(bool)
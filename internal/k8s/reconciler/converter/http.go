@@ -155,12 +155,10 @@ func httpReferencesToRules(referenceMap service.RouteRuleReferenceMap) []core.HT
 				if reference.Reference.HTTPRef.Weight != nil {
 					weight = *reference.Reference.HTTPRef.Weight
 				}
-				// note that we ignore the Port value
+				// the Port value was already used to select the Consul service
+				// when resolving the reference, traffic is routed through the mesh
 				services = append(services, core.HTTPService{
-					Service: core.ResolvedService{
-						ConsulNamespace: reference.Consul.Namespace,
-						Service:         reference.Consul.Name,
-					},
+					Service: consulServiceToResolvedService(reference.Consul),
 					Weight:  weight,
 					Filters: convertHTTPRouteFilters(reference.Reference.HTTPRef.Filters),
 				})
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package converter
+
+import (
+	"github.com/hashicorp/consul-api-gateway/internal/core"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
+)
+
+func consulServiceToResolvedService(consul *service.ConsulService) core.ResolvedService {
+	resolved := core.ResolvedService{
+		ConsulNamespace: consul.Namespace,
+		Service:         consul.Name,
+	}
+	if consul.External != nil {
+		resolved.External = &core.ExternalService{
+			Address: consul.External.Address,
+			Port:    consul.External.Port,
+			Owner:   consul.External.Owner,
+		}
+		if tls := consul.External.TLS; tls != nil {
+			resolved.External.TLS = &core.ExternalServiceTLS{
+				CAFile:   tls.CAFile,
+				CertFile: tls.CertFile,
+				KeyFile:  tls.KeyFile,
+				SNI:      tls.SNI,
+			}
+		}
+	}
+	return resolved
+}
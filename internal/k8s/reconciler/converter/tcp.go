@@ -56,10 +56,7 @@ func tcpReferencesToService(referenceMap service.RouteRuleReferenceMap) core.Res
 			switch reference.Type {
 			case service.ConsulServiceReference:
 				// at this point there should only be a single resolved service in the reference map
-				return core.ResolvedService{
-					ConsulNamespace: reference.Consul.Namespace,
-					Service:         reference.Consul.Name,
-				}
+				return consulServiceToResolvedService(reference.Consul)
 			default:
 				continue
 			}
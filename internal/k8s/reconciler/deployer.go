@@ -6,11 +6,15 @@ package reconciler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	apps "k8s.io/api/apps/v1"
+	autoscaling "k8s.io/api/autoscaling/v2"
 	core "k8s.io/api/core/v1"
+	policy "k8s.io/api/policy/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
@@ -79,10 +83,22 @@ func (d *GatewayDeployer) Deploy(ctx context.Context, gateway *K8sGateway) error
 		return err
 	}
 
+	if err := d.ensureCertificates(ctx, gateway.Config, gateway.Gateway); err != nil {
+		return err
+	}
+
 	if err := d.ensureDeployment(ctx, gateway.GatewayState.ConsulNamespace, gateway.Config, gateway.Gateway); err != nil {
 		return err
 	}
 
+	if err := d.ensureHorizontalPodAutoscaler(ctx, gateway.Config, gateway.Gateway); err != nil {
+		return err
+	}
+
+	if err := d.ensurePodDisruptionBudget(ctx, gateway.Config, gateway.Gateway); err != nil {
+		return err
+	}
+
 	return d.ensureService(ctx, gateway.Config, gateway.Gateway)
 }
 
@@ -102,7 +118,21 @@ func (d *GatewayDeployer) ensureServiceAccount(ctx context.Context, config apigw
 		return nil
 	}
 
-	if _, err := d.client.EnsureExists(ctx, role); err != nil {
+	// keep the role's rules in sync so that security policies added to the config
+	// are granted to existing gateways, but never grant them to a role that
+	// someone else created with the same name
+	mutatedRole := role.DeepCopy()
+	if _, err := d.client.EnsureExists(ctx, mutatedRole, func() error {
+		if mutatedRole.ResourceVersion == "" {
+			if err := d.client.SetControllerOwnership(gateway, mutatedRole); err != nil {
+				return err
+			}
+		} else if !meta.IsControlledBy(mutatedRole, gateway) {
+			return fmt.Errorf("role %s/%s not owned by the gateway", mutatedRole.Namespace, mutatedRole.Name)
+		}
+		mutatedRole.Rules = role.Rules
+		return nil
+	}); err != nil {
 		return err
 	}
 
@@ -151,7 +181,131 @@ func (d *GatewayDeployer) ensureSecret(ctx context.Context, config apigwv1alpha1
 	return nil
 }
 
+// ensureCertificates creates or updates a cert-manager Certificate for every listener
+// with an allowed cert-manager issuer in its TLS options, and deletes any Certificates
+// that were created for listeners that no longer have one.
+func (d *GatewayDeployer) ensureCertificates(ctx context.Context, config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) error {
+	desired := make(map[string]*unstructured.Unstructured)
+	for _, listener := range gateway.Spec.Listeners {
+		if certificate := certManagerCertificateFor(config, gateway, listener); certificate != nil {
+			desired[certificate.GetName()] = certificate
+		}
+	}
+
+	existing, err := d.client.GetCertManagerCertificatesForGateway(ctx, gateway)
+	if errors.Is(err, gatewayclient.ErrCertManagerNotInstalled) {
+		// listeners requesting certificates are marked as invalid
+		// during validation, so there's nothing for us to do here
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, certificate := range desired {
+		claimable, err := d.certificateClaimable(ctx, gateway, certificate)
+		if err != nil {
+			return err
+		}
+		if !claimable {
+			// reported as an invalid certificate reference during validation
+			continue
+		}
+
+		mutated := certificate.DeepCopy()
+		updated, err := d.client.EnsureExists(ctx, mutated, func() error {
+			mutated.SetLabels(certificate.GetLabels())
+			mutated.Object["spec"] = certificate.Object["spec"]
+			return d.client.SetControllerOwnership(gateway, mutated)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create or update gateway certificate: %w", err)
+		}
+		if updated && d.logger.IsTrace() {
+			d.logger.Trace("created or updated gateway certificate", "certificate", certificate.GetName())
+		}
+	}
+
+	for i := range existing {
+		certificate := &existing[i]
+		if _, ok := desired[certificate.GetName()]; ok {
+			continue
+		}
+		if err := d.client.DeleteCertManagerCertificate(ctx, certificate); err != nil {
+			return fmt.Errorf("failed to delete gateway certificate: %w", err)
+		}
+		if d.logger.IsTrace() {
+			d.logger.Trace("deleted gateway certificate", "certificate", certificate.GetName())
+		}
+	}
+
+	return nil
+}
+
+// certificateClaimable checks that neither a listener's Certificate nor the secret it issues
+// into already exist for something other than the Gateway, since cert-manager overwrites
+// the secret and we'd otherwise take over the Certificate
+func (d *GatewayDeployer) certificateClaimable(ctx context.Context, gateway *gwv1beta1.Gateway, certificate *unstructured.Unstructured) (bool, error) {
+	key := types.NamespacedName{Namespace: certificate.GetNamespace(), Name: certificate.GetName()}
+	existing, err := d.client.GetCertManagerCertificate(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil && !meta.IsControlledBy(existing, gateway) {
+		d.logger.Warn("not updating cert-manager Certificate that is not owned by the gateway", "certificate", key)
+		return false, nil
+	}
+
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	secret, err := d.client.GetSecret(ctx, types.NamespacedName{Namespace: certificate.GetNamespace(), Name: secretName})
+	if err != nil {
+		return false, err
+	}
+	if secret != nil && !utils.IsCertManagerSecretFor(secret, certificate.GetName()) {
+		d.logger.Warn("not issuing into secret that was not issued for the gateway's cert-manager Certificate", "certificate", key, "secret", secretName)
+		return false, nil
+	}
+	return true, nil
+}
+
+// certManagerCertificateFor returns the cert-manager Certificate requested by a listener, if
+// any. Certificates are only created for listeners with a hostname whose certificate reference
+// is a Secret in the Gateway's namespace and whose issuer is allowed by the GatewayClassConfig,
+// invalid listeners are reported during validation.
+func certManagerCertificateFor(config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway, listener gwv1beta1.Listener) *unstructured.Unstructured {
+	issuer, ok := utils.CertManagerIssuerFor(listener)
+	if !ok || len(listener.TLS.CertificateRefs) == 0 || listener.Hostname == nil || *listener.Hostname == "" {
+		return nil
+	}
+	if !config.Spec.CertManagerSpec.AllowsIssuer(issuer.Name, issuer.Kind, issuer.Group) {
+		return nil
+	}
+	ref := listener.TLS.CertificateRefs[0]
+	if !utils.IsSecretInNamespace(ref, gateway.Namespace) {
+		return nil
+	}
+
+	certificate := utils.NewCertManagerCertificate(
+		gateway.Namespace,
+		utils.CertManagerCertificateName(gateway, listener),
+		string(ref.Name),
+		[]string{string(*listener.Hostname)},
+		issuer,
+	)
+	certificate.SetLabels(utils.LabelsForGateway(gateway))
+	return certificate
+}
+
+// ensureDeployment creates or updates the gateway's Deployment or DaemonSet, depending
+// on the configured deployment mode, and deletes the other if we previously created it
 func (d *GatewayDeployer) ensureDeployment(ctx context.Context, namespace string, config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) error {
+	if config.Spec.DeploymentSpec.Mode == apigwv1alpha1.DeploymentModeDaemonSet {
+		if err := d.ensureDaemonSet(ctx, namespace, config, gateway); err != nil {
+			return err
+		}
+		return d.deleteDeployment(ctx, gateway)
+	}
+
 	// get current deployment so user set replica count isn't overridden by default values
 	currentDeployment, err := d.client.GetDeployment(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name})
 	if err != nil {
@@ -180,6 +334,134 @@ func (d *GatewayDeployer) ensureDeployment(ctx context.Context, namespace string
 		}
 	}
 
+	return d.deleteDaemonSet(ctx, gateway)
+}
+
+func (d *GatewayDeployer) ensureDaemonSet(ctx context.Context, namespace string, config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) error {
+	daemonSet := d.DaemonSet(namespace, config, gateway)
+	mutated := daemonSet.DeepCopy()
+
+	updated, err := d.client.CreateOrUpdateDaemonSet(ctx, mutated, func() error {
+		mutated = apigwv1alpha1.MergeDaemonSet(daemonSet, mutated)
+		return d.client.SetControllerOwnership(gateway, mutated)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update gateway daemonset: %w", err)
+	}
+
+	if updated && d.logger.IsTrace() {
+		data, err := json.MarshalIndent(mutated, "", "  ")
+		if err == nil {
+			d.logger.Trace("created or updated gateway daemonset", "daemonset", string(data))
+		}
+	}
+
+	return nil
+}
+
+// deleteDeployment deletes the gateway's Deployment if we previously created one
+func (d *GatewayDeployer) deleteDeployment(ctx context.Context, gateway *gwv1beta1.Gateway) error {
+	existing, err := d.client.GetDeployment(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name})
+	if err != nil {
+		return err
+	}
+	if existing == nil || !meta.IsControlledBy(existing, gateway) {
+		return nil
+	}
+	if err := d.client.DeleteDeployment(ctx, existing); err != nil {
+		return fmt.Errorf("failed to delete gateway deployment: %w", err)
+	}
+	return nil
+}
+
+// deleteDaemonSet deletes the gateway's DaemonSet if we previously created one
+func (d *GatewayDeployer) deleteDaemonSet(ctx context.Context, gateway *gwv1beta1.Gateway) error {
+	existing, err := d.client.GetDaemonSet(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name})
+	if err != nil {
+		return err
+	}
+	if existing == nil || !meta.IsControlledBy(existing, gateway) {
+		return nil
+	}
+	if err := d.client.DeleteDaemonSet(ctx, existing); err != nil {
+		return fmt.Errorf("failed to delete gateway daemonset: %w", err)
+	}
+	return nil
+}
+
+// ensureHorizontalPodAutoscaler creates or updates the gateway's HorizontalPodAutoscaler
+// if autoscaling is configured, otherwise it deletes any that we previously created
+func (d *GatewayDeployer) ensureHorizontalPodAutoscaler(ctx context.Context, config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) error {
+	autoscaler := d.HorizontalPodAutoscaler(config, gateway)
+	if autoscaler == nil {
+		existing, err := d.client.GetHorizontalPodAutoscaler(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name})
+		if err != nil {
+			return err
+		}
+		if existing == nil || !meta.IsControlledBy(existing, gateway) {
+			return nil
+		}
+		if err := d.client.DeleteHorizontalPodAutoscaler(ctx, existing); err != nil {
+			return fmt.Errorf("failed to delete gateway horizontal pod autoscaler: %w", err)
+		}
+		return nil
+	}
+
+	mutated := autoscaler.DeepCopy()
+	updated, err := d.client.EnsureExists(ctx, mutated, func() error {
+		mutated.Labels = autoscaler.Labels
+		mutated.Spec = autoscaler.Spec
+		return d.client.SetControllerOwnership(gateway, mutated)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update gateway horizontal pod autoscaler: %w", err)
+	}
+
+	if updated && d.logger.IsTrace() {
+		data, err := json.MarshalIndent(mutated, "", "  ")
+		if err == nil {
+			d.logger.Trace("created or updated gateway horizontal pod autoscaler", "autoscaler", string(data))
+		}
+	}
+
+	return nil
+}
+
+// ensurePodDisruptionBudget creates or updates the gateway's PodDisruptionBudget if a
+// disruption budget is configured, otherwise it deletes any that we previously created
+func (d *GatewayDeployer) ensurePodDisruptionBudget(ctx context.Context, config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) error {
+	budget := d.PodDisruptionBudget(config, gateway)
+	if budget == nil {
+		existing, err := d.client.GetPodDisruptionBudget(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name})
+		if err != nil {
+			return err
+		}
+		if existing == nil || !meta.IsControlledBy(existing, gateway) {
+			return nil
+		}
+		if err := d.client.DeletePodDisruptionBudget(ctx, existing); err != nil {
+			return fmt.Errorf("failed to delete gateway pod disruption budget: %w", err)
+		}
+		return nil
+	}
+
+	mutated := budget.DeepCopy()
+	updated, err := d.client.EnsureExists(ctx, mutated, func() error {
+		mutated.Labels = budget.Labels
+		mutated.Spec = budget.Spec
+		return d.client.SetControllerOwnership(gateway, mutated)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create or update gateway pod disruption budget: %w", err)
+	}
+
+	if updated && d.logger.IsTrace() {
+		data, err := json.MarshalIndent(mutated, "", "  ")
+		if err == nil {
+			d.logger.Trace("created or updated gateway pod disruption budget", "budget", string(data))
+		}
+	}
+
 	return nil
 }
 
@@ -218,8 +500,30 @@ func (d *GatewayDeployer) Deployment(namespace string, config apigwv1alpha1.Gate
 		Build(currentReplicas)
 }
 
+func (d *GatewayDeployer) DaemonSet(namespace string, config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) *apps.DaemonSet {
+	return builder.NewGatewayDeployment(gateway).
+		WithSDS(d.sdsHost, d.sdsPort).
+		WithClassConfig(config).
+		WithConsulCA(d.consulCA).
+		WithConsulGatewayNamespace(namespace).
+		WithPrimaryConsulDatacenter(d.primaryDatacenter).
+		BuildDaemonSet()
+}
+
 func (d *GatewayDeployer) Service(config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) *core.Service {
 	return builder.NewGatewayService(gateway).
 		WithClassConfig(config).
 		Build()
 }
+
+func (d *GatewayDeployer) HorizontalPodAutoscaler(config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) *autoscaling.HorizontalPodAutoscaler {
+	return builder.NewGatewayHorizontalPodAutoscaler(gateway).
+		WithClassConfig(config).
+		Build()
+}
+
+func (d *GatewayDeployer) PodDisruptionBudget(config apigwv1alpha1.GatewayClassConfig, gateway *gwv1beta1.Gateway) *policy.PodDisruptionBudget {
+	return builder.NewGatewayPodDisruptionBudget(gateway).
+		WithClassConfig(config).
+		Build()
+}
@@ -0,0 +1,250 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
+	core "k8s.io/api/core/v1"
+	rbac "k8s.io/api/rbac/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/state"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
+)
+
+func TestDeployer_DaemonSetMode(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockClient(ctrl)
+
+	deployer := NewDeployer(DeployerConfig{
+		Client: client,
+		Logger: hclog.NewNullLogger(),
+	})
+
+	gw := &gwv1beta1.Gateway{
+		ObjectMeta: meta.ObjectMeta{Name: "gateway", Namespace: "default", UID: "uid"},
+	}
+	config := apigwv1alpha1.GatewayClassConfig{
+		Spec: apigwv1alpha1.GatewayClassConfigSpec{
+			DeploymentSpec: apigwv1alpha1.DeploymentSpec{Mode: apigwv1alpha1.DeploymentModeDaemonSet},
+		},
+	}
+	gateway := newK8sGateway(config, gw, state.InitialGatewayState(gw))
+
+	// the deployment from before the gateway switched modes is deleted
+	controller := true
+	deployment := &apps.Deployment{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            "gateway",
+			Namespace:       "default",
+			OwnerReferences: []meta.OwnerReference{{UID: "uid", Controller: &controller}},
+		},
+	}
+
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().CreateOrUpdateDaemonSet(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, daemonSet *apps.DaemonSet, _ ...func() error) (bool, error) {
+		assert.True(t, daemonSet.Spec.Template.Spec.HostNetwork)
+		return true, nil
+	})
+	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(deployment, nil)
+	client.EXPECT().DeleteDeployment(gomock.Any(), deployment).Return(nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
+	require.NoError(t, deployer.Deploy(context.Background(), gateway))
+
+	// a deployment we didn't create is left alone
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().CreateOrUpdateDaemonSet(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(&apps.Deployment{
+		Spec: apps.DeploymentSpec{Replicas: pointer.Int32(1)},
+	}, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
+	require.NoError(t, deployer.Deploy(context.Background(), gateway))
+}
+
+func TestDeployer_DeploymentModeDeletesDaemonSet(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockClient(ctrl)
+
+	deployer := NewDeployer(DeployerConfig{
+		Client: client,
+		Logger: hclog.NewNullLogger(),
+	})
+
+	gw := &gwv1beta1.Gateway{
+		ObjectMeta: meta.ObjectMeta{Name: "gateway", Namespace: "default", UID: "uid"},
+	}
+	gateway := newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
+
+	controller := true
+	daemonSet := &apps.DaemonSet{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            "gateway",
+			Namespace:       "default",
+			OwnerReferences: []meta.OwnerReference{{UID: "uid", Controller: &controller}},
+		},
+	}
+
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(daemonSet, nil)
+	client.EXPECT().DeleteDaemonSet(gomock.Any(), daemonSet).Return(nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
+	require.NoError(t, deployer.Deploy(context.Background(), gateway))
+}
+
+func TestDeployer_EnsureServiceAccountRole(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockClient(ctrl)
+
+	deployer := NewDeployer(DeployerConfig{
+		Client: client,
+		Logger: hclog.NewNullLogger(),
+	})
+
+	gw := &gwv1beta1.Gateway{
+		ObjectMeta: meta.ObjectMeta{Name: "gateway", Namespace: "default", UID: "uid"},
+	}
+	config := apigwv1alpha1.GatewayClassConfig{
+		Spec: apigwv1alpha1.GatewayClassConfigSpec{
+			ConsulSpec: apigwv1alpha1.ConsulSpec{
+				AuthSpec: apigwv1alpha1.AuthSpec{Managed: true, PodSecurityPolicy: "psp"},
+			},
+		},
+	}
+
+	// ensureRole stubs EnsureExists for the gateway's role as if the given role
+	// already existed, running the deployer's mutator against it
+	ensureRole := func(existing *rbac.Role) *gomock.Call {
+		return client.EXPECT().EnsureExists(gomock.Any(), gomock.AssignableToTypeOf(&rbac.Role{}), gomock.Any()).DoAndReturn(
+			func(_ context.Context, object ctrlclient.Object, mutators ...func() error) (bool, error) {
+				role := object.(*rbac.Role)
+				if existing != nil {
+					existing.DeepCopyInto(role)
+				}
+				for _, mutate := range mutators {
+					if err := mutate(); err != nil {
+						return false, err
+					}
+				}
+				return true, nil
+			})
+	}
+
+	// new roles are owned by the gateway
+	client.EXPECT().EnsureServiceAccount(gomock.Any(), gw, gomock.Any()).Return(nil)
+	ensureRole(nil)
+	client.EXPECT().SetControllerOwnership(gw, gomock.Any()).Return(nil)
+	client.EXPECT().EnsureExists(gomock.Any(), gomock.AssignableToTypeOf(&rbac.RoleBinding{})).Return(true, nil)
+	require.NoError(t, deployer.ensureServiceAccount(context.Background(), config, gw))
+
+	// the rules of roles the gateway owns are kept in sync
+	controller := true
+	client.EXPECT().EnsureServiceAccount(gomock.Any(), gw, gomock.Any()).Return(nil)
+	ensureRole(&rbac.Role{
+		ObjectMeta: meta.ObjectMeta{
+			Name:            "gateway",
+			Namespace:       "default",
+			ResourceVersion: "1",
+			OwnerReferences: []meta.OwnerReference{{UID: "uid", Controller: &controller}},
+		},
+	})
+	client.EXPECT().EnsureExists(gomock.Any(), gomock.AssignableToTypeOf(&rbac.RoleBinding{})).Return(true, nil)
+	require.NoError(t, deployer.ensureServiceAccount(context.Background(), config, gw))
+
+	// roles someone else created aren't granted the config's rules
+	client.EXPECT().EnsureServiceAccount(gomock.Any(), gw, gomock.Any()).Return(nil)
+	ensureRole(&rbac.Role{
+		ObjectMeta: meta.ObjectMeta{Name: "gateway", Namespace: "default", ResourceVersion: "1"},
+	})
+	require.Error(t, deployer.ensureServiceAccount(context.Background(), config, gw))
+}
+
+func TestDeployer_EnsureCertificates(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockClient(ctrl)
+
+	deployer := NewDeployer(DeployerConfig{
+		Client: client,
+		Logger: hclog.NewNullLogger(),
+	})
+
+	hostname := gwv1beta1.Hostname("example.com")
+	gw := &gwv1beta1.Gateway{
+		ObjectMeta: meta.ObjectMeta{Name: "gateway", Namespace: "default", UID: "uid"},
+		Spec: gwv1beta1.GatewaySpec{
+			Listeners: []gwv1beta1.Listener{{
+				Name:     "https",
+				Hostname: &hostname,
+				Protocol: gwv1beta1.HTTPSProtocolType,
+				TLS: &gwv1beta1.GatewayTLSConfig{
+					CertificateRefs: []gwv1beta1.SecretObjectReference{{Name: "secret"}},
+					Options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+						utils.CertManagerIssuerOption: "issuer",
+					},
+				},
+			}},
+		},
+	}
+	config := apigwv1alpha1.GatewayClassConfig{
+		Spec: apigwv1alpha1.GatewayClassConfigSpec{
+			CertManagerSpec: &apigwv1alpha1.CertManagerSpec{
+				AllowedIssuers: []apigwv1alpha1.CertManagerIssuerReference{{Name: "issuer"}},
+			},
+		},
+	}
+	key := types.NamespacedName{Namespace: "default", Name: "gateway-https"}
+	secretKey := types.NamespacedName{Namespace: "default", Name: "secret"}
+
+	// issuers the class config doesn't allow don't get certificates
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gw).Return(nil, nil)
+	require.NoError(t, deployer.ensureCertificates(context.Background(), apigwv1alpha1.GatewayClassConfig{}, gw))
+
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gw).Return(nil, nil)
+	client.EXPECT().GetCertManagerCertificate(gomock.Any(), key).Return(nil, nil)
+	client.EXPECT().GetSecret(gomock.Any(), secretKey).Return(nil, nil)
+	client.EXPECT().EnsureExists(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+	require.NoError(t, deployer.ensureCertificates(context.Background(), config, gw))
+
+	// existing secrets that cert-manager didn't issue for the certificate aren't overwritten
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gw).Return(nil, nil)
+	client.EXPECT().GetCertManagerCertificate(gomock.Any(), key).Return(nil, nil)
+	client.EXPECT().GetSecret(gomock.Any(), secretKey).Return(&core.Secret{Type: core.SecretTypeTLS}, nil)
+	require.NoError(t, deployer.ensureCertificates(context.Background(), config, gw))
+
+	// existing certificates the gateway doesn't own aren't taken over
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gw).Return(nil, nil)
+	client.EXPECT().GetCertManagerCertificate(gomock.Any(), key).Return(&unstructured.Unstructured{Object: map[string]interface{}{}}, nil)
+	require.NoError(t, deployer.ensureCertificates(context.Background(), config, gw))
+}
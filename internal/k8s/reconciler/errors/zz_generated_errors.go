@@ -11,6 +11,7 @@ const (
 	CertificateResolutionErrorTypeNotFound     CertificateResolutionErrorType = "NotFoundError"
 	CertificateResolutionErrorTypeNotPermitted CertificateResolutionErrorType = "NotPermittedError"
 	CertificateResolutionErrorTypeUnsupported  CertificateResolutionErrorType = "UnsupportedError"
+	CertificateResolutionErrorTypeInvalid      CertificateResolutionErrorType = "InvalidError"
 )
 
 type CertificateResolutionError struct {
@@ -27,6 +28,9 @@ func NewCertificateResolutionErrorNotPermitted(inner string) CertificateResoluti
 func NewCertificateResolutionErrorUnsupported(inner string) CertificateResolutionError {
 	return CertificateResolutionError{inner, CertificateResolutionErrorTypeUnsupported}
 }
+func NewCertificateResolutionErrorInvalid(inner string) CertificateResolutionError {
+	return CertificateResolutionError{inner, CertificateResolutionErrorTypeInvalid}
+}
 
 func (r CertificateResolutionError) Error() string {
 	return r.inner
@@ -22,6 +22,8 @@ func TestCertificateResolutionErrorType(t *testing.T) {
 	require.Equal(t, CertificateResolutionErrorTypeNotPermitted, NewCertificateResolutionErrorNotPermitted(expected).Kind())
 	require.Equal(t, expected, NewCertificateResolutionErrorUnsupported(expected).Error())
 	require.Equal(t, CertificateResolutionErrorTypeUnsupported, NewCertificateResolutionErrorUnsupported(expected).Kind())
+	require.Equal(t, expected, NewCertificateResolutionErrorInvalid(expected).Error())
+	require.Equal(t, CertificateResolutionErrorTypeInvalid, NewCertificateResolutionErrorInvalid(expected).Kind())
 }
 
 func TestBindErrorType(t *testing.T) {
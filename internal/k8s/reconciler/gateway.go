@@ -52,7 +52,8 @@ func (g *K8sGateway) Resolve() core.ResolvedGateway {
 	var listeners []core.ResolvedListener
 	for i, listener := range g.Gateway.Spec.Listeners {
 		state := g.GatewayState.Listeners[i]
-		if state.Valid() {
+		// pending listeners, i.e. those waiting on a certificate, can't serve traffic yet
+		if state.Valid() && state.Status.Ready.Pending == nil {
 			listeners = append(listeners, g.resolveListener(state, listener))
 		}
 	}
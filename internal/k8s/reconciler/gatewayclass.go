@@ -6,6 +6,7 @@ package reconciler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/hashicorp/go-hclog"
 
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/builder"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	rstatus "github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/status"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
@@ -119,6 +121,10 @@ func (c *K8sGatewayClass) Validate(ctx context.Context) error {
 			c.status.Accepted.InvalidParameters = errors.New("gateway class not found")
 			return nil
 		}
+		if err := builder.ValidateConfig(&gwv1beta1.Gateway{}, *found); err != nil {
+			c.status.Accepted.InvalidParameters = fmt.Errorf("invalid gateway class configuration: %w", err)
+			return nil
+		}
 		c.config = *found
 		// clear out any accepted errors
 		c.status.Accepted = rstatus.GatewayClassAcceptedStatus{}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
@@ -71,6 +72,18 @@ func TestGatewayClassValidate(t *testing.T) {
 	require.Equal(t, rstatus.GatewayClassConditionReasonInvalidParameters, gatewayClass.status.Accepted.Condition(0).Reason)
 	require.False(t, gatewayClass.IsValid())
 
+	invalid := &apigwv1alpha1.GatewayClassConfig{
+		Spec: apigwv1alpha1.GatewayClassConfigSpec{
+			PodTemplate: &apigwv1alpha1.PodTemplateSpec{
+				Containers: []core.Container{{Name: "consul-api-gateway", Image: "image"}},
+			},
+		},
+	}
+	client.EXPECT().GetGatewayClassConfig(gomock.Any(), gomock.Any()).Return(invalid, nil)
+	require.NoError(t, gatewayClass.Validate(context.Background()))
+	require.Equal(t, rstatus.GatewayClassConditionReasonInvalidParameters, gatewayClass.status.Accepted.Condition(0).Reason)
+	require.False(t, gatewayClass.IsValid())
+
 	config := &apigwv1alpha1.GatewayClassConfig{}
 	client.EXPECT().GetGatewayClassConfig(gomock.Any(), gomock.Any()).Return(config, nil)
 	require.NoError(t, gatewayClass.Validate(context.Background()))
@@ -5,9 +5,16 @@ package reconciler
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/apimachinery/pkg/types"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -17,18 +24,29 @@ import (
 	"github.com/hashicorp/consul-api-gateway/internal/common"
 	"github.com/hashicorp/consul-api-gateway/internal/consul"
 	"github.com/hashicorp/consul-api-gateway/internal/core"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/builder"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/status"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/validator"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	"github.com/hashicorp/consul-api-gateway/internal/metrics"
 	"github.com/hashicorp/consul-api-gateway/internal/store"
+	"github.com/hashicorp/consul-api-gateway/internal/vault"
 	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
 //go:generate mockgen -source ./manager.go -destination ./mocks/manager.go -package mocks ReconcileManager
 
 const (
-	annotationConfig = "api-gateway.consul.hashicorp.com/config"
+	annotationConfig           = "api-gateway.consul.hashicorp.com/config"
+	annotationConfigGeneration = "api-gateway.consul.hashicorp.com/config-generation"
+	// the class config a gateway's config was rendered from before any GatewayConfig
+	// overrides were merged in
+	annotationClassConfig = "api-gateway.consul.hashicorp.com/class-config"
+	// an HMAC of the above annotations, since anyone who can edit a gateway can
+	// edit them, config that the controller didn't sign is never trusted
+	annotationConfigSignature = "api-gateway.consul.hashicorp.com/config-signature"
 )
 
 type ReconcileManager interface {
@@ -57,11 +75,18 @@ type GatewayReconcileManager struct {
 	gatewayClasses   *K8sGatewayClasses
 	gatewayValidator *validator.GatewayValidator
 	routeValidator   *validator.RouteValidator
+	catalogWatcher   *consul.CatalogWatcher
 
 	consulNamespaceMapper common.ConsulNamespaceMapper
 
+	requeueGateway func(name types.NamespacedName)
+
+	configSigningKey []byte
+
 	namespaceMap map[types.NamespacedName]string
-	// guards the above map
+	// pending revalidations of gateways whose listener certificates near expiry
+	certificateTimers map[types.NamespacedName]*time.Timer
+	// guards the above maps
 	mutex sync.RWMutex
 }
 
@@ -79,6 +104,16 @@ type ManagerConfig struct {
 	Logger                   hclog.Logger
 	ConsulNamespaceMapper    common.ConsulNamespaceMapper
 	ConsulNamespaceMirroring bool
+	// CatalogWatcher, if set, is used to re-resolve routes whenever the
+	// Consul services they reference are registered or deregistered
+	CatalogWatcher *consul.CatalogWatcher
+	// RequeueGateway, if set, is called to revalidate a gateway once one of
+	// its listener certificates nears or reaches its expiry
+	RequeueGateway func(name types.NamespacedName)
+	// VaultCertificatePolicy restricts what listeners' VaultCertificates can reference
+	VaultCertificatePolicy vault.CertificatePolicy
+	// ConfigSigningKey signs the config snapshotted onto gateways
+	ConfigSigningKey []byte
 }
 
 func NewReconcileManager(config ManagerConfig) *GatewayReconcileManager {
@@ -95,15 +130,19 @@ func NewReconcileManager(config ManagerConfig) *GatewayReconcileManager {
 	})
 
 	return &GatewayReconcileManager{
+		catalogWatcher:        config.CatalogWatcher,
+		certificateTimers:     make(map[types.NamespacedName]*time.Timer),
 		client:                config.Client,
+		configSigningKey:      config.ConfigSigningKey,
 		consul:                config.Consul,
 		consulCA:              config.ConsulCA,
 		consulNamespaceMapper: config.ConsulNamespaceMapper,
 		deployer:              deployer,
 		logger:                config.Logger,
 		gatewayClasses:        NewK8sGatewayClasses(config.Logger.Named("gatewayclasses"), config.Client),
-		gatewayValidator:      validator.NewGatewayValidator(config.Client),
+		gatewayValidator:      validator.NewGatewayValidator(config.Client, config.VaultCertificatePolicy),
 		namespaceMap:          make(map[types.NamespacedName]string),
+		requeueGateway:        config.RequeueGateway,
 		routeValidator:        validator.NewRouteValidator(resolver, config.Client),
 		sdsHost:               config.SDSHost,
 		sdsPort:               config.SDSPort,
@@ -138,14 +177,15 @@ func (m *GatewayReconcileManager) UpsertGateway(ctx context.Context, g *gwv1beta
 		g.Annotations = map[string]string{}
 	}
 
-	annotated := false
-
 	// first check to see whether we have our initial configuration as a gateway annotation
 	if annotatedConfig, ok := g.Annotations[annotationConfig]; ok {
-		if err := json.Unmarshal([]byte(annotatedConfig), &config.Spec); err != nil {
+		if !m.configSigned(g) {
+			// re-render the gateway from its class config rather than running config
+			// that someone other than the controller wrote
+			m.logger.Warn("ignoring GatewayClassConfig annotation without a valid signature", "gateway", g.Name, "namespace", g.Namespace)
+		} else if err := json.Unmarshal([]byte(annotatedConfig), &config.Spec); err != nil {
 			m.logger.Warn("error unmarshaling GatewayClassConfig annotation, skipping")
 		} else {
-			annotated = true
 			managed = true
 		}
 	}
@@ -164,20 +204,55 @@ func (m *GatewayReconcileManager) UpsertGateway(ctx context.Context, g *gwv1beta
 				return nil
 			}
 		}
+		base := config
+		// any problems with the gateway's overrides get reported once it's annotated
+		if _, err := m.applyGatewayConfig(ctx, g, &config); err != nil {
+			return err
+		}
+		// if we're annotating the gateway for the first time, update the gateway and return
+		// the upsert will get handled next reconciliation loop which should get triggered
+		// because of the call to Update
+		return m.annotateConfig(ctx, g, base, config, true)
 	}
 
-	// at this point we have a managed gateway and its configuration, make sure we set the
-	// configuration annotation
-	marshaled, err := json.Marshal(config.Spec)
+	// check whether the gateway is running the latest version of its class config
+	// and whether it should be re-rendered from it
+	latest, latestFound, err := m.client.GetConfigForGatewayClassName(ctx, gatewayClassName)
 	if err != nil {
 		return err
 	}
-	g.Annotations[annotationConfig] = string(marshaled)
-	if !annotated {
-		// if we're annotating the gateway for the first time, update the gateway and return
-		// the upsert will get handled next reconciliation loop which should get triggered
-		// because of the call to Update
-		return m.client.Update(ctx, g)
+	followed := latestFound && m.followsConfig(g, latest)
+
+	// gateways following their class config are rendered from the live config, all
+	// others from the signed snapshot of the class config they were rendered from,
+	// gateways annotated before it was tracked separately from their overrides fall
+	// back to their running config
+	base := config
+	if followed {
+		base = latest
+	} else if annotatedBase, ok := g.Annotations[annotationClassConfig]; ok {
+		var spec apigwv1alpha1.GatewayClassConfigSpec
+		if err := json.Unmarshal([]byte(annotatedBase), &spec); err != nil {
+			m.logger.Warn("error unmarshaling class config annotation, using the gateway's config")
+		} else {
+			base.Spec = spec
+		}
+	}
+
+	// GatewayConfig changes apply regardless of the class config's update strategy,
+	// while an invalid override leaves the gateway running its last valid config
+	merged := base
+	invalidOverride, err := m.applyGatewayConfig(ctx, g, &merged)
+	if err != nil {
+		return err
+	}
+	if invalidOverride == "" && (followed || specChanged(g.Annotations[annotationConfig], merged)) {
+		if followed {
+			m.logger.Info("updating gateway to latest GatewayClassConfig", "gateway", g.Name, "namespace", g.Namespace, "generation", latest.Generation)
+		} else {
+			m.logger.Info("updating gateway to its latest GatewayConfig", "gateway", g.Name, "namespace", g.Namespace)
+		}
+		return m.annotateConfig(ctx, g, base, merged, followed)
 	}
 
 	consulNamespace := m.consulNamespaceMapper(g.GetNamespace())
@@ -188,11 +263,16 @@ func (m *GatewayReconcileManager) UpsertGateway(ctx context.Context, g *gwv1beta
 	// external references and set the statuses accordingly. Since we actually
 	// have other object updates triggering reconciliation loops, this is necessary
 	// prior to dirty-checking on upsert.
-	state, err := m.gatewayValidator.Validate(ctx, g, m.deployer.Service(config, g))
+	state, err := m.gatewayValidator.Validate(ctx, g, config, m.deployer.Service(config, g))
 	if err != nil {
 		return err
 	}
 	state.ConsulNamespace = consulNamespace
+	m.scheduleCertificateRecheck(utils.NamespacedName(g), state.CertificateRecheck())
+	if latestFound {
+		state.Status.ConfigUpToDate = configStatus(g, latest)
+		state.Status.ConfigUpToDate.InvalidGatewayConfig = invalidOverride
+	}
 
 	gateway := newK8sGateway(config, g, state)
 
@@ -204,6 +284,136 @@ func (m *GatewayReconcileManager) UpsertGateway(ctx context.Context, g *gwv1beta
 	})
 }
 
+// applyGatewayConfig merges the GatewayConfig referenced by a gateway over its class
+// config. If the GatewayConfig doesn't exist or can't be applied, the class config is
+// left as-is and the reason is returned.
+func (m *GatewayReconcileManager) applyGatewayConfig(ctx context.Context, g *gwv1beta1.Gateway, config *apigwv1alpha1.GatewayClassConfig) (string, error) {
+	name, ok := g.Annotations[apigwv1alpha1.GatewayConfigAnnotation]
+	if !ok {
+		return "", nil
+	}
+
+	override, err := m.client.GetGatewayConfig(ctx, types.NamespacedName{Name: name, Namespace: g.Namespace})
+	if err != nil {
+		return "", err
+	}
+	if override == nil {
+		return fmt.Sprintf("GatewayConfig %q not found", name), nil
+	}
+
+	merged, err := config.WithOverrides(override)
+	if err != nil {
+		return err.Error(), nil
+	}
+	if err := builder.ValidateConfig(g, merged); err != nil {
+		return fmt.Sprintf("invalid GatewayConfig %q: %v", name, err), nil
+	}
+
+	*config = merged
+	return "", nil
+}
+
+// followsConfig returns whether a gateway should be re-rendered from the latest version
+// of its class config, which only happens when the config opts into it and is valid
+func (m *GatewayReconcileManager) followsConfig(g *gwv1beta1.Gateway, latest apigwv1alpha1.GatewayClassConfig) bool {
+	if latest.Spec.UpdateStrategy != apigwv1alpha1.UpdateStrategyFollow {
+		return false
+	}
+	if !configChanged(g, latest) {
+		return false
+	}
+	if err := builder.ValidateConfig(g, latest); err != nil {
+		m.logger.Warn("not updating gateway to invalid GatewayClassConfig", "gateway", g.Name, "namespace", g.Namespace, "error", err)
+		return false
+	}
+	return true
+}
+
+// annotateConfig records the config a gateway runs, along with the class config it was
+// rendered from, and updates the gateway so that it's reconciled again with them
+func (m *GatewayReconcileManager) annotateConfig(ctx context.Context, g *gwv1beta1.Gateway, base, config apigwv1alpha1.GatewayClassConfig, updateGeneration bool) error {
+	marshaled, err := json.Marshal(config.Spec)
+	if err != nil {
+		return err
+	}
+	marshaledBase, err := json.Marshal(base.Spec)
+	if err != nil {
+		return err
+	}
+	g.Annotations[annotationConfig] = string(marshaled)
+	g.Annotations[annotationClassConfig] = string(marshaledBase)
+	if updateGeneration {
+		g.Annotations[annotationConfigGeneration] = strconv.FormatInt(base.Generation, 10)
+	}
+	g.Annotations[annotationConfigSignature] = m.signConfig(g)
+	return m.client.Update(ctx, g)
+}
+
+// signConfig returns the signature of the config annotations on a gateway, binding
+// them to the gateway so that they can't be copied over from another one
+func (m *GatewayReconcileManager) signConfig(g *gwv1beta1.Gateway) string {
+	mac := hmac.New(sha256.New, m.configSigningKey)
+	for _, value := range []string{
+		g.Namespace,
+		g.Name,
+		g.Annotations[annotationConfig],
+		g.Annotations[annotationClassConfig],
+		g.Annotations[annotationConfigGeneration],
+	} {
+		// length-prefix each value so that they can't be shifted between fields
+		fmt.Fprintf(mac, "%d:%s", len(value), value)
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// configSigned returns whether the config annotations on a gateway were signed by the controller
+func (m *GatewayReconcileManager) configSigned(g *gwv1beta1.Gateway) bool {
+	signature, err := base64.StdEncoding.DecodeString(g.Annotations[annotationConfigSignature])
+	if err != nil {
+		return false
+	}
+	expected, err := base64.StdEncoding.DecodeString(m.signConfig(g))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(signature, expected)
+}
+
+// configChanged returns whether the class config a gateway was rendered from differs from the given config
+func configChanged(g *gwv1beta1.Gateway, config apigwv1alpha1.GatewayClassConfig) bool {
+	annotated, ok := g.Annotations[annotationClassConfig]
+	if !ok {
+		annotated = g.Annotations[annotationConfig]
+	}
+	return specChanged(annotated, config)
+}
+
+// specChanged returns whether an annotated config differs from the given config
+func specChanged(annotated string, config apigwv1alpha1.GatewayClassConfig) bool {
+	marshaled, err := json.Marshal(config.Spec)
+	if err != nil {
+		return true
+	}
+	return annotated != string(marshaled)
+}
+
+// configStatus returns the status of the config generation that a gateway is running
+func configStatus(g *gwv1beta1.Gateway, latest apigwv1alpha1.GatewayClassConfig) status.GatewayConfigUpToDateStatus {
+	if !configChanged(g, latest) {
+		return status.GatewayConfigUpToDateStatus{
+			LatestGeneration: fmt.Sprintf("running GatewayClassConfig generation %d", latest.Generation),
+		}
+	}
+	generation, ok := g.Annotations[annotationConfigGeneration]
+	if !ok {
+		// gateways annotated before we started tracking generations
+		generation = "unknown"
+	}
+	return status.GatewayConfigUpToDateStatus{
+		OutdatedGeneration: fmt.Sprintf("running GatewayClassConfig generation %s, latest generation is %d", generation, latest.Generation),
+	}
+}
+
 func (m *GatewayReconcileManager) UpsertHTTPRoute(ctx context.Context, r *gwv1alpha2.HTTPRoute) error {
 	return m.upsertRoute(ctx, r, r.Spec.ParentRefs, HTTPRouteID(utils.NamespacedName(r)))
 }
@@ -231,6 +441,7 @@ func (m *GatewayReconcileManager) upsertRoute(ctx context.Context, r Route, pare
 	if err != nil {
 		return err
 	}
+	m.watchRouteServices(id, state.ConsulServices)
 
 	route := newK8sRoute(r, state)
 
@@ -259,16 +470,46 @@ func (m *GatewayReconcileManager) DeleteGateway(ctx context.Context, name types.
 	}
 
 	delete(m.namespaceMap, name)
+	m.scheduleCertificateRecheck(name, time.Time{})
+	metrics.ListenerCertificateExpiry.DeletePartialMatch(prometheus.Labels{"namespace": name.Namespace, "gateway": name.Name})
 
 	return nil
 }
 
+// scheduleCertificateRecheck replaces any pending revalidation of the gateway
+// with one at the given time, a zero time cancels it
+func (m *GatewayReconcileManager) scheduleCertificateRecheck(name types.NamespacedName, at time.Time) {
+	if timer, ok := m.certificateTimers[name]; ok {
+		timer.Stop()
+		delete(m.certificateTimers, name)
+	}
+	if at.IsZero() || m.requeueGateway == nil {
+		return
+	}
+	m.certificateTimers[name] = time.AfterFunc(time.Until(at), func() {
+		m.requeueGateway(name)
+	})
+}
+
 func (m *GatewayReconcileManager) DeleteHTTPRoute(ctx context.Context, name types.NamespacedName) error {
-	return m.store.DeleteRoute(ctx, HTTPRouteID(name))
+	id := HTTPRouteID(name)
+	m.watchRouteServices(id, nil)
+	return m.store.DeleteRoute(ctx, id)
 }
 
 func (m *GatewayReconcileManager) DeleteTCPRoute(ctx context.Context, name types.NamespacedName) error {
-	return m.store.DeleteRoute(ctx, TCPRouteID(name))
+	id := TCPRouteID(name)
+	m.watchRouteServices(id, nil)
+	return m.store.DeleteRoute(ctx, id)
+}
+
+// watchRouteServices replaces the set of Consul services watched on behalf of
+// the route, an empty set stops watching entirely
+func (m *GatewayReconcileManager) watchRouteServices(id string, services []consul.WatchedService) {
+	if m.catalogWatcher == nil {
+		return
+	}
+	m.catalogWatcher.Watch(id, services...)
 }
 
 func (m *GatewayReconcileManager) deleteUnmanagedRoute(ctx context.Context, namespace string, parents []gwv1alpha2.ParentReference, id string) (bool, error) {
@@ -285,6 +526,7 @@ func (m *GatewayReconcileManager) deleteUnmanagedRoute(ctx context.Context, name
 	if !managed {
 		// we're not managing this route (potentially reference got removed on an update)
 		// ensure it's cleaned up
+		m.watchRouteServices(id, nil)
 		if err := m.store.DeleteRoute(ctx, id); err != nil {
 			return false, err
 		}
@@ -7,9 +7,12 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
@@ -17,6 +20,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
+	"github.com/hashicorp/consul-api-gateway/internal/metrics"
 	storeMocks "github.com/hashicorp/consul-api-gateway/internal/store/mocks"
 	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
@@ -70,6 +74,7 @@ func TestUpsertGateway(t *testing.T) {
 		Logger:                hclog.NewNullLogger(),
 		Store:                 store,
 		ConsulNamespaceMapper: testNamespaceMapper,
+		ConfigSigningKey:      []byte("key"),
 	})
 
 	inner := &gwv1beta1.Gateway{}
@@ -92,12 +97,172 @@ func TestUpsertGateway(t *testing.T) {
 	require.NotEmpty(t, inner.Annotations[annotationConfig])
 
 	// validation
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(apigwv1alpha1.GatewayClassConfig{}, false, expected)
+	require.Equal(t, expected, manager.UpsertGateway(context.Background(), inner))
+
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(apigwv1alpha1.GatewayClassConfig{}, true, nil)
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, expected)
 	require.Equal(t, expected, manager.UpsertGateway(context.Background(), inner))
 
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(apigwv1alpha1.GatewayClassConfig{}, true, nil)
+	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	store.EXPECT().UpsertGateway(gomock.Any(), gomock.Any(), gomock.Any())
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+
+	// snapshotted config changes only show up in the status
+	snapshot := apigwv1alpha1.GatewayClassConfig{
+		ObjectMeta: meta.ObjectMeta{Generation: 2},
+		Spec:       apigwv1alpha1.GatewayClassConfigSpec{LogLevel: "debug"},
+	}
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(snapshot, true, nil)
+	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	store.EXPECT().UpsertGateway(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, gateway interface{}, _ interface{}) error {
+		configStatus := gateway.(*K8sGateway).GatewayState.Status.ConfigUpToDate
+		require.Equal(t, "running GatewayClassConfig generation 0, latest generation is 2", configStatus.OutdatedGeneration)
+		return nil
+	})
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+
+	// followed config changes are rendered onto the gateway
+	follow := *snapshot.DeepCopy()
+	follow.Spec.UpdateStrategy = apigwv1alpha1.UpdateStrategyFollow
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(follow, true, nil)
+	client.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+	require.Contains(t, inner.Annotations[annotationConfig], `"logLevel":"debug"`)
+	require.Equal(t, "2", inner.Annotations[annotationConfigGeneration])
+
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(follow, true, nil)
+	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	store.EXPECT().UpsertGateway(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, gateway interface{}, _ interface{}) error {
+		configStatus := gateway.(*K8sGateway).GatewayState.Status.ConfigUpToDate
+		require.Equal(t, "running GatewayClassConfig generation 2", configStatus.LatestGeneration)
+		return nil
+	})
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+
+	// invalid configs aren't followed
+	invalid := *follow.DeepCopy()
+	invalid.Generation = 3
+	invalid.Spec.PodTemplate = &apigwv1alpha1.PodTemplateSpec{
+		Containers: []core.Container{{Name: "sidecar"}},
+	}
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(invalid, true, nil)
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
 	store.EXPECT().UpsertGateway(gomock.Any(), gomock.Any(), gomock.Any())
 	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+	require.Equal(t, "2", inner.Annotations[annotationConfigGeneration])
+
+	// config annotations edited by anyone but the controller are re-rendered from the class config
+	inner.Annotations[annotationConfig] = `{"logLevel":"trace"}`
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(snapshot, true, nil)
+	client.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+	require.Contains(t, inner.Annotations[annotationConfig], `"logLevel":"debug"`)
+	require.True(t, manager.configSigned(inner))
+
+	// as are signed annotations copied over from another gateway
+	other := inner.DeepCopy()
+	other.Name = "other"
+	require.False(t, manager.configSigned(other))
+}
+
+func TestUpsertGatewayOverrides(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockClient(ctrl)
+	store := storeMocks.NewMockStore(ctrl)
+
+	manager := NewReconcileManager(ManagerConfig{
+		Client:                client,
+		Logger:                hclog.NewNullLogger(),
+		Store:                 store,
+		ConsulNamespaceMapper: testNamespaceMapper,
+	})
+
+	expected := errors.New("expected")
+	loadBalancer := core.ServiceTypeLoadBalancer
+	config := apigwv1alpha1.GatewayClassConfig{
+		Spec: apigwv1alpha1.GatewayClassConfigSpec{
+			AllowedOverrides: []apigwv1alpha1.GatewayConfigField{apigwv1alpha1.GatewayConfigFieldServiceType},
+		},
+	}
+	override := &apigwv1alpha1.GatewayConfig{
+		Spec: apigwv1alpha1.GatewayConfigSpec{ServiceType: &loadBalancer},
+	}
+	inner := &gwv1beta1.Gateway{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:   "namespace",
+			Annotations: map[string]string{apigwv1alpha1.GatewayConfigAnnotation: "override"},
+		},
+	}
+	key := types.NamespacedName{Name: "override", Namespace: "namespace"}
+
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(config, true, nil)
+	client.EXPECT().GetGatewayConfig(gomock.Any(), key).Return(nil, expected)
+	require.Equal(t, expected, manager.UpsertGateway(context.Background(), inner))
+
+	// overrides are merged into the annotated config
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(config, true, nil)
+	client.EXPECT().GetGatewayConfig(gomock.Any(), key).Return(override, nil)
+	client.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+	require.Contains(t, inner.Annotations[annotationConfig], `"serviceType":"LoadBalancer"`)
+
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(config, true, nil)
+	client.EXPECT().GetGatewayConfig(gomock.Any(), key).Return(override, nil)
+	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetService(gomock.Any(), gomock.Any()).Return(nil, nil)
+	store.EXPECT().UpsertGateway(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, gateway interface{}, _ interface{}) error {
+		configStatus := gateway.(*K8sGateway).GatewayState.Status.ConfigUpToDate
+		require.Empty(t, configStatus.InvalidGatewayConfig)
+		require.NotEmpty(t, configStatus.LatestGeneration)
+		return nil
+	})
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+
+	// override changes are applied even though the class config is snapshotted
+	nodePort := core.ServiceTypeNodePort
+	changed := override.DeepCopy()
+	changed.Spec.ServiceType = &nodePort
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(config, true, nil)
+	client.EXPECT().GetGatewayConfig(gomock.Any(), key).Return(changed, nil)
+	client.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+	require.Contains(t, inner.Annotations[annotationConfig], `"serviceType":"NodePort"`)
+	require.NotContains(t, inner.Annotations[annotationClassConfig], `"serviceType":`)
+
+	// overrides the class doesn't allow are reported and the last valid config is kept
+	disallowed := config.DeepCopy()
+	disallowed.Name = "config"
+	disallowed.Generation = 2
+	disallowed.Spec.AllowedOverrides = nil
+	disallowed.Spec.UpdateStrategy = apigwv1alpha1.UpdateStrategyFollow
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(*disallowed, true, nil)
+	client.EXPECT().GetGatewayConfig(gomock.Any(), key).Return(changed, nil)
+	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	store.EXPECT().UpsertGateway(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, gateway interface{}, _ interface{}) error {
+		configStatus := gateway.(*K8sGateway).GatewayState.Status.ConfigUpToDate
+		require.Equal(t, `GatewayClassConfig "config" does not allow overriding serviceType`, configStatus.InvalidGatewayConfig)
+		require.NotEmpty(t, configStatus.OutdatedGeneration)
+		return nil
+	})
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+	require.Contains(t, inner.Annotations[annotationConfig], `"serviceType":"NodePort"`)
+
+	// missing overrides are reported and the last valid config is kept
+	client.EXPECT().GetConfigForGatewayClassName(gomock.Any(), "").Return(config, true, nil)
+	client.EXPECT().GetGatewayConfig(gomock.Any(), key).Return(nil, nil)
+	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	store.EXPECT().UpsertGateway(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, gateway interface{}, _ interface{}) error {
+		configStatus := gateway.(*K8sGateway).GatewayState.Status.ConfigUpToDate
+		require.Equal(t, `GatewayConfig "override" not found`, configStatus.InvalidGatewayConfig)
+		return nil
+	})
+	require.NoError(t, manager.UpsertGateway(context.Background(), inner))
+	require.Contains(t, inner.Annotations[annotationConfig], `"serviceType":"NodePort"`)
 }
 
 func TestUpsertHTTPRoute(t *testing.T) {
@@ -211,6 +376,49 @@ func TestDeleteGateway(t *testing.T) {
 	require.NoError(t, manager.DeleteGateway(context.Background(), types.NamespacedName{}))
 }
 
+func TestDeleteGatewayCertificateRecheck(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := storeMocks.NewMockStore(ctrl)
+
+	requeued := make(chan types.NamespacedName, 1)
+	manager := NewReconcileManager(ManagerConfig{
+		Logger:                hclog.NewNullLogger(),
+		Store:                 store,
+		ConsulNamespaceMapper: testNamespaceMapper,
+		RequeueGateway: func(name types.NamespacedName) {
+			requeued <- name
+		},
+	})
+
+	name := types.NamespacedName{Name: "expiring", Namespace: "recheck"}
+
+	manager.scheduleCertificateRecheck(name, time.Now().Add(10*time.Millisecond))
+	select {
+	case requeued := <-requeued:
+		require.Equal(t, name, requeued)
+	case <-time.After(time.Second):
+		t.Fatal("gateway was not requeued")
+	}
+
+	// deleting the gateway cancels its recheck and drops its expiry series
+	manager.scheduleCertificateRecheck(name, time.Now().Add(50*time.Millisecond))
+	metrics.ListenerCertificateExpiry.WithLabelValues(name.Namespace, name.Name, "listener", "secret").Set(1)
+
+	store.EXPECT().DeleteGateway(gomock.Any(), gomock.Any()).Return(nil)
+	require.NoError(t, manager.DeleteGateway(context.Background(), name))
+	require.Empty(t, manager.certificateTimers)
+	require.False(t, metrics.ListenerCertificateExpiry.DeleteLabelValues(name.Namespace, name.Name, "listener", "secret"))
+
+	select {
+	case <-requeued:
+		t.Fatal("deleted gateway was requeued")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func TestDeleteHTTPRoute(t *testing.T) {
 	t.Parallel()
 
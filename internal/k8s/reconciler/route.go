@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
@@ -189,3 +190,20 @@ func HTTPRouteID(namespacedName types.NamespacedName) string {
 func TCPRouteID(namespacedName types.NamespacedName) string {
 	return "tcp-" + namespacedName.String()
 }
+
+// ParseRouteID is the inverse of HTTPRouteID and TCPRouteID, it returns the
+// kind and name of the route the id was generated for.
+func ParseRouteID(id string) (string, types.NamespacedName, bool) {
+	kind, name, found := strings.Cut(id, "-")
+	if !found || (kind != "http" && kind != "tcp") {
+		return "", types.NamespacedName{}, false
+	}
+	namespace, name, found := strings.Cut(name, "/")
+	if !found {
+		return "", types.NamespacedName{}, false
+	}
+	if kind == "http" {
+		return "HTTPRoute", types.NamespacedName{Namespace: namespace, Name: name}, true
+	}
+	return "TCPRoute", types.NamespacedName{Namespace: namespace, Name: name}, true
+}
@@ -42,6 +42,27 @@ func TestTCPRouteID(t *testing.T) {
 	require.Equal(t, "tcp-namespace/name", TCPRouteID(types.NamespacedName{Namespace: "namespace", Name: "name"}))
 }
 
+func TestParseRouteID(t *testing.T) {
+	t.Parallel()
+
+	name := types.NamespacedName{Namespace: "namespace", Name: "name"}
+
+	kind, parsed, ok := ParseRouteID(HTTPRouteID(name))
+	require.True(t, ok)
+	require.Equal(t, "HTTPRoute", kind)
+	require.Equal(t, name, parsed)
+
+	kind, parsed, ok = ParseRouteID(TCPRouteID(name))
+	require.True(t, ok)
+	require.Equal(t, "TCPRoute", kind)
+	require.Equal(t, name, parsed)
+
+	_, _, ok = ParseRouteID("udp-namespace/name")
+	require.False(t, ok)
+	_, _, ok = ParseRouteID("http-name")
+	require.False(t, ok)
+}
+
 func TestRouteCommonRouteSpec(t *testing.T) {
 	t.Parallel()
 
@@ -6,6 +6,8 @@ package state
 import (
 	"encoding/json"
 	"errors"
+	"net"
+	"time"
 
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
@@ -26,6 +28,21 @@ type GatewayState struct {
 	Listeners []*ListenerState
 }
 
+// CertificateRecheck returns the earliest time a listener's certificate needs
+// to be revalidated as it nears or passes its expiry, it's zero if none do
+func (g *GatewayState) CertificateRecheck() time.Time {
+	var recheck time.Time
+	for _, listener := range g.Listeners {
+		if listener.CertificateRecheck.IsZero() {
+			continue
+		}
+		if recheck.IsZero() || listener.CertificateRecheck.Before(recheck) {
+			recheck = listener.CertificateRecheck
+		}
+	}
+	return recheck
+}
+
 func InitialGatewayState(g *gwv1beta1.Gateway) *GatewayState {
 	state := &GatewayState{
 		Generation: g.GetGeneration(),
@@ -56,11 +73,15 @@ func (g *GatewayState) GetStatus(gateway *gwv1beta1.Gateway) gwv1beta1.GatewaySt
 		conditions = gateway.Status.Conditions
 	}
 
-	ipType := gwv1beta1.IPAddressType
 	addresses := make([]gwv1beta1.GatewayAddress, 0, len(g.Addresses))
 	for _, address := range g.Addresses {
+		// load balancers may be assigned hostnames rather than IPs
+		addressType := gwv1beta1.IPAddressType
+		if net.ParseIP(address) == nil {
+			addressType = gwv1beta1.HostnameAddressType
+		}
 		addresses = append(addresses, gwv1beta1.GatewayAddress{
-			Type:  &ipType,
+			Type:  &addressType,
 			Value: address,
 		})
 	}
@@ -112,6 +133,8 @@ type ListenerState struct {
 	Name     gwv1beta1.SectionName
 	TLS      core.TLSParams
 	Status   status.ListenerStatus
+	// CertificateRecheck is when the listener's certificate next needs validating
+	CertificateRecheck time.Time
 }
 
 func (l *ListenerState) Valid() bool {
@@ -6,6 +6,9 @@ package state
 import (
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	"github.com/hashicorp/consul/api"
+
+	"github.com/hashicorp/consul-api-gateway/internal/consul"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/common"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/status"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
@@ -16,6 +19,9 @@ type RouteState struct {
 	References       service.RouteRuleReferenceMap
 	ResolutionErrors *service.ResolutionErrors
 	ParentStatuses   status.RouteStatuses
+	// ConsulServices are the catalog services the route's backends resolve
+	// to, they are watched so that the route can be re-resolved on changes
+	ConsulServices []consul.WatchedService `json:",omitempty"`
 }
 
 func NewRouteState() *RouteState {
@@ -37,3 +43,18 @@ func (r *RouteState) Bound(ref gwv1alpha2.ParentReference) {
 func (r *RouteState) Remove(ref gwv1alpha2.ParentReference) {
 	r.ParentStatuses.Remove(common.AsJSON(ref))
 }
+
+// WatchConsulService records a Consul service that the route depends on and
+// whether it was registered when the route was resolved.
+func (r *RouteState) WatchConsulService(name api.CompoundServiceName, registered bool) {
+	for i, service := range r.ConsulServices {
+		if service.Name == name {
+			r.ConsulServices[i].Registered = service.Registered || registered
+			return
+		}
+	}
+	r.ConsulServices = append(r.ConsulServices, consul.WatchedService{
+		Name:       name,
+		Registered: registered,
+	})
+}
@@ -153,7 +153,7 @@ func Test{{ $status.Kind }}{{ $conditionType.Name }}Status(t *testing.T) {
 	{{ if not $conditionType.Ignore }}assert.False(t, status.HasError()){{ end }}
 
 	{{ range $error := $conditionType.Errors }}
-	status = {{ $status.Kind }}{{ $conditionType.Name }}Status{ {{ $error.Name }}: expected}
+	status = {{ $status.Kind }}{{ $conditionType.Name }}Status{ {{ $error.Name }}: {{ if $error.String }}expected.Error(){{ else }}expected{{ end }}}
 	assert.Equal(t, "expected", status.Condition(0).Message)
 	assert.Equal(t, {{ $status.Kind }}ConditionReason{{ $error.Name }}, status.Condition(0).Reason)
 	{{ if not $conditionType.Ignore }}assert.True(t, status.HasError()){{ end }}
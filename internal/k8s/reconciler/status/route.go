@@ -99,6 +99,8 @@ func (r RouteStatuses) BindFailed(resolutionErrors *service.ResolutionErrors, er
 		routeStatus.ResolvedRefs.InvalidKind = err
 	case service.BackendNotFoundErrorType:
 		routeStatus.ResolvedRefs.BackendNotFound = err
+	case service.PortNotFoundErrorType:
+		routeStatus.ResolvedRefs.PortNotFound = err
 	}
 
 	r[id] = routeStatus
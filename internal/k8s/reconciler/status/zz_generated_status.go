@@ -590,6 +590,148 @@ func (s GatewayInSyncStatus) HasError() bool {
 	return s.SyncError != nil
 }
 
+// GatewayConfigUpToDateStatus - This condition indicates whether the Gateway is
+// running the latest generation of its GatewayClassConfig. Gateways only follow
+// changes to their GatewayClassConfig when its updateStrategy is Follow,
+// otherwise they keep running the configuration they were created with.
+//
+// [custom]
+type GatewayConfigUpToDateStatus struct {
+	// This reason is used with the “ConfigUpToDate” condition when the
+	// GatewayConfig referenced by the Gateway doesn't exist or can't be applied, in
+	// which case the Gateway runs its GatewayClassConfig without any overrides.
+	//
+	// [custom]
+	InvalidGatewayConfig string
+	// This reason is used with the “ConfigUpToDate” condition when the Gateway
+	// is running the latest generation of its GatewayClassConfig, with the
+	// generation in the message.
+	//
+	// [custom]
+	LatestGeneration string
+	// This reason is used with the “ConfigUpToDate” condition when the Gateway
+	// is running an earlier generation of its GatewayClassConfig than the latest
+	// one.
+	//
+	// [custom]
+	OutdatedGeneration string
+}
+
+const (
+	// GatewayConditionConfigUpToDate - This condition indicates whether the Gateway
+	// is running the latest generation of its GatewayClassConfig. Gateways only
+	// follow changes to their GatewayClassConfig when its updateStrategy is Follow,
+	// otherwise they keep running the configuration they were created with.
+	//
+	// [custom]
+	GatewayConditionConfigUpToDate = "ConfigUpToDate"
+	// GatewayConditionReasonConfigUpToDate - This reason is used with the
+	// “ConfigUpToDate” condition when the condition is True.
+	//
+	// [custom]
+	GatewayConditionReasonConfigUpToDate = "ConfigUpToDate"
+	// GatewayConditionReasonInvalidGatewayConfig - This reason is used with the
+	// “ConfigUpToDate” condition when the GatewayConfig referenced by the
+	// Gateway doesn't exist or can't be applied, in which case the Gateway runs its
+	// GatewayClassConfig without any overrides.
+	//
+	// [custom]
+	GatewayConditionReasonInvalidGatewayConfig = "InvalidGatewayConfig"
+	// GatewayConditionReasonLatestGeneration - This reason is used with the
+	// “ConfigUpToDate” condition when the Gateway is running the latest
+	// generation of its GatewayClassConfig, with the generation in the message.
+	//
+	// [custom]
+	GatewayConditionReasonLatestGeneration = "LatestGeneration"
+	// GatewayConditionReasonOutdatedGeneration - This reason is used with the
+	// “ConfigUpToDate” condition when the Gateway is running an earlier
+	// generation of its GatewayClassConfig than the latest one.
+	//
+	// [custom]
+	GatewayConditionReasonOutdatedGeneration = "OutdatedGeneration"
+)
+
+// Condition returns the status condition of the GatewayConfigUpToDateStatus
+// based off of the underlying errors that are set.
+func (s GatewayConfigUpToDateStatus) Condition(generation int64) meta.Condition {
+	if s.InvalidGatewayConfig != "" {
+		return meta.Condition{
+			Type:               GatewayConditionConfigUpToDate,
+			Status:             meta.ConditionFalse,
+			Reason:             GatewayConditionReasonInvalidGatewayConfig,
+			Message:            s.InvalidGatewayConfig,
+			ObservedGeneration: generation,
+			LastTransitionTime: meta.Now(),
+		}
+	}
+
+	if s.LatestGeneration != "" {
+		return meta.Condition{
+			Type:               GatewayConditionConfigUpToDate,
+			Status:             meta.ConditionTrue,
+			Reason:             GatewayConditionReasonLatestGeneration,
+			Message:            s.LatestGeneration,
+			ObservedGeneration: generation,
+			LastTransitionTime: meta.Now(),
+		}
+	}
+
+	if s.OutdatedGeneration != "" {
+		return meta.Condition{
+			Type:               GatewayConditionConfigUpToDate,
+			Status:             meta.ConditionFalse,
+			Reason:             GatewayConditionReasonOutdatedGeneration,
+			Message:            s.OutdatedGeneration,
+			ObservedGeneration: generation,
+			LastTransitionTime: meta.Now(),
+		}
+	}
+
+	return meta.Condition{
+		Type:               GatewayConditionConfigUpToDate,
+		Status:             meta.ConditionTrue,
+		Reason:             GatewayConditionReasonConfigUpToDate,
+		Message:            "ConfigUpToDate",
+		ObservedGeneration: generation,
+		LastTransitionTime: meta.Now(),
+	}
+}
+
+// MarshalJSON marshals a GatewayConfigUpToDateStatus value to JSON
+func (s GatewayConfigUpToDateStatus) MarshalJSON() ([]byte, error) {
+	data := map[string]string{}
+
+	data["InvalidGatewayConfig"] = s.InvalidGatewayConfig
+
+	data["LatestGeneration"] = s.LatestGeneration
+
+	data["OutdatedGeneration"] = s.OutdatedGeneration
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON unmarshals a GatewayConfigUpToDateStatus from JSON
+func (s *GatewayConfigUpToDateStatus) UnmarshalJSON(b []byte) error {
+	data := map[string]string{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	s.InvalidGatewayConfig = data["InvalidGatewayConfig"]
+
+	s.LatestGeneration = data["LatestGeneration"]
+
+	s.OutdatedGeneration = data["OutdatedGeneration"]
+
+	return nil
+}
+
+// HasError returns whether any of the GatewayConfigUpToDateStatus errors are
+// set.
+func (s GatewayConfigUpToDateStatus) HasError() bool {
+	return s.InvalidGatewayConfig != "" || s.LatestGeneration != "" || s.OutdatedGeneration != ""
+}
+
 // GatewayStatus - Defines the observed state of a Gateway.
 type GatewayStatus struct {
 	// This condition is true when the Gateway is expected to be able to serve
@@ -606,6 +748,11 @@ type GatewayStatus struct {
 	Scheduled GatewayScheduledStatus
 	// This condition is true when the Gateway has successfully synced externally.
 	InSync GatewayInSyncStatus
+	// This condition indicates whether the Gateway is running the latest generation
+	// of its GatewayClassConfig. Gateways only follow changes to their
+	// GatewayClassConfig when its updateStrategy is Follow, otherwise they keep
+	// running the configuration they were created with.
+	ConfigUpToDate GatewayConfigUpToDateStatus
 }
 
 // Conditions returns the aggregated status conditions of the GatewayStatus.
@@ -614,6 +761,7 @@ func (s GatewayStatus) Conditions(generation int64) []meta.Condition {
 		s.Ready.Condition(generation),
 		s.Scheduled.Condition(generation),
 		s.InSync.Condition(generation),
+		s.ConfigUpToDate.Condition(generation),
 	}
 }
 
@@ -822,6 +970,11 @@ type RouteResolvedRefsStatus struct {
 	//
 	// [spec]
 	BackendNotFound error
+	// This reason is used when a Route references a port of a backend that the
+	// backend does not expose or that no Consul service is registered for.
+	//
+	// [spec]
+	PortNotFound error
 }
 
 const (
@@ -868,6 +1021,12 @@ const (
 	//
 	// [spec]
 	RouteConditionReasonBackendNotFound = "BackendNotFound"
+	// RouteConditionReasonPortNotFound - This reason is used when a Route
+	// references a port of a backend that the backend does not expose or that no
+	// Consul service is registered for.
+	//
+	// [spec]
+	RouteConditionReasonPortNotFound = "PortNotFound"
 )
 
 // Condition returns the status condition of the RouteResolvedRefsStatus based
@@ -939,6 +1098,17 @@ func (s RouteResolvedRefsStatus) Condition(generation int64) meta.Condition {
 		}
 	}
 
+	if s.PortNotFound != nil {
+		return meta.Condition{
+			Type:               RouteConditionResolvedRefs,
+			Status:             meta.ConditionFalse,
+			Reason:             RouteConditionReasonPortNotFound,
+			Message:            s.PortNotFound.Error(),
+			ObservedGeneration: generation,
+			LastTransitionTime: meta.Now(),
+		}
+	}
+
 	return meta.Condition{
 		Type:               RouteConditionResolvedRefs,
 		Status:             meta.ConditionTrue,
@@ -977,6 +1147,10 @@ func (s RouteResolvedRefsStatus) MarshalJSON() ([]byte, error) {
 		data["BackendNotFound"] = s.BackendNotFound.Error()
 	}
 
+	if s.PortNotFound != nil {
+		data["PortNotFound"] = s.PortNotFound.Error()
+	}
+
 	return json.Marshal(data)
 }
 
@@ -1011,12 +1185,16 @@ func (s *RouteResolvedRefsStatus) UnmarshalJSON(b []byte) error {
 		s.BackendNotFound = errors.New(err)
 	}
 
+	if err, ok := data["PortNotFound"]; ok {
+		s.PortNotFound = errors.New(err)
+	}
+
 	return nil
 }
 
 // HasError returns whether any of the RouteResolvedRefsStatus errors are set.
 func (s RouteResolvedRefsStatus) HasError() bool {
-	return s.Errors != nil || s.ServiceNotFound != nil || s.ConsulServiceNotFound != nil || s.RefNotPermitted != nil || s.InvalidKind != nil || s.BackendNotFound != nil
+	return s.Errors != nil || s.ServiceNotFound != nil || s.ConsulServiceNotFound != nil || s.RefNotPermitted != nil || s.InvalidKind != nil || s.BackendNotFound != nil || s.PortNotFound != nil
 }
 
 // RouteStatus - The status associated with a Route with respect to a given
@@ -1669,6 +1847,277 @@ func (s ListenerResolvedRefsStatus) HasError() bool {
 	return s.InvalidCertificateRef != nil || s.InvalidRouteKinds != nil || s.RefNotPermitted != nil
 }
 
+// ListenerCertificateExpiringStatus - This condition indicates that the
+// certificate referenced by the Listener's TLS configuration expires soon and
+// should be renewed.
+//
+// [custom]
+type ListenerCertificateExpiringStatus struct {
+	// This reason is used with the “CertificateExpiring” condition when the
+	// Listener's certificate is within its expiration warning window.
+	//
+	// [custom]
+	NearExpiry error
+}
+
+const (
+	// ListenerConditionCertificateExpiring - This condition indicates that the
+	// certificate referenced by the Listener's TLS configuration expires soon and
+	// should be renewed.
+	//
+	// [custom]
+	ListenerConditionCertificateExpiring = "CertificateExpiring"
+	// ListenerConditionReasonCertificateValid - This reason is used with the
+	// “CertificateExpiring” condition when the condition is False.
+	//
+	// [custom]
+	ListenerConditionReasonCertificateValid = "CertificateValid"
+	// ListenerConditionReasonNearExpiry - This reason is used with the
+	// “CertificateExpiring” condition when the Listener's certificate is within
+	// its expiration warning window.
+	//
+	// [custom]
+	ListenerConditionReasonNearExpiry = "NearExpiry"
+)
+
+// Condition returns the status condition of the
+// ListenerCertificateExpiringStatus based off of the underlying errors that are
+// set.
+func (s ListenerCertificateExpiringStatus) Condition(generation int64) meta.Condition {
+	if s.NearExpiry != nil {
+		return meta.Condition{
+			Type:               ListenerConditionCertificateExpiring,
+			Status:             meta.ConditionTrue,
+			Reason:             ListenerConditionReasonNearExpiry,
+			Message:            s.NearExpiry.Error(),
+			ObservedGeneration: generation,
+			LastTransitionTime: meta.Now(),
+		}
+	}
+
+	return meta.Condition{
+		Type:               ListenerConditionCertificateExpiring,
+		Status:             meta.ConditionFalse,
+		Reason:             ListenerConditionReasonCertificateValid,
+		Message:            "CertificateValid",
+		ObservedGeneration: generation,
+		LastTransitionTime: meta.Now(),
+	}
+}
+
+// MarshalJSON marshals a ListenerCertificateExpiringStatus value to JSON
+func (s ListenerCertificateExpiringStatus) MarshalJSON() ([]byte, error) {
+	data := map[string]string{}
+
+	if s.NearExpiry != nil {
+		data["NearExpiry"] = s.NearExpiry.Error()
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON unmarshals a ListenerCertificateExpiringStatus from JSON
+func (s *ListenerCertificateExpiringStatus) UnmarshalJSON(b []byte) error {
+	data := map[string]string{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	if err, ok := data["NearExpiry"]; ok {
+		s.NearExpiry = errors.New(err)
+	}
+
+	return nil
+}
+
+// HasError returns whether any of the ListenerCertificateExpiringStatus errors
+// are set.
+func (s ListenerCertificateExpiringStatus) HasError() bool {
+	return s.NearExpiry != nil
+}
+
+// ListenerCertificateReadyStatus - This condition indicates whether the
+// cert-manager Certificate created for the Listener has been issued. Listeners
+// whose certificates aren't issued by cert-manager are always ready.
+//
+// [custom]
+type ListenerCertificateReadyStatus struct {
+	// This reason is used with the “CertificateReady” condition when the
+	// Listener's cert-manager Certificate does not exist yet or its Ready condition
+	// is not True.
+	//
+	// [custom]
+	CertificateNotReady error
+}
+
+const (
+	// ListenerConditionCertificateReady - This condition indicates whether the
+	// cert-manager Certificate created for the Listener has been issued. Listeners
+	// whose certificates aren't issued by cert-manager are always ready.
+	//
+	// [custom]
+	ListenerConditionCertificateReady = "CertificateReady"
+	// ListenerConditionReasonCertificateReady - This reason is used with the
+	// “CertificateReady” condition when the condition is True.
+	//
+	// [custom]
+	ListenerConditionReasonCertificateReady = "CertificateReady"
+	// ListenerConditionReasonCertificateNotReady - This reason is used with the
+	// “CertificateReady” condition when the Listener's cert-manager Certificate
+	// does not exist yet or its Ready condition is not True.
+	//
+	// [custom]
+	ListenerConditionReasonCertificateNotReady = "CertificateNotReady"
+)
+
+// Condition returns the status condition of the ListenerCertificateReadyStatus
+// based off of the underlying errors that are set.
+func (s ListenerCertificateReadyStatus) Condition(generation int64) meta.Condition {
+	if s.CertificateNotReady != nil {
+		return meta.Condition{
+			Type:               ListenerConditionCertificateReady,
+			Status:             meta.ConditionFalse,
+			Reason:             ListenerConditionReasonCertificateNotReady,
+			Message:            s.CertificateNotReady.Error(),
+			ObservedGeneration: generation,
+			LastTransitionTime: meta.Now(),
+		}
+	}
+
+	return meta.Condition{
+		Type:               ListenerConditionCertificateReady,
+		Status:             meta.ConditionTrue,
+		Reason:             ListenerConditionReasonCertificateReady,
+		Message:            "CertificateReady",
+		ObservedGeneration: generation,
+		LastTransitionTime: meta.Now(),
+	}
+}
+
+// MarshalJSON marshals a ListenerCertificateReadyStatus value to JSON
+func (s ListenerCertificateReadyStatus) MarshalJSON() ([]byte, error) {
+	data := map[string]string{}
+
+	if s.CertificateNotReady != nil {
+		data["CertificateNotReady"] = s.CertificateNotReady.Error()
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON unmarshals a ListenerCertificateReadyStatus from JSON
+func (s *ListenerCertificateReadyStatus) UnmarshalJSON(b []byte) error {
+	data := map[string]string{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	if err, ok := data["CertificateNotReady"]; ok {
+		s.CertificateNotReady = errors.New(err)
+	}
+
+	return nil
+}
+
+// HasError returns whether any of the ListenerCertificateReadyStatus errors are
+// set.
+func (s ListenerCertificateReadyStatus) HasError() bool {
+	return s.CertificateNotReady != nil
+}
+
+// ListenerTLSOptionsIgnoredStatus - This condition indicates that TLS options
+// which apply to the Listener are being ignored because they can't be
+// configured. Unlike unsupported options set on the Listener itself, which
+// detach it, the Listener is still attached.
+//
+// [custom]
+type ListenerTLSOptionsIgnoredStatus struct {
+	// This reason is used with the “TLSOptionsIgnored” condition when an
+	// unsupported TLS option is set as a default in the Gateway's annotations, or
+	// cipher suites are set for a Listener whose minimum TLS version is 1.3, where
+	// they can't be configured.
+	//
+	// [custom]
+	IgnoredTLSOption error
+}
+
+const (
+	// ListenerConditionTLSOptionsIgnored - This condition indicates that TLS
+	// options which apply to the Listener are being ignored because they can't be
+	// configured. Unlike unsupported options set on the Listener itself, which
+	// detach it, the Listener is still attached.
+	//
+	// [custom]
+	ListenerConditionTLSOptionsIgnored = "TLSOptionsIgnored"
+	// ListenerConditionReasonTLSOptionsApplied - This reason is used with the
+	// “TLSOptionsIgnored” condition when the condition is False.
+	//
+	// [custom]
+	ListenerConditionReasonTLSOptionsApplied = "TLSOptionsApplied"
+	// ListenerConditionReasonIgnoredTLSOption - This reason is used with the
+	// “TLSOptionsIgnored” condition when an unsupported TLS option is set as a
+	// default in the Gateway's annotations, or cipher suites are set for a Listener
+	// whose minimum TLS version is 1.3, where they can't be configured.
+	//
+	// [custom]
+	ListenerConditionReasonIgnoredTLSOption = "IgnoredTLSOption"
+)
+
+// Condition returns the status condition of the ListenerTLSOptionsIgnoredStatus
+// based off of the underlying errors that are set.
+func (s ListenerTLSOptionsIgnoredStatus) Condition(generation int64) meta.Condition {
+	if s.IgnoredTLSOption != nil {
+		return meta.Condition{
+			Type:               ListenerConditionTLSOptionsIgnored,
+			Status:             meta.ConditionTrue,
+			Reason:             ListenerConditionReasonIgnoredTLSOption,
+			Message:            s.IgnoredTLSOption.Error(),
+			ObservedGeneration: generation,
+			LastTransitionTime: meta.Now(),
+		}
+	}
+
+	return meta.Condition{
+		Type:               ListenerConditionTLSOptionsIgnored,
+		Status:             meta.ConditionFalse,
+		Reason:             ListenerConditionReasonTLSOptionsApplied,
+		Message:            "TLSOptionsApplied",
+		ObservedGeneration: generation,
+		LastTransitionTime: meta.Now(),
+	}
+}
+
+// MarshalJSON marshals a ListenerTLSOptionsIgnoredStatus value to JSON
+func (s ListenerTLSOptionsIgnoredStatus) MarshalJSON() ([]byte, error) {
+	data := map[string]string{}
+
+	if s.IgnoredTLSOption != nil {
+		data["IgnoredTLSOption"] = s.IgnoredTLSOption.Error()
+	}
+
+	return json.Marshal(data)
+}
+
+// UnmarshalJSON unmarshals a ListenerTLSOptionsIgnoredStatus from JSON
+func (s *ListenerTLSOptionsIgnoredStatus) UnmarshalJSON(b []byte) error {
+	data := map[string]string{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return err
+	}
+
+	if err, ok := data["IgnoredTLSOption"]; ok {
+		s.IgnoredTLSOption = errors.New(err)
+	}
+
+	return nil
+}
+
+// HasError returns whether any of the ListenerTLSOptionsIgnoredStatus errors
+// are set.
+func (s ListenerTLSOptionsIgnoredStatus) HasError() bool {
+	return s.IgnoredTLSOption != nil
+}
+
 // ListenerStatus - The status associated with a Listener.
 type ListenerStatus struct {
 	// This condition indicates that the controller was unable to resolve
@@ -1691,6 +2140,17 @@ type ListenerStatus struct {
 	// This condition indicates whether the controller was able to resolve all the
 	// object references for the Listener.
 	ResolvedRefs ListenerResolvedRefsStatus
+	// This condition indicates that the certificate referenced by the Listener's
+	// TLS configuration expires soon and should be renewed.
+	CertificateExpiring ListenerCertificateExpiringStatus
+	// This condition indicates whether the cert-manager Certificate created for the
+	// Listener has been issued. Listeners whose certificates aren't issued by
+	// cert-manager are always ready.
+	CertificateReady ListenerCertificateReadyStatus
+	// This condition indicates that TLS options which apply to the Listener are
+	// being ignored because they can't be configured. Unlike unsupported options
+	// set on the Listener itself, which detach it, the Listener is still attached.
+	TLSOptionsIgnored ListenerTLSOptionsIgnoredStatus
 }
 
 // Conditions returns the aggregated status conditions of the ListenerStatus.
@@ -1700,6 +2160,9 @@ func (s ListenerStatus) Conditions(generation int64) []meta.Condition {
 		s.Detached.Condition(generation),
 		s.Ready.Condition(generation),
 		s.ResolvedRefs.Condition(generation),
+		s.CertificateExpiring.Condition(generation),
+		s.CertificateReady.Condition(generation),
+		s.TLSOptionsIgnored.Condition(generation),
 	}
 }
 
@@ -152,6 +152,35 @@ func TestGatewayInSyncStatus(t *testing.T) {
 
 }
 
+func TestGatewayConfigUpToDateStatus(t *testing.T) {
+	t.Parallel()
+
+	var status GatewayConfigUpToDateStatus
+
+	expected := errors.New("expected")
+
+	status = GatewayConfigUpToDateStatus{}
+	assert.Equal(t, "ConfigUpToDate", status.Condition(0).Message)
+	assert.Equal(t, GatewayConditionReasonConfigUpToDate, status.Condition(0).Reason)
+	assert.False(t, status.HasError())
+
+	status = GatewayConfigUpToDateStatus{InvalidGatewayConfig: expected.Error()}
+	assert.Equal(t, "expected", status.Condition(0).Message)
+	assert.Equal(t, GatewayConditionReasonInvalidGatewayConfig, status.Condition(0).Reason)
+	assert.True(t, status.HasError())
+
+	status = GatewayConfigUpToDateStatus{LatestGeneration: expected.Error()}
+	assert.Equal(t, "expected", status.Condition(0).Message)
+	assert.Equal(t, GatewayConditionReasonLatestGeneration, status.Condition(0).Reason)
+	assert.True(t, status.HasError())
+
+	status = GatewayConfigUpToDateStatus{OutdatedGeneration: expected.Error()}
+	assert.Equal(t, "expected", status.Condition(0).Message)
+	assert.Equal(t, GatewayConditionReasonOutdatedGeneration, status.Condition(0).Reason)
+	assert.True(t, status.HasError())
+
+}
+
 func TestGatewayStatus(t *testing.T) {
 	t.Parallel()
 
@@ -175,6 +204,11 @@ func TestGatewayStatus(t *testing.T) {
 	reason = GatewayConditionReasonInSync
 	assert.Equal(t, conditionType, conditions[2].Type)
 	assert.Equal(t, reason, conditions[2].Reason)
+
+	conditionType = GatewayConditionConfigUpToDate
+	reason = GatewayConditionReasonConfigUpToDate
+	assert.Equal(t, conditionType, conditions[3].Type)
+	assert.Equal(t, reason, conditions[3].Reason)
 }
 
 func TestGatewayReadyStatusMarshaling(t *testing.T) {
@@ -232,6 +266,28 @@ func TestGatewayInSyncStatusMarshaling(t *testing.T) {
 	assert.Equal(t, status.SyncError.Error(), unmarshaled.SyncError.Error())
 }
 
+func TestGatewayConfigUpToDateStatusMarshaling(t *testing.T) {
+	t.Parallel()
+
+	status := GatewayConfigUpToDateStatus{
+		InvalidGatewayConfig: "InvalidGatewayConfig",
+		LatestGeneration:     "LatestGeneration",
+		OutdatedGeneration:   "OutdatedGeneration",
+	}
+
+	data, err := json.Marshal(&status)
+	require.NoError(t, err)
+
+	unmarshaled := GatewayConfigUpToDateStatus{}
+	require.NoError(t, json.Unmarshal(data, &unmarshaled))
+	assert.Equal(t, status.InvalidGatewayConfig, unmarshaled.InvalidGatewayConfig)
+
+	assert.Equal(t, status.LatestGeneration, unmarshaled.LatestGeneration)
+
+	assert.Equal(t, status.OutdatedGeneration, unmarshaled.OutdatedGeneration)
+
+}
+
 func TestRouteAcceptedStatus(t *testing.T) {
 	t.Parallel()
 
@@ -303,6 +359,11 @@ func TestRouteResolvedRefsStatus(t *testing.T) {
 	assert.Equal(t, RouteConditionReasonBackendNotFound, status.Condition(0).Reason)
 	assert.True(t, status.HasError())
 
+	status = RouteResolvedRefsStatus{PortNotFound: expected}
+	assert.Equal(t, "expected", status.Condition(0).Message)
+	assert.Equal(t, RouteConditionReasonPortNotFound, status.Condition(0).Reason)
+	assert.True(t, status.HasError())
+
 }
 
 func TestRouteStatus(t *testing.T) {
@@ -356,6 +417,7 @@ func TestRouteResolvedRefsStatusMarshaling(t *testing.T) {
 		RefNotPermitted:       errors.New("RefNotPermitted"),
 		InvalidKind:           errors.New("InvalidKind"),
 		BackendNotFound:       errors.New("BackendNotFound"),
+		PortNotFound:          errors.New("PortNotFound"),
 	}
 
 	data, err := json.Marshal(&status)
@@ -369,6 +431,7 @@ func TestRouteResolvedRefsStatusMarshaling(t *testing.T) {
 	assert.Equal(t, status.RefNotPermitted.Error(), unmarshaled.RefNotPermitted.Error())
 	assert.Equal(t, status.InvalidKind.Error(), unmarshaled.InvalidKind.Error())
 	assert.Equal(t, status.BackendNotFound.Error(), unmarshaled.BackendNotFound.Error())
+	assert.Equal(t, status.PortNotFound.Error(), unmarshaled.PortNotFound.Error())
 }
 
 func TestListenerConflictedStatus(t *testing.T) {
@@ -487,6 +550,63 @@ func TestListenerResolvedRefsStatus(t *testing.T) {
 
 }
 
+func TestListenerCertificateExpiringStatus(t *testing.T) {
+	t.Parallel()
+
+	var status ListenerCertificateExpiringStatus
+
+	expected := errors.New("expected")
+
+	status = ListenerCertificateExpiringStatus{}
+	assert.Equal(t, "CertificateValid", status.Condition(0).Message)
+	assert.Equal(t, ListenerConditionReasonCertificateValid, status.Condition(0).Reason)
+	assert.False(t, status.HasError())
+
+	status = ListenerCertificateExpiringStatus{NearExpiry: expected}
+	assert.Equal(t, "expected", status.Condition(0).Message)
+	assert.Equal(t, ListenerConditionReasonNearExpiry, status.Condition(0).Reason)
+	assert.True(t, status.HasError())
+
+}
+
+func TestListenerCertificateReadyStatus(t *testing.T) {
+	t.Parallel()
+
+	var status ListenerCertificateReadyStatus
+
+	expected := errors.New("expected")
+
+	status = ListenerCertificateReadyStatus{}
+	assert.Equal(t, "CertificateReady", status.Condition(0).Message)
+	assert.Equal(t, ListenerConditionReasonCertificateReady, status.Condition(0).Reason)
+	assert.False(t, status.HasError())
+
+	status = ListenerCertificateReadyStatus{CertificateNotReady: expected}
+	assert.Equal(t, "expected", status.Condition(0).Message)
+	assert.Equal(t, ListenerConditionReasonCertificateNotReady, status.Condition(0).Reason)
+	assert.True(t, status.HasError())
+
+}
+
+func TestListenerTLSOptionsIgnoredStatus(t *testing.T) {
+	t.Parallel()
+
+	var status ListenerTLSOptionsIgnoredStatus
+
+	expected := errors.New("expected")
+
+	status = ListenerTLSOptionsIgnoredStatus{}
+	assert.Equal(t, "TLSOptionsApplied", status.Condition(0).Message)
+	assert.Equal(t, ListenerConditionReasonTLSOptionsApplied, status.Condition(0).Reason)
+	assert.False(t, status.HasError())
+
+	status = ListenerTLSOptionsIgnoredStatus{IgnoredTLSOption: expected}
+	assert.Equal(t, "expected", status.Condition(0).Message)
+	assert.Equal(t, ListenerConditionReasonIgnoredTLSOption, status.Condition(0).Reason)
+	assert.True(t, status.HasError())
+
+}
+
 func TestListenerStatus(t *testing.T) {
 	t.Parallel()
 
@@ -516,6 +636,21 @@ func TestListenerStatus(t *testing.T) {
 	assert.Equal(t, conditionType, conditions[3].Type)
 	assert.Equal(t, reason, conditions[3].Reason)
 
+	conditionType = ListenerConditionCertificateExpiring
+	reason = ListenerConditionReasonCertificateValid
+	assert.Equal(t, conditionType, conditions[4].Type)
+	assert.Equal(t, reason, conditions[4].Reason)
+
+	conditionType = ListenerConditionCertificateReady
+	reason = ListenerConditionReasonCertificateReady
+	assert.Equal(t, conditionType, conditions[5].Type)
+	assert.Equal(t, reason, conditions[5].Reason)
+
+	conditionType = ListenerConditionTLSOptionsIgnored
+	reason = ListenerConditionReasonTLSOptionsApplied
+	assert.Equal(t, conditionType, conditions[6].Type)
+	assert.Equal(t, reason, conditions[6].Reason)
+
 	require.True(t, status.Valid())
 
 	validationError := errors.New("error")
@@ -637,3 +772,48 @@ func TestListenerResolvedRefsStatusMarshaling(t *testing.T) {
 	assert.Equal(t, status.InvalidRouteKinds.Error(), unmarshaled.InvalidRouteKinds.Error())
 	assert.Equal(t, status.RefNotPermitted.Error(), unmarshaled.RefNotPermitted.Error())
 }
+
+func TestListenerCertificateExpiringStatusMarshaling(t *testing.T) {
+	t.Parallel()
+
+	status := ListenerCertificateExpiringStatus{
+		NearExpiry: errors.New("NearExpiry"),
+	}
+
+	data, err := json.Marshal(&status)
+	require.NoError(t, err)
+
+	unmarshaled := ListenerCertificateExpiringStatus{}
+	require.NoError(t, json.Unmarshal(data, &unmarshaled))
+	assert.Equal(t, status.NearExpiry.Error(), unmarshaled.NearExpiry.Error())
+}
+
+func TestListenerCertificateReadyStatusMarshaling(t *testing.T) {
+	t.Parallel()
+
+	status := ListenerCertificateReadyStatus{
+		CertificateNotReady: errors.New("CertificateNotReady"),
+	}
+
+	data, err := json.Marshal(&status)
+	require.NoError(t, err)
+
+	unmarshaled := ListenerCertificateReadyStatus{}
+	require.NoError(t, json.Unmarshal(data, &unmarshaled))
+	assert.Equal(t, status.CertificateNotReady.Error(), unmarshaled.CertificateNotReady.Error())
+}
+
+func TestListenerTLSOptionsIgnoredStatusMarshaling(t *testing.T) {
+	t.Parallel()
+
+	status := ListenerTLSOptionsIgnoredStatus{
+		IgnoredTLSOption: errors.New("IgnoredTLSOption"),
+	}
+
+	data, err := json.Marshal(&status)
+	require.NoError(t, err)
+
+	unmarshaled := ListenerTLSOptionsIgnoredStatus{}
+	require.NoError(t, json.Unmarshal(data, &unmarshaled))
+	assert.Equal(t, status.IgnoredTLSOption.Error(), unmarshaled.IgnoredTLSOption.Error())
+}
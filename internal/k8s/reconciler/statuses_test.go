@@ -37,16 +37,24 @@ func TestStatuses_GatewayTrackSync(t *testing.T) {
 
 	gateway := newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
 	gateway.Gateway.Status = gateway.GatewayState.GetStatus(gw)
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
 	assert.NoError(t, updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
 		return false, nil
 	}))
 
 	gw = &gwv1beta1.Gateway{}
 	gateway = newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().UpdateStatus(gomock.Any(), gateway.Gateway).Return(nil)
 	assert.NoError(t, updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
 		return false, nil
@@ -54,8 +62,12 @@ func TestStatuses_GatewayTrackSync(t *testing.T) {
 
 	gw = &gwv1beta1.Gateway{}
 	gateway = newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().UpdateStatus(gomock.Any(), gateway.Gateway).Return(nil)
 	assert.NoError(t, updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
 		return false, nil
@@ -65,6 +77,7 @@ func TestStatuses_GatewayTrackSync(t *testing.T) {
 
 	gw = &gwv1beta1.Gateway{}
 	gateway = newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, expected)
 	assert.True(t, errors.Is(updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
@@ -73,8 +86,12 @@ func TestStatuses_GatewayTrackSync(t *testing.T) {
 
 	gw = &gwv1beta1.Gateway{}
 	gateway = newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().UpdateStatus(gomock.Any(), gateway.Gateway).Return(expected)
 	assert.Equal(t, expected, updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
 		return false, nil
@@ -82,8 +99,12 @@ func TestStatuses_GatewayTrackSync(t *testing.T) {
 
 	gw = &gwv1beta1.Gateway{}
 	gateway = newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().UpdateStatus(gomock.Any(), gateway.Gateway).Return(nil)
 	assert.NoError(t, updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
 		return true, nil
@@ -94,8 +115,12 @@ func TestStatuses_GatewayTrackSync(t *testing.T) {
 		apigwv1alpha1.GatewayClassConfig{Spec: apigwv1alpha1.GatewayClassConfigSpec{ConsulSpec: apigwv1alpha1.ConsulSpec{Scheme: "https"}}},
 		gw, state.InitialGatewayState(gw))
 	client.EXPECT().CreateOrUpdateSecret(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().UpdateStatus(gomock.Any(), gateway.Gateway).Return(nil)
 	assert.NoError(t, updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
 		return true, nil
@@ -103,8 +128,12 @@ func TestStatuses_GatewayTrackSync(t *testing.T) {
 
 	gw = &gwv1beta1.Gateway{}
 	gateway = newK8sGateway(apigwv1alpha1.GatewayClassConfig{}, gw, state.InitialGatewayState(gw))
+	client.EXPECT().GetCertManagerCertificatesForGateway(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().GetDeployment(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().CreateOrUpdateDeployment(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
+	client.EXPECT().GetDaemonSet(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetHorizontalPodAutoscaler(gomock.Any(), gomock.Any()).Return(nil, nil)
+	client.EXPECT().GetPodDisruptionBudget(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().UpdateStatus(gomock.Any(), gateway.Gateway).Return(nil)
 	assert.NoError(t, updater.UpdateGatewayStatusOnSync(context.Background(), gateway, func() (bool, error) {
 		return false, expected
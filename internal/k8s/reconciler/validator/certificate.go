@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validator
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	rerrors "github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/errors"
+)
+
+// certificateExpiryWarningWindow is how long before a listener's certificate
+// expires that we start warning about it in the listener's status
+const certificateExpiryWarningWindow = 30 * 24 * time.Hour
+
+// validateCertificate checks that a PEM encoded certificate chain and private key
+// can be served for a listener with the given hostname, returning the parsed leaf
+// certificate. The chain must start with the leaf certificate and each subsequent
+// certificate must have signed the one before it, the private key must match
+// the leaf, and the leaf must be currently valid and cover the listener's hostname.
+func validateCertificate(certificatePEM, privateKeyPEM []byte, hostname *gwv1beta1.Hostname, now time.Time) (*x509.Certificate, error) {
+	chain := []*x509.Certificate{}
+	for rest := certificatePEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("certificate %d in chain could not be parsed: %v", len(chain), err))
+		}
+		chain = append(chain, certificate)
+	}
+	if len(chain) == 0 {
+		return nil, rerrors.NewCertificateResolutionErrorInvalid("no PEM encoded certificates found")
+	}
+
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return nil, rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("certificate %d in chain is not signed by certificate %d, the chain must be ordered from the leaf certificate to the root", i, i+1))
+		}
+	}
+
+	if _, err := tls.X509KeyPair(certificatePEM, privateKeyPEM); err != nil {
+		return nil, rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("private key is invalid or does not match the certificate: %v", err))
+	}
+
+	leaf := chain[0]
+	if now.After(leaf.NotAfter) {
+		return nil, rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("certificate expired at %s", leaf.NotAfter.UTC().Format(time.RFC3339)))
+	}
+	if now.Before(leaf.NotBefore) {
+		return nil, rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("certificate is not valid until %s", leaf.NotBefore.UTC().Format(time.RFC3339)))
+	}
+
+	if hostname != nil && *hostname != "" && *hostname != "*" {
+		// x509 only matches wildcards in the certificate, so check a wildcard
+		// listener hostname by verifying an arbitrary name it would match
+		verify := strings.Replace(string(*hostname), "*", "wildcard", 1)
+		if err := leaf.VerifyHostname(verify); err != nil {
+			return nil, rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("certificate SANs do not cover listener hostname %q", *hostname))
+		}
+	}
+
+	return leaf, nil
+}
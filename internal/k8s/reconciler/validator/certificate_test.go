@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package validator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	gwTesting "github.com/hashicorp/consul-api-gateway/internal/testing"
+)
+
+func tlsSecret(certificate *gwTesting.CertificateInfo) *core.Secret {
+	return &core.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Name: "secret",
+		},
+		Type: core.SecretTypeTLS,
+		Data: map[string][]byte{
+			core.TLSCertKey:       certificate.CertBytes,
+			core.TLSPrivateKeyKey: certificate.PrivateKeyBytes,
+		},
+	}
+}
+
+func TestValidateCertificate(t *testing.T) {
+	t.Parallel()
+
+	ca := gwTesting.DefaultTestCA
+	leaf, err := gwTesting.GenerateSignedCertificate(gwTesting.GenerateCertificateOptions{
+		CA:          ca,
+		ServiceName: "leaf",
+		ExtraSANs:   []string{"example.com", "*.example.com"},
+	})
+	require.NoError(t, err)
+	expired, err := gwTesting.GenerateSignedCertificate(gwTesting.GenerateCertificateOptions{
+		CA:          ca,
+		ServiceName: "expired",
+		Expiration:  time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	chain := append(append([]byte{}, leaf.CertBytes...), ca.CertBytes...)
+	misordered := append(append([]byte{}, ca.CertBytes...), leaf.CertBytes...)
+
+	for _, test := range []struct {
+		name        string
+		certificate []byte
+		privateKey  []byte
+		hostname    string
+		now         time.Time
+		expected    string
+	}{{
+		name:        "valid leaf",
+		certificate: leaf.CertBytes,
+		privateKey:  leaf.PrivateKeyBytes,
+		hostname:    "example.com",
+	}, {
+		name:        "valid chain",
+		certificate: chain,
+		privateKey:  leaf.PrivateKeyBytes,
+		hostname:    "www.example.com",
+	}, {
+		name:        "wildcard hostname",
+		certificate: leaf.CertBytes,
+		privateKey:  leaf.PrivateKeyBytes,
+		hostname:    "*.example.com",
+	}, {
+		name:        "no certificates",
+		certificate: []byte("foo"),
+		privateKey:  leaf.PrivateKeyBytes,
+		expected:    "no PEM encoded certificates found",
+	}, {
+		name:        "mismatched key",
+		certificate: leaf.CertBytes,
+		privateKey:  ca.PrivateKeyBytes,
+		expected:    "private key is invalid or does not match the certificate",
+	}, {
+		name:        "misordered chain",
+		certificate: misordered,
+		privateKey:  leaf.PrivateKeyBytes,
+		expected:    "certificate 0 in chain is not signed by certificate 1",
+	}, {
+		name:        "unrelated intermediate",
+		certificate: append(append([]byte{}, leaf.CertBytes...), expired.CertBytes...),
+		privateKey:  leaf.PrivateKeyBytes,
+		expected:    "certificate 0 in chain is not signed by certificate 1",
+	}, {
+		name:        "expired",
+		certificate: expired.CertBytes,
+		privateKey:  expired.PrivateKeyBytes,
+		expected:    "certificate expired at",
+	}, {
+		name:        "not yet valid",
+		certificate: leaf.CertBytes,
+		privateKey:  leaf.PrivateKeyBytes,
+		now:         time.Now().Add(-time.Hour),
+		expected:    "certificate is not valid until",
+	}, {
+		name:        "hostname not covered",
+		certificate: leaf.CertBytes,
+		privateKey:  leaf.PrivateKeyBytes,
+		hostname:    "example.org",
+		expected:    `certificate SANs do not cover listener hostname "example.org"`,
+	}, {
+		name:        "wildcard hostname not covered",
+		certificate: leaf.CertBytes,
+		privateKey:  leaf.PrivateKeyBytes,
+		hostname:    "*.example.org",
+		expected:    `certificate SANs do not cover listener hostname "*.example.org"`,
+	}} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			now := test.now
+			if now.IsZero() {
+				now = time.Now()
+			}
+			var hostname *gwv1beta1.Hostname
+			if test.hostname != "" {
+				h := gwv1beta1.Hostname(test.hostname)
+				hostname = &h
+			}
+
+			certificate, err := validateCertificate(test.certificate, test.privateKey, hostname, now)
+			if test.expected == "" {
+				require.NoError(t, err)
+				assert.Equal(t, leaf.Cert.NotAfter.Unix(), certificate.NotAfter.Unix())
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
@@ -5,22 +5,28 @@ package validator
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/exp/slices"
 	core "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
-	"github.com/hashicorp/consul-api-gateway/internal/common"
+	"github.com/hashicorp/consul-api-gateway/internal/acme"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	rcommon "github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/common"
 	rerrors "github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/errors"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/state"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	"github.com/hashicorp/consul-api-gateway/internal/metrics"
+	"github.com/hashicorp/consul-api-gateway/internal/vault"
+	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
 // GatewayValidator is responsible for taking a provided v1beta1.Gateway and
@@ -28,18 +34,24 @@ import (
 // makes up the Status on the Kubernetes Gateway resource
 // and stores information about currently bound Routes.
 type GatewayValidator struct {
-	client gatewayclient.Client
+	client      gatewayclient.Client
+	vaultPolicy vault.CertificatePolicy
 }
 
-func NewGatewayValidator(client gatewayclient.Client) *GatewayValidator {
+func NewGatewayValidator(client gatewayclient.Client, vaultPolicy vault.CertificatePolicy) *GatewayValidator {
 	return &GatewayValidator{
-		client: client,
+		client:      client,
+		vaultPolicy: vaultPolicy,
 	}
 }
 
-func (g *GatewayValidator) Validate(ctx context.Context, gateway *gwv1beta1.Gateway, service *core.Service) (*state.GatewayState, error) {
+func (g *GatewayValidator) Validate(ctx context.Context, gateway *gwv1beta1.Gateway, config apigwv1alpha1.GatewayClassConfig, service *core.Service) (*state.GatewayState, error) {
 	state := state.InitialGatewayState(gateway)
 
+	// drop the expiry series of listeners that were removed or changed certificates,
+	// the remaining listeners' series get set again as their certificates are resolved
+	metrics.ListenerCertificateExpiry.DeletePartialMatch(prometheus.Labels{"namespace": gateway.Namespace, "gateway": gateway.Name})
+
 	g.validateListenerConflicts(state, gateway)
 
 	if err := g.validatePods(ctx, state, gateway); err != nil {
@@ -50,18 +62,16 @@ func (g *GatewayValidator) Validate(ctx context.Context, gateway *gwv1beta1.Gate
 		return nil, err
 	}
 
-	if len(gateway.Spec.Addresses) != 0 {
-		state.Status.Ready.AddressNotAssigned = errors.New("gateway does not support requesting addresses")
-	}
+	g.validateAddresses(state, gateway, config, service)
 
-	if err := g.validateListeners(ctx, state, gateway); err != nil {
+	if err := g.validateListeners(ctx, state, gateway, config); err != nil {
 		return nil, err
 	}
 
 	return state, nil
 }
 
-func (g *GatewayValidator) validateListeners(ctx context.Context, state *state.GatewayState, gateway *gwv1beta1.Gateway) error {
+func (g *GatewayValidator) validateListeners(ctx context.Context, state *state.GatewayState, gateway *gwv1beta1.Gateway, config apigwv1alpha1.GatewayClassConfig) error {
 	listenersInvalid, listenersReady := false, true
 
 	for i, listenerState := range state.Listeners {
@@ -69,7 +79,7 @@ func (g *GatewayValidator) validateListeners(ctx context.Context, state *state.G
 		g.validateUnsupported(listenerState, gateway)
 		g.validateProtocols(listenerState, listener)
 
-		if err := g.validateTLS(ctx, listenerState, gateway, listener); err != nil {
+		if err := g.validateTLS(ctx, listenerState, gateway, config, listener); err != nil {
 			return err
 		}
 
@@ -223,7 +233,13 @@ func (g *GatewayValidator) validateGatewayIP(ctx context.Context, state *state.G
 		 * cluster (in the case of Kind) or open firewall rules (in the case of GKE) in order to
 		 * access the gateway from outside the cluster.
 		 */
-		return g.assignGatewayIPFromPodHost(ctx, state, gateway)
+		if err := g.assignGatewayIPFromPodHost(ctx, state, gateway); err != nil {
+			return err
+		}
+		if state.ServiceReady {
+			state.Addresses = append(state.Addresses, service.Spec.ExternalIPs...)
+		}
+		return nil
 	default:
 		return fmt.Errorf("unsupported service type: %s", service.Spec.Type)
 	}
@@ -272,6 +288,7 @@ func (g *GatewayValidator) assignGatewayIPFromService(ctx context.Context, state
 	if updated.Spec.ClusterIP != "" {
 		state.ServiceReady = true
 		state.Addresses = append(state.Addresses, updated.Spec.ClusterIP)
+		state.Addresses = append(state.Addresses, updated.Spec.ExternalIPs...)
 	}
 
 	return nil
@@ -291,10 +308,16 @@ func (g *GatewayValidator) assignGatewayIPFromPods(ctx context.Context, state *s
 	}
 
 	for _, pod := range pods {
-		if pod.Status.PodIP != "" {
+		// pods on the host network, such as those of DaemonSet
+		// mode gateways, are reached at their node's address
+		address := pod.Status.PodIP
+		if pod.Spec.HostNetwork {
+			address = pod.Status.HostIP
+		}
+		if address != "" {
 			state.ServiceReady = true
-			if !slices.Contains(state.Addresses, pod.Status.PodIP) {
-				state.Addresses = append(state.Addresses, pod.Status.PodIP)
+			if !slices.Contains(state.Addresses, address) {
+				state.Addresses = append(state.Addresses, address)
 			}
 		}
 	}
@@ -331,12 +354,68 @@ func (g *GatewayValidator) assignGatewayIPFromPodHost(ctx context.Context, state
 	return nil
 }
 
+// validateAddresses checks that the addresses requested in the Gateway's spec.addresses
+// have been assigned to it. Requested hostnames are handed to external-dns, so they're
+// considered assigned once the load balancer they point at is ready.
+func (g *GatewayValidator) validateAddresses(state *state.GatewayState, gateway *gwv1beta1.Gateway, config apigwv1alpha1.GatewayClassConfig, service *core.Service) {
+	if len(gateway.Spec.Addresses) == 0 {
+		return
+	}
+	if hasNamedAddress(gateway) {
+		state.Status.Ready.AddressNotAssigned = errors.New("gateway does not support requesting named addresses")
+		return
+	}
+	if service == nil {
+		state.Status.Ready.AddressNotAssigned = errors.New("requesting addresses requires a gateway service")
+		return
+	}
+
+	// only addresses allowed by the GatewayClassConfig are requested on the service
+	var allowedRanges []string
+	var allowHostnames bool
+	if addresses := config.Spec.AddressesSpec; addresses != nil {
+		allowedRanges, allowHostnames = addresses.AllowedIPRanges, addresses.AllowHostnames
+	}
+	ips, hostnames, err := utils.AllowedAddresses(gateway, allowedRanges, allowHostnames)
+	if err != nil {
+		state.Status.Ready.AddressNotAssigned = err
+		return
+	}
+	if len(hostnames) > 0 && service.Spec.Type != core.ServiceTypeLoadBalancer {
+		state.Status.Ready.AddressNotAssigned = errors.New("requesting hostnames requires a LoadBalancer service")
+		return
+	}
+	if state.ServiceReady {
+		for _, hostname := range hostnames {
+			if !slices.Contains(state.Addresses, hostname) {
+				state.Addresses = append(state.Addresses, hostname)
+			}
+		}
+	}
+
+	for _, address := range append(ips, hostnames...) {
+		if !slices.Contains(state.Addresses, address) {
+			state.Status.Ready.AddressNotAssigned = fmt.Errorf("requested address %s has not been assigned", address)
+			return
+		}
+	}
+}
+
+func hasNamedAddress(gateway *gwv1beta1.Gateway) bool {
+	for _, address := range gateway.Spec.Addresses {
+		if address.Type != nil && *address.Type == gwv1beta1.NamedAddressType {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *GatewayValidator) validateUnsupported(state *state.ListenerState, gateway *gwv1beta1.Gateway) {
 	// seems weird that we're looking at gateway fields for listener status
 	// but that's the weirdness of the spec
-	if len(gateway.Spec.Addresses) > 0 {
-		// we dnn't support address binding
-		state.Status.Detached.UnsupportedAddress = errors.New("specified addresses are not supported")
+	if hasNamedAddress(gateway) {
+		// we don't support binding to named addresses
+		state.Status.Detached.UnsupportedAddress = errors.New("named addresses are not supported")
 	}
 }
 
@@ -353,7 +432,7 @@ func (g *GatewayValidator) validateProtocols(state *state.ListenerState, listene
 	}
 }
 
-func (g *GatewayValidator) validateTLS(ctx context.Context, state *state.ListenerState, gateway *gwv1beta1.Gateway, listener gwv1beta1.Listener) error {
+func (g *GatewayValidator) validateTLS(ctx context.Context, state *state.ListenerState, gateway *gwv1beta1.Gateway, config apigwv1alpha1.GatewayClassConfig, listener gwv1beta1.Listener) error {
 	if listener.TLS == nil {
 		_, tlsRequired := utils.ProtocolToConsul(listener.Protocol)
 		if tlsRequired {
@@ -382,78 +461,135 @@ func (g *GatewayValidator) validateTLS(ctx context.Context, state *state.Listene
 		return err
 	} else if !allowed {
 		nsName := getNamespacedName(ref.Name, ref.Namespace, gateway.Namespace)
+		kind := "Secret"
+		if ref.Kind != nil {
+			kind = string(*ref.Kind)
+		}
 		state.Status.ResolvedRefs.RefNotPermitted = rerrors.NewCertificateResolutionErrorNotPermitted(
-			fmt.Sprintf("Cross-namespace listener certificate not allowed without matching ReferenceGrant for Secret %q", nsName))
+			fmt.Sprintf("Cross-namespace listener certificate not allowed without matching ReferenceGrant for %s %q", kind, nsName))
 		return nil
 	}
 
-	resource, err := resolveCertificateReference(ctx, g.client, gateway, ref)
+	if issuer, ok := utils.CertManagerIssuerFor(listener); ok {
+		skip, err := g.validateCertManagerCertificate(ctx, state, gateway, config, listener, issuer, ref)
+		if err != nil || skip {
+			return err
+		}
+	}
+
+	resource, certificate, err := g.resolveCertificateReference(ctx, gateway, listener, ref)
 	if err != nil {
 		var certificateErr rerrors.CertificateResolutionError
 		if !errors.As(err, &certificateErr) {
 			return err
 		}
+		if certificateErr.Kind() == rerrors.CertificateResolutionErrorTypeNotPermitted {
+			state.Status.ResolvedRefs.RefNotPermitted = certificateErr
+			return nil
+		}
 		state.Status.ResolvedRefs.InvalidCertificateRef = certificateErr
 		return nil
 	}
 
 	state.TLS.Certificates = []string{resource}
 
-	if listener.TLS.Options != nil {
-		tlsMinVersion := listener.TLS.Options[tlsMinVersionAnnotationKey]
-		tlsMaxVersion := listener.TLS.Options[tlsMaxVersionAnnotationKey]
-		tlsCipherSuitesStr := listener.TLS.Options[tlsCipherSuitesAnnotationKey]
-
-		if tlsMinVersion != "" {
-			if _, ok := common.SupportedTLSVersions[string(tlsMinVersion)]; !ok {
-				state.Status.Ready.Invalid = errors.New("unrecognized TLS min version")
-				return nil
-			}
-
-			if tlsCipherSuitesStr != "" {
-				if _, ok := common.TLSVersionsWithConfigurableCipherSuites[string(tlsMinVersion)]; !ok {
-					state.Status.Ready.Invalid = errors.New("configuring TLS cipher suites is only supported for TLS 1.2 and earlier")
-					return nil
-				}
-			}
-
-			state.TLS.MinVersion = string(tlsMinVersion)
+	if certificate != nil {
+		// we only have the certificate itself for references the controller can read
+		metrics.ListenerCertificateExpiry.WithLabelValues(gateway.Namespace, gateway.Name, string(listener.Name), resource).Set(float64(certificate.NotAfter.Unix()))
+
+		// revalidate once the certificate enters the warning window, or once it
+		// expires if it's already in it, so the listener's status doesn't go stale
+		warnAt := certificate.NotAfter.Add(-certificateExpiryWarningWindow)
+		if time.Now().Before(warnAt) {
+			state.CertificateRecheck = warnAt
+		} else {
+			state.Status.CertificateExpiring.NearExpiry = fmt.Errorf("certificate expires at %s", certificate.NotAfter.UTC().Format(time.RFC3339))
+			state.CertificateRecheck = certificate.NotAfter
 		}
+	}
 
-		if tlsMaxVersion != "" {
-			if _, ok := common.SupportedTLSVersions[string(tlsMaxVersion)]; !ok {
-				state.Status.Ready.Invalid = errors.New("unrecognized TLS max version")
-				return nil
-			}
+	ignored, err := validateTLSOptions(gateway, listener, &state.TLS)
+	if err != nil {
+		state.Status.Ready.Invalid = err
+	}
 
-			state.TLS.MaxVersion = string(tlsMaxVersion)
-		}
+	unsupported, err := validateUnsupportedTLSOptions(gateway, listener)
+	if err != nil {
+		state.Status.Detached.UnsupportedExtension = err
+	}
 
-		if tlsCipherSuitesStr != "" {
-			// split comma delimited string into string array and trim whitespace
-			tlsCipherSuitesUntrimmed := strings.Split(string(tlsCipherSuitesStr), ",")
-			tlsCipherSuites := tlsCipherSuitesUntrimmed[:0]
-			for _, c := range tlsCipherSuitesUntrimmed {
-				tlsCipherSuites = append(tlsCipherSuites, strings.TrimSpace(c))
-			}
+	if ignored = append(ignored, unsupported...); len(ignored) > 0 {
+		state.Status.TLSOptionsIgnored.IgnoredTLSOption = errors.New(strings.Join(ignored, ", "))
+	}
 
-			// validate each cipher suite in array
-			for _, c := range tlsCipherSuites {
-				if ok := common.SupportedTLSCipherSuite(c); !ok {
-					state.Status.Ready.Invalid = fmt.Errorf("unrecognized or unsupported TLS cipher suite: %s", c)
-					return nil
-				}
-			}
+	return nil
+}
 
-			// set cipher suites on listener TLS params
-			state.TLS.CipherSuites = tlsCipherSuites
-		}
+// validateCertManagerCertificate checks the cert-manager Certificate that the deployer
+// creates for a listener with a cert-manager issuer, reporting whether it's ready in the
+// listener's status. The listener is kept pending until the Certificate's secret exists.
+// The issuer must be allowed by the GatewayClassConfig and the Certificate and its secret
+// must not already exist for anything other than the listener.
+// It returns true if the listener's certificate reference shouldn't be resolved yet.
+func (g *GatewayValidator) validateCertManagerCertificate(ctx context.Context, state *state.ListenerState, gateway *gwv1beta1.Gateway, config apigwv1alpha1.GatewayClassConfig, listener gwv1beta1.Listener, issuer utils.CertManagerIssuer, ref gwv1beta1.SecretObjectReference) (bool, error) {
+	if listener.TLS.Options[tlsCertificateIssuerAnnotationKey] != "" {
+		state.Status.Ready.Invalid = errors.New("cert-manager issuers cannot be combined with a certificate issuer")
+		return true, nil
+	}
+	if listener.Hostname == nil || *listener.Hostname == "" {
+		state.Status.Ready.Invalid = errors.New("cert-manager certificates require a listener hostname")
+		return true, nil
+	}
+	if !utils.IsSecretInNamespace(ref, gateway.Namespace) {
+		state.Status.ResolvedRefs.InvalidCertificateRef = rerrors.NewCertificateResolutionErrorInvalid("cert-manager certificates must reference a Secret in the Gateway's namespace")
+		return true, nil
+	}
+	if !config.Spec.CertManagerSpec.AllowsIssuer(issuer.Name, issuer.Kind, issuer.Group) {
+		state.Status.ResolvedRefs.RefNotPermitted = rerrors.NewCertificateResolutionErrorNotPermitted(fmt.Sprintf("cert-manager %s %q is not allowed by the GatewayClassConfig", issuer.Kind, issuer.Name))
+		return true, nil
+	}
+
+	name := utils.CertManagerCertificateName(gateway, listener)
+	certificate, err := g.client.GetCertManagerCertificate(ctx, types.NamespacedName{
+		Namespace: gateway.Namespace,
+		Name:      name,
+	})
+	if errors.Is(err, gatewayclient.ErrCertManagerNotInstalled) {
+		state.Status.Ready.Invalid = err
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if certificate != nil && !metav1.IsControlledBy(certificate, gateway) {
+		state.Status.ResolvedRefs.InvalidCertificateRef = rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("cert-manager Certificate %q already exists and is not owned by the Gateway", name))
+		return true, nil
+	}
+	if certificate == nil {
+		state.Status.CertificateReady.CertificateNotReady = errors.New("waiting for the cert-manager Certificate to be created")
+	} else if ready, reason := utils.CertManagerCertificateReady(certificate); !ready {
+		state.Status.CertificateReady.CertificateNotReady = errors.New(reason)
 	}
 
-	return nil
+	secret, err := g.client.GetSecret(ctx, types.NamespacedName{Namespace: gateway.Namespace, Name: string(ref.Name)})
+	if err != nil {
+		return false, err
+	}
+	if secret == nil {
+		state.Status.Ready.Pending = fmt.Errorf("waiting for cert-manager to issue a certificate into secret %q", ref.Name)
+		return true, nil
+	}
+	if !utils.IsCertManagerSecretFor(secret, name) {
+		state.Status.ResolvedRefs.InvalidCertificateRef = rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("secret %q already exists and was not issued for the Gateway's cert-manager Certificate", ref.Name))
+		return true, nil
+	}
+	return false, nil
 }
 
-func resolveCertificateReference(ctx context.Context, client gatewayclient.Client, gateway *gwv1beta1.Gateway, ref gwv1beta1.SecretObjectReference) (string, error) {
+// resolveCertificateReference returns the SDS resource name for a listener's certificate
+// reference along with the certificate itself if its contents are available to the controller.
+func (g *GatewayValidator) resolveCertificateReference(ctx context.Context, gateway *gwv1beta1.Gateway, listener gwv1beta1.Listener, ref gwv1beta1.SecretObjectReference) (string, *x509.Certificate, error) {
+	client := g.client
 	group := core.GroupName
 	kind := "Secret"
 	namespace := gateway.Namespace
@@ -470,17 +606,99 @@ func resolveCertificateReference(ctx context.Context, client gatewayclient.Clien
 
 	switch {
 	case kind == "Secret" && group == core.GroupName:
+		if listener.TLS.Options[tlsCertificateIssuerAnnotationKey] == acmeCertificateIssuer {
+			resource, err := acmeCertificateReference(ctx, client, namespace, string(ref.Name), listener)
+			return resource, nil, err
+		}
 		cert, err := client.GetSecret(ctx, types.NamespacedName{Name: string(ref.Name), Namespace: namespace})
 		if err != nil {
-			return "", fmt.Errorf("error fetching secret: %w", err)
+			return "", nil, fmt.Errorf("error fetching secret: %w", err)
 		}
 		if cert == nil {
-			return "", rerrors.NewCertificateResolutionErrorNotFound("certificate not found")
+			return "", nil, rerrors.NewCertificateResolutionErrorNotFound("certificate not found")
+		}
+		if cert.Type != core.SecretTypeTLS {
+			return "", nil, rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("secret must be of type %s", core.SecretTypeTLS))
 		}
-		return utils.NewK8sSecret(namespace, string(ref.Name)).String(), nil
+		certificate, err := validateCertificate(cert.Data[core.TLSCertKey], cert.Data[core.TLSPrivateKeyKey], listener.Hostname, time.Now())
+		if err != nil {
+			return "", nil, err
+		}
+		return utils.NewK8sSecret(namespace, string(ref.Name)).String(), certificate, nil
+	case kind == apigwv1alpha1.VaultCertificateKind && group == apigwv1alpha1.Group:
+		cert, err := client.GetVaultCertificate(ctx, types.NamespacedName{Name: string(ref.Name), Namespace: namespace})
+		if err != nil {
+			return "", nil, fmt.Errorf("error fetching vault certificate: %w", err)
+		}
+		if cert == nil {
+			return "", nil, rerrors.NewCertificateResolutionErrorNotFound("certificate not found")
+		}
+		resource, err := vaultCertificateReference(cert, g.vaultPolicy)
+		return resource, nil, err
 	// add more supported types here
 	default:
-		return "", rerrors.NewCertificateResolutionErrorUnsupported(fmt.Sprintf("unsupported certificate type - group: %s, kind: %s", group, kind))
+		return "", nil, rerrors.NewCertificateResolutionErrorUnsupported(fmt.Sprintf("unsupported certificate type - group: %s, kind: %s", group, kind))
+	}
+}
+
+// acmeCertificateReference returns the SDS resource name for a certificate issued
+// through ACME for the listener's hostname and stored in the referenced secret. The
+// secret must either not exist yet or have been created to store an issued certificate.
+func acmeCertificateReference(ctx context.Context, client gatewayclient.Client, namespace, name string, listener gwv1beta1.Listener) (string, error) {
+	if listener.Hostname == nil || *listener.Hostname == "" {
+		return "", rerrors.NewCertificateResolutionErrorInvalid("ACME certificates require a listener hostname")
+	}
+	if strings.Contains(string(*listener.Hostname), "*") {
+		return "", rerrors.NewCertificateResolutionErrorInvalid("ACME certificates cannot be issued for wildcard hostnames")
+	}
+
+	secret, err := client.GetSecret(ctx, types.NamespacedName{Namespace: namespace, Name: name})
+	if err != nil {
+		return "", fmt.Errorf("error fetching secret: %w", err)
+	}
+	if secret != nil && !utils.IsACMEManagedSecret(secret, core.SecretTypeTLS) {
+		return "", rerrors.NewCertificateResolutionErrorInvalid(fmt.Sprintf("secret %s/%s already exists and is not a %s secret managed by ACME", namespace, name, core.SecretTypeTLS))
+	}
+	return acme.NewSecret(namespace, name, string(*listener.Hostname)).String(), nil
+}
+
+// vaultCertificateReference converts a VaultCertificate into the secret URL
+// that the matching Vault SDS secret client understands, KV paths and PKI roles
+// must be allowed for the VaultCertificate's namespace by the controller's policy
+func vaultCertificateReference(cert *apigwv1alpha1.VaultCertificate, policy vault.CertificatePolicy) (string, error) {
+	kv, pki := cert.Spec.KV, cert.Spec.PKI
+	switch {
+	case kv != nil && pki != nil:
+		return "", rerrors.NewCertificateResolutionErrorInvalid("vault certificate must specify only one of kv or pki")
+	case kv != nil:
+		if kv.Path == "" || kv.CertField == "" || kv.PrivateKeyField == "" {
+			return "", rerrors.NewCertificateResolutionErrorInvalid("vault kv certificate must specify a path, certificate field and private key field")
+		}
+		secret := vault.NewKVSecret("/"+strings.TrimPrefix(kv.Path, "/"), kv.CertField, kv.PrivateKeyField)
+		secret.Mount = kv.Mount
+		if !policy.AllowsKV(cert.Namespace, secret) {
+			return "", rerrors.NewCertificateResolutionErrorNotPermitted(fmt.Sprintf("vault kv path %q is not allowed for namespace %s", kv.Path, cert.Namespace))
+		}
+		return secret.String(), nil
+	case pki != nil:
+		if pki.CommonName == "" {
+			return "", rerrors.NewCertificateResolutionErrorInvalid("vault pki certificate must specify a common name")
+		}
+		secret := vault.NewPKISecret(
+			pki.CommonName,
+			strings.Join(pki.AltNames, ","),
+			strings.Join(pki.IPSANs, ","),
+			strings.Join(pki.OtherSANs, ","),
+			pki.TTL,
+		)
+		secret.Role = pki.Role
+		secret.Mount = pki.Mount
+		if !policy.AllowsPKI(cert.Namespace, secret) {
+			return "", rerrors.NewCertificateResolutionErrorNotPermitted(fmt.Sprintf("vault pki mount %q and role %q are not allowed for namespace %s", pki.Mount, pki.Role, cert.Namespace))
+		}
+		return secret.String(), nil
+	default:
+		return "", rerrors.NewCertificateResolutionErrorInvalid("vault certificate must specify one of kv or pki")
 	}
 }
 
@@ -8,19 +8,26 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/builder"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/state"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/status"
+	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
+	gwTesting "github.com/hashicorp/consul-api-gateway/internal/testing"
+	"github.com/hashicorp/consul-api-gateway/internal/vault"
 	apigwv1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 )
 
@@ -44,22 +51,22 @@ func TestGatewayValidate(t *testing.T) {
 		},
 	}
 
-	validator := NewGatewayValidator(client)
+	validator := NewGatewayValidator(client, vault.CertificatePolicy{})
 	client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(nil, nil)
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
-	state, err := validator.Validate(context.Background(), gateway, nil)
+	state, err := validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	assert.NotNil(t, state)
 
 	expected := errors.New("expected")
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
 	client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(nil, expected)
-	state, err = validator.Validate(context.Background(), gateway, nil)
+	state, err = validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.True(t, errors.Is(err, expected))
 	assert.Nil(t, state)
 
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, expected).Times(1)
-	state, err = validator.Validate(context.Background(), gateway, nil)
+	state, err = validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.True(t, errors.Is(err, expected))
 	assert.Nil(t, state)
 }
@@ -156,7 +163,7 @@ func TestGatewayValidateGatewayIP(t *testing.T) {
 			} else {
 				client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return([]core.Pod{pod}, nil)
 			}
-			validator := NewGatewayValidator(client)
+			validator := NewGatewayValidator(client, vault.CertificatePolicy{})
 			state := &state.GatewayState{}
 			service := serviceFor(config, gateway)
 			assert.NoError(t, validator.validateGatewayIP(context.Background(), state, gateway, service))
@@ -169,6 +176,150 @@ func TestGatewayValidateGatewayIP(t *testing.T) {
 	}
 }
 
+func TestGatewayValidateGatewayIP_HostNetwork(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockClient(ctrl)
+
+	pods := []core.Pod{{
+		Spec:   core.PodSpec{HostNetwork: true},
+		Status: core.PodStatus{HostIP: "1.1.1.1", PodIP: "1.1.1.1"},
+	}, {
+		Spec:   core.PodSpec{HostNetwork: true},
+		Status: core.PodStatus{HostIP: "2.2.2.2"},
+	}}
+	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(pods, nil)
+
+	state := &state.GatewayState{}
+	require.NoError(t, NewGatewayValidator(client, vault.CertificatePolicy{}).validateGatewayIP(context.Background(), state, &gwv1beta1.Gateway{}, nil))
+	assert.Equal(t, []string{"1.1.1.1", "2.2.2.2"}, state.Addresses)
+	assert.True(t, state.ServiceReady)
+}
+
+func TestGatewayValidateAddresses(t *testing.T) {
+	t.Parallel()
+
+	ipAddress := gwv1beta1.IPAddressType
+	hostname := gwv1beta1.HostnameAddressType
+	named := gwv1beta1.NamedAddressType
+
+	for _, tc := range []struct {
+		name        string
+		addresses   []gwv1beta1.GatewayAddress
+		serviceType *core.ServiceType
+		disallowed  bool
+		assigned    []string
+		ready       bool
+		expected    []string
+		err         string
+	}{{
+		name:        "no addresses",
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1"},
+	}, {
+		name:        "assigned IP",
+		addresses:   []gwv1beta1.GatewayAddress{{Value: "10.0.0.1"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1"},
+	}, {
+		name:        "unassigned IP",
+		addresses:   []gwv1beta1.GatewayAddress{{Type: &ipAddress, Value: "10.0.0.2"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1"},
+		err:         "requested address 10.0.0.2 has not been assigned",
+	}, {
+		name:        "hostname",
+		addresses:   []gwv1beta1.GatewayAddress{{Type: &hostname, Value: "gateway.example.com"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1", "gateway.example.com"},
+	}, {
+		name:        "hostname pending load balancer",
+		addresses:   []gwv1beta1.GatewayAddress{{Type: &hostname, Value: "gateway.example.com"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		err:         "requested address gateway.example.com has not been assigned",
+	}, {
+		name:        "hostname without load balancer",
+		addresses:   []gwv1beta1.GatewayAddress{{Type: &hostname, Value: "gateway.example.com"}},
+		serviceType: serviceType(core.ServiceTypeClusterIP),
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1"},
+		err:         "requesting hostnames requires a LoadBalancer service",
+	}, {
+		name:      "no service",
+		addresses: []gwv1beta1.GatewayAddress{{Value: "10.0.0.1"}},
+		assigned:  []string{"10.0.0.1"},
+		ready:     true,
+		expected:  []string{"10.0.0.1"},
+		err:       "requesting addresses requires a gateway service",
+	}, {
+		name:        "named address",
+		addresses:   []gwv1beta1.GatewayAddress{{Type: &named, Value: "reserved"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		err:         "gateway does not support requesting named addresses",
+	}, {
+		name:        "IP outside allowed ranges",
+		addresses:   []gwv1beta1.GatewayAddress{{Value: "192.168.0.1"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1"},
+		err:         "requested address 192.168.0.1 is not allowed by the gateway class",
+	}, {
+		name:        "invalid IP",
+		addresses:   []gwv1beta1.GatewayAddress{{Value: "10.0.0.300"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1"},
+		err:         `requested address "10.0.0.300" is not a valid IP address`,
+	}, {
+		name:        "addresses not allowed",
+		addresses:   []gwv1beta1.GatewayAddress{{Value: "10.0.0.1"}, {Type: &hostname, Value: "gateway.example.com"}},
+		serviceType: serviceType(core.ServiceTypeLoadBalancer),
+		disallowed:  true,
+		assigned:    []string{"10.0.0.1"},
+		ready:       true,
+		expected:    []string{"10.0.0.1"},
+		err:         "requested address 10.0.0.1 is not allowed by the gateway class",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &gwv1beta1.Gateway{
+				Spec: gwv1beta1.GatewaySpec{Addresses: tc.addresses},
+			}
+			config := apigwv1alpha1.GatewayClassConfig{
+				Spec: apigwv1alpha1.GatewayClassConfigSpec{ServiceType: tc.serviceType},
+			}
+			if !tc.disallowed {
+				config.Spec.AddressesSpec = &apigwv1alpha1.AddressesSpec{
+					AllowedIPRanges: []string{"10.0.0.0/24"},
+					AllowHostnames:  true,
+				}
+			}
+			state := &state.GatewayState{Addresses: tc.assigned, ServiceReady: tc.ready}
+
+			NewGatewayValidator(nil, vault.CertificatePolicy{}).validateAddresses(state, gateway, config, serviceFor(config, gateway))
+
+			assert.Equal(t, tc.expected, state.Addresses)
+			if tc.err == "" {
+				require.NoError(t, state.Status.Ready.AddressNotAssigned)
+				return
+			}
+			require.EqualError(t, state.Status.Ready.AddressNotAssigned, tc.err)
+		})
+	}
+}
+
 func TestGatewayValidate_ListenerProtocolConflicts(t *testing.T) {
 	t.Parallel()
 
@@ -191,9 +342,9 @@ func TestGatewayValidate_ListenerProtocolConflicts(t *testing.T) {
 	}
 
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
-	validator := NewGatewayValidator(client)
+	validator := NewGatewayValidator(client, vault.CertificatePolicy{})
 
-	state, err := validator.Validate(context.Background(), gateway, nil)
+	state, err := validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	require.Equal(t, status.ListenerConditionReasonProtocolConflict, state.Listeners[0].Status.Conflicted.Condition(0).Reason)
 	require.Equal(t, status.ListenerConditionReasonProtocolConflict, state.Listeners[1].Status.Conflicted.Condition(0).Reason)
@@ -225,9 +376,9 @@ func TestGatewayValidate_ListenerHostnameConflicts(t *testing.T) {
 	}
 
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
-	validator := NewGatewayValidator(client)
+	validator := NewGatewayValidator(client, vault.CertificatePolicy{})
 
-	state, err := validator.Validate(context.Background(), gateway, nil)
+	state, err := validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	require.Equal(t, status.ListenerConditionReasonHostnameConflict, state.Listeners[0].Status.Conflicted.Condition(0).Reason)
 	require.Equal(t, status.ListenerConditionReasonHostnameConflict, state.Listeners[1].Status.Conflicted.Condition(0).Reason)
@@ -250,8 +401,8 @@ func TestGatewayValidate_Pods(t *testing.T) {
 	client.EXPECT().PodsWithLabels(gomock.Any(), gomock.Any()).Return([]core.Pod{{
 		Status: core.PodStatus{},
 	}}, nil).Times(2)
-	validator := NewGatewayValidator(client)
-	gwState, err := validator.Validate(context.Background(), gateway, nil)
+	validator := NewGatewayValidator(client, vault.CertificatePolicy{})
+	gwState, err := validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	require.Equal(t, status.GatewayConditionReasonUnknown, gwState.Status.Scheduled.Condition(0).Reason)
 
@@ -261,7 +412,7 @@ func TestGatewayValidate_Pods(t *testing.T) {
 			Phase: core.PodPending,
 		},
 	}}, nil).Times(2)
-	gwState, err = validator.Validate(context.Background(), gateway, nil)
+	gwState, err = validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	require.Equal(t, status.GatewayConditionReasonNotReconciled, gwState.Status.Scheduled.Condition(0).Reason)
 
@@ -276,7 +427,7 @@ func TestGatewayValidate_Pods(t *testing.T) {
 			}},
 		},
 	}}, nil).Times(2)
-	gwState, err = validator.Validate(context.Background(), gateway, nil)
+	gwState, err = validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, status.GatewayConditionReasonNoResources, gwState.Status.Scheduled.Condition(0).Reason)
 
@@ -290,7 +441,7 @@ func TestGatewayValidate_Pods(t *testing.T) {
 			}},
 		},
 	}}, nil).Times(2)
-	gwState, err = validator.Validate(context.Background(), gateway, nil)
+	gwState, err = validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	assert.True(t, gwState.PodReady)
 
@@ -300,7 +451,7 @@ func TestGatewayValidate_Pods(t *testing.T) {
 			Phase: core.PodSucceeded,
 		},
 	}}, nil).Times(2)
-	gwState, err = validator.Validate(context.Background(), gateway, nil)
+	gwState, err = validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, status.GatewayConditionReasonPodFailed, gwState.Status.Scheduled.Condition(0).Reason)
 
@@ -310,7 +461,7 @@ func TestGatewayValidate_Pods(t *testing.T) {
 			Phase: core.PodFailed,
 		},
 	}}, nil).Times(2)
-	gwState, err = validator.Validate(context.Background(), gateway, nil)
+	gwState, err = validator.Validate(context.Background(), gateway, apigwv1alpha1.GatewayClassConfig{}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, status.GatewayConditionReasonPodFailed, gwState.Status.Scheduled.Condition(0).Reason)
 }
@@ -323,7 +474,12 @@ func TestListenerValidate(t *testing.T) {
 	defer ctrl.Finish()
 	client := mocks.NewMockClient(ctrl)
 
-	validator := NewGatewayValidator(client)
+	validator := NewGatewayValidator(client, vault.CertificatePolicy{
+		KVMount:  "secret",
+		KVPaths:  vault.Allowlist{"default": {"secret/gateways"}},
+		PKIMount: "pki",
+		PKIRoles: vault.Allowlist{"default": {"pki-int/web"}},
+	})
 
 	t.Run("Unsupported protocol", func(t *testing.T) {
 		listener := gwv1beta1.Listener{}
@@ -349,9 +505,10 @@ func TestListenerValidate(t *testing.T) {
 	})
 
 	t.Run("Unsupported address", func(t *testing.T) {
+		named := gwv1beta1.NamedAddressType
 		gateway := &gwv1beta1.Gateway{
 			Spec: gwv1beta1.GatewaySpec{
-				Addresses: []gwv1beta1.GatewayAddress{{}},
+				Addresses: []gwv1beta1.GatewayAddress{{Type: &named, Value: "reserved"}},
 			},
 		}
 		listenerState := &state.ListenerState{}
@@ -365,7 +522,7 @@ func TestListenerValidate(t *testing.T) {
 			Protocol: gwv1beta1.HTTPSProtocolType,
 		}
 		listenerState := &state.ListenerState{}
-		validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		condition := listenerState.Status.Ready.Condition(0)
 		require.Equal(t, status.ListenerConditionReasonInvalid, condition.Reason)
 	})
@@ -379,7 +536,7 @@ func TestListenerValidate(t *testing.T) {
 			},
 		}
 		listenerState := &state.ListenerState{}
-		validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		condition := listenerState.Status.Ready.Condition(0)
 		require.Equal(t, status.ListenerConditionReasonInvalid, condition.Reason)
 	})
@@ -390,7 +547,7 @@ func TestListenerValidate(t *testing.T) {
 			TLS:      &gwv1beta1.GatewayTLSConfig{},
 		}
 		listenerState := &state.ListenerState{}
-		validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		condition := listenerState.Status.ResolvedRefs.Condition(0)
 		require.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
 	})
@@ -406,7 +563,7 @@ func TestListenerValidate(t *testing.T) {
 		}
 		listenerState := &state.ListenerState{}
 		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(nil, expected)
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.True(t, errors.Is(err, expected))
 	})
 
@@ -422,7 +579,7 @@ func TestListenerValidate(t *testing.T) {
 		listenerState := &state.ListenerState{}
 		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.ResolvedRefs.Condition(0)
@@ -443,7 +600,7 @@ func TestListenerValidate(t *testing.T) {
 		listenerState := &state.ListenerState{}
 		client.EXPECT().GetReferenceGrantsInNamespace(gomock.Any(), string(otherNamespace)).Return([]gwv1alpha2.ReferenceGrant{}, nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.ResolvedRefs.Condition(0)
@@ -480,13 +637,9 @@ func TestListenerValidate(t *testing.T) {
 					}},
 				},
 			}}, nil)
-		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(&core.Secret{
-			ObjectMeta: meta.ObjectMeta{
-				Name: "secret",
-			},
-		}, nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, gateway, listener)
+		err := validator.validateTLS(context.Background(), listenerState, gateway, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 		assert.Len(t, listenerState.TLS.Certificates, 1)
 
@@ -504,15 +657,116 @@ func TestListenerValidate(t *testing.T) {
 			},
 		}
 		listenerState := &state.ListenerState{}
-		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(&core.Secret{
-			ObjectMeta: meta.ObjectMeta{
-				Name: "secret",
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+		assert.Len(t, listenerState.TLS.Certificates, 1)
+	})
+
+	t.Run("Invalid secret type", func(t *testing.T) {
+		listener := gwv1beta1.Listener{
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
 			},
-		}, nil)
+		}
+		listenerState := &state.ListenerState{}
+		secret := tlsSecret(gwTesting.DefaultTestServerCertificate)
+		secret.Type = core.SecretTypeOpaque
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(secret, nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+		assert.Empty(t, listenerState.TLS.Certificates)
+
+		condition := listenerState.Status.ResolvedRefs.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
+		assert.Equal(t, "secret must be of type kubernetes.io/tls", condition.Message)
+	})
+
+	t.Run("Certificate does not cover hostname", func(t *testing.T) {
+		hostname := gwv1beta1.Hostname("example.com")
+		listener := gwv1beta1.Listener{
+			Hostname: &hostname,
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+			},
+		}
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+		assert.Empty(t, listenerState.TLS.Certificates)
+
+		condition := listenerState.Status.ResolvedRefs.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
+		assert.Equal(t, `certificate SANs do not cover listener hostname "example.com"`, condition.Message)
+	})
+
+	t.Run("Certificate near expiry", func(t *testing.T) {
+		listener := gwv1beta1.Listener{
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+			},
+		}
+		listenerState := &state.ListenerState{}
+		certificate, err := gwTesting.GenerateSignedCertificate(gwTesting.GenerateCertificateOptions{
+			CA:          gwTesting.DefaultTestCA,
+			ServiceName: "expiring",
+			Expiration:  time.Now().Add(24 * time.Hour),
+		})
+		require.NoError(t, err)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(certificate), nil)
+
+		err = validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 		assert.Len(t, listenerState.TLS.Certificates, 1)
+
+		condition := listenerState.Status.ResolvedRefs.Condition(0)
+		assert.Equal(t, meta.ConditionTrue, condition.Status)
+		condition = listenerState.Status.CertificateExpiring.Condition(0)
+		assert.Equal(t, meta.ConditionTrue, condition.Status)
+		assert.Equal(t, status.ListenerConditionReasonNearExpiry, condition.Reason)
+		// rechecked once it expires
+		assert.WithinDuration(t, time.Now().Add(24*time.Hour), listenerState.CertificateRecheck, time.Minute)
+	})
+
+	t.Run("Certificate outside expiry window", func(t *testing.T) {
+		listener := gwv1beta1.Listener{
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+			},
+		}
+		listenerState := &state.ListenerState{}
+		certificate, err := gwTesting.GenerateSignedCertificate(gwTesting.GenerateCertificateOptions{
+			CA:          gwTesting.DefaultTestCA,
+			ServiceName: "valid",
+			Expiration:  time.Now().Add(90 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(certificate), nil)
+
+		err = validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+		assert.Len(t, listenerState.TLS.Certificates, 1)
+
+		condition := listenerState.Status.CertificateExpiring.Condition(0)
+		assert.Equal(t, meta.ConditionFalse, condition.Status)
+		// rechecked once it enters the warning window
+		assert.WithinDuration(t, time.Now().Add(60*24*time.Hour), listenerState.CertificateRecheck, time.Minute)
 	})
 
 	t.Run("Unsupported certificate type", func(t *testing.T) {
@@ -530,13 +784,242 @@ func TestListenerValidate(t *testing.T) {
 		}
 		listenerState := &state.ListenerState{}
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.ResolvedRefs.Condition(0)
 		assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
 	})
 
+	otherNamespace := gwv1beta1.Namespace("other-namespace")
+	for _, test := range []struct {
+		name         string
+		namespace    *gwv1beta1.Namespace
+		expect       func()
+		certificates []string
+		reason       string
+		message      string
+	}{{
+		name: "Valid vault kv certificate ref",
+		expect: func() {
+			client.EXPECT().GetVaultCertificate(gomock.Any(), gomock.Any()).Return(&apigwv1alpha1.VaultCertificate{
+				ObjectMeta: meta.ObjectMeta{Namespace: "default"},
+				Spec: apigwv1alpha1.VaultCertificateSpec{
+					KV: &apigwv1alpha1.VaultKVCertificate{
+						Path:            "gateways/web",
+						CertField:       "cert",
+						PrivateKeyField: "key",
+					},
+				},
+			}, nil)
+		},
+		certificates: []string{"vault+kv:///gateways/web?tlsCertField=cert&tlsPrivateKeyField=key"},
+	}, {
+		name: "Vault kv certificate ref outside of allowed paths",
+		expect: func() {
+			client.EXPECT().GetVaultCertificate(gomock.Any(), gomock.Any()).Return(&apigwv1alpha1.VaultCertificate{
+				ObjectMeta: meta.ObjectMeta{Namespace: "default"},
+				Spec: apigwv1alpha1.VaultCertificateSpec{
+					KV: &apigwv1alpha1.VaultKVCertificate{
+						Path:            "controller/credentials",
+						CertField:       "cert",
+						PrivateKeyField: "key",
+					},
+				},
+			}, nil)
+		},
+		reason:  status.ListenerConditionReasonRefNotPermitted,
+		message: `vault kv path "controller/credentials" is not allowed for namespace default`,
+	}, {
+		name: "Valid vault pki certificate ref",
+		expect: func() {
+			client.EXPECT().GetVaultCertificate(gomock.Any(), gomock.Any()).Return(&apigwv1alpha1.VaultCertificate{
+				ObjectMeta: meta.ObjectMeta{Namespace: "default"},
+				Spec: apigwv1alpha1.VaultCertificateSpec{
+					PKI: &apigwv1alpha1.VaultPKICertificate{
+						Mount:      "pki-int",
+						Role:       "web",
+						CommonName: "example.com",
+						AltNames:   []string{"a.example.com", "b.example.com"},
+						TTL:        "12h",
+					},
+				},
+			}, nil)
+		},
+		certificates: []string{"vault+pki://example.com?altNames=a.example.com%2Cb.example.com&mount=pki-int&role=web&ttl=12h"},
+	}, {
+		name: "Vault pki certificate ref with a role that isn't allowed",
+		expect: func() {
+			client.EXPECT().GetVaultCertificate(gomock.Any(), gomock.Any()).Return(&apigwv1alpha1.VaultCertificate{
+				ObjectMeta: meta.ObjectMeta{Namespace: "default"},
+				Spec: apigwv1alpha1.VaultCertificateSpec{
+					PKI: &apigwv1alpha1.VaultPKICertificate{
+						Mount:      "pki-int",
+						Role:       "admin",
+						CommonName: "example.com",
+					},
+				},
+			}, nil)
+		},
+		reason:  status.ListenerConditionReasonRefNotPermitted,
+		message: `vault pki mount "pki-int" and role "admin" are not allowed for namespace default`,
+	}, {
+		name: "Invalid vault certificate ref",
+		expect: func() {
+			client.EXPECT().GetVaultCertificate(gomock.Any(), gomock.Any()).Return(&apigwv1alpha1.VaultCertificate{}, nil)
+		},
+		reason: status.ListenerConditionReasonInvalidCertificateRef,
+	}, {
+		name: "No vault certificate found",
+		expect: func() {
+			client.EXPECT().GetVaultCertificate(gomock.Any(), gomock.Any()).Return(nil, nil)
+		},
+		reason: status.ListenerConditionReasonInvalidCertificateRef,
+	}, {
+		name:      "Invalid cross-namespace vault certificate ref with no ReferenceGrant",
+		namespace: &otherNamespace,
+		expect: func() {
+			client.EXPECT().GetReferenceGrantsInNamespace(gomock.Any(), string(otherNamespace)).Return([]gwv1alpha2.ReferenceGrant{{
+				Spec: gwv1alpha2.ReferenceGrantSpec{
+					From: []gwv1alpha2.ReferenceGrantFrom{{
+						Group: "gateway.networking.k8s.io",
+						Kind:  "Gateway",
+					}},
+					// grants access to secrets, not vault certificates
+					To: []gwv1alpha2.ReferenceGrantTo{{
+						Kind: "Secret",
+					}},
+				},
+			}}, nil)
+		},
+		reason:  status.ListenerConditionReasonRefNotPermitted,
+		message: apigwv1alpha1.VaultCertificateKind,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			group := gwv1beta1.Group(apigwv1alpha1.Group)
+			kind := gwv1beta1.Kind(apigwv1alpha1.VaultCertificateKind)
+			listener := gwv1beta1.Listener{
+				Protocol: gwv1beta1.HTTPSProtocolType,
+				TLS: &gwv1beta1.GatewayTLSConfig{
+					CertificateRefs: []gwv1beta1.SecretObjectReference{{
+						Group:     &group,
+						Kind:      &kind,
+						Namespace: test.namespace,
+						Name:      "vault",
+					}},
+				},
+			}
+			listenerState := &state.ListenerState{}
+			test.expect()
+
+			err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
+			require.NoError(t, err)
+
+			if test.reason == "" {
+				assert.Equal(t, test.certificates, listenerState.TLS.Certificates)
+				return
+			}
+			condition := listenerState.Status.ResolvedRefs.Condition(0)
+			assert.Equal(t, test.reason, condition.Reason)
+			assert.Contains(t, condition.Message, test.message)
+		})
+	}
+
+	t.Run("Valid ACME certificate ref", func(t *testing.T) {
+		hostname := gwv1beta1.Hostname("example.com")
+		listener := gwv1beta1.Listener{
+			Hostname: &hostname,
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+				Options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+					"api-gateway.consul.hashicorp.com/tls_certificate_issuer": "acme",
+				},
+			},
+		}
+		for _, secret := range []*core.Secret{nil, {
+			ObjectMeta: meta.ObjectMeta{
+				Labels: map[string]string{"api-gateway.consul.hashicorp.com/managed-by": "acme"},
+			},
+			Type: core.SecretTypeTLS,
+		}} {
+			listenerState := &state.ListenerState{}
+			client.EXPECT().GetSecret(gomock.Any(), types.NamespacedName{Namespace: "default", Name: "secret"}).Return(secret, nil)
+
+			err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{ObjectMeta: meta.ObjectMeta{Namespace: "default"}}, apigwv1alpha1.GatewayClassConfig{}, listener)
+			require.NoError(t, err)
+			require.Equal(t, []string{"acme:///default/secret?hostnames=example.com"}, listenerState.TLS.Certificates)
+
+			condition := listenerState.Status.ResolvedRefs.Condition(0)
+			assert.Equal(t, meta.ConditionTrue, condition.Status)
+		}
+	})
+
+	t.Run("Unmanaged ACME certificate secret", func(t *testing.T) {
+		hostname := gwv1beta1.Hostname("example.com")
+		listener := gwv1beta1.Listener{
+			Hostname: &hostname,
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+				Options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+					"api-gateway.consul.hashicorp.com/tls_certificate_issuer": "acme",
+				},
+			},
+		}
+		for _, secret := range []*core.Secret{{
+			// not created by the controller
+			Type: core.SecretTypeTLS,
+		}, {
+			// the account key secret
+			ObjectMeta: meta.ObjectMeta{
+				Labels: map[string]string{"api-gateway.consul.hashicorp.com/managed-by": "acme"},
+			},
+			Type: core.SecretTypeOpaque,
+		}} {
+			listenerState := &state.ListenerState{}
+			client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(secret, nil)
+
+			err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{ObjectMeta: meta.ObjectMeta{Namespace: "default"}}, apigwv1alpha1.GatewayClassConfig{}, listener)
+			require.NoError(t, err)
+			assert.Empty(t, listenerState.TLS.Certificates)
+
+			condition := listenerState.Status.ResolvedRefs.Condition(0)
+			assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
+			assert.Equal(t, "secret default/secret already exists and is not a kubernetes.io/tls secret managed by ACME", condition.Message)
+		}
+	})
+
+	t.Run("Invalid ACME certificate ref", func(t *testing.T) {
+		hostname := gwv1beta1.Hostname("*.example.com")
+		for _, hostname := range []*gwv1beta1.Hostname{nil, &hostname} {
+			listener := gwv1beta1.Listener{
+				Hostname: hostname,
+				Protocol: gwv1beta1.HTTPSProtocolType,
+				TLS: &gwv1beta1.GatewayTLSConfig{
+					CertificateRefs: []gwv1beta1.SecretObjectReference{{
+						Name: "secret",
+					}},
+					Options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+						"api-gateway.consul.hashicorp.com/tls_certificate_issuer": "acme",
+					},
+				},
+			}
+			listenerState := &state.ListenerState{}
+
+			err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
+			require.NoError(t, err)
+			assert.Empty(t, listenerState.TLS.Certificates)
+
+			condition := listenerState.Status.ResolvedRefs.Condition(0)
+			assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
+		}
+	})
+
 	t.Run("Valid minimum TLS version", func(t *testing.T) {
 		listener := gwv1beta1.Listener{
 			Protocol: gwv1beta1.HTTPSProtocolType,
@@ -550,13 +1033,9 @@ func TestListenerValidate(t *testing.T) {
 			},
 		}
 		listenerState := &state.ListenerState{}
-		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(&core.Secret{
-			ObjectMeta: meta.ObjectMeta{
-				Name: "secret",
-			},
-		}, nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.Ready.Condition(0)
@@ -577,13 +1056,9 @@ func TestListenerValidate(t *testing.T) {
 			},
 		}
 		listenerState := &state.ListenerState{}
-		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(&core.Secret{
-			ObjectMeta: meta.ObjectMeta{
-				Name: "secret",
-			},
-		}, nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.Ready.Condition(0)
@@ -604,13 +1079,9 @@ func TestListenerValidate(t *testing.T) {
 			},
 		}
 		listenerState := &state.ListenerState{}
-		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(&core.Secret{
-			ObjectMeta: meta.ObjectMeta{
-				Name: "secret",
-			},
-		}, nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.Ready.Condition(0)
@@ -618,7 +1089,7 @@ func TestListenerValidate(t *testing.T) {
 		assert.Equal(t, []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}, listenerState.TLS.CipherSuites)
 	})
 
-	t.Run("TLS cipher suite not allowed", func(t *testing.T) {
+	t.Run("TLS cipher suites ignored with TLS 1.3", func(t *testing.T) {
 		listener := gwv1beta1.Listener{
 			Protocol: gwv1beta1.HTTPSProtocolType,
 			TLS: &gwv1beta1.GatewayTLSConfig{
@@ -632,18 +1103,19 @@ func TestListenerValidate(t *testing.T) {
 			},
 		}
 		listenerState := &state.ListenerState{}
-		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(&core.Secret{
-			ObjectMeta: meta.ObjectMeta{
-				Name: "secret",
-			},
-		}, nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.Ready.Condition(0)
-		assert.Equal(t, status.ListenerConditionReasonInvalid, condition.Reason)
-		assert.Equal(t, "configuring TLS cipher suites is only supported for TLS 1.2 and earlier", condition.Message)
+		assert.Equal(t, status.ListenerConditionReasonReady, condition.Reason)
+		assert.Equal(t, "TLSv1_3", listenerState.TLS.MinVersion)
+		assert.Empty(t, listenerState.TLS.CipherSuites)
+
+		condition = listenerState.Status.TLSOptionsIgnored.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonIgnoredTLSOption, condition.Reason)
+		assert.Equal(t, "TLS cipher suites are only configurable for TLS 1.2 and earlier", condition.Message)
 	})
 
 	t.Run("Invalid TLS cipher suite", func(t *testing.T) {
@@ -659,18 +1131,364 @@ func TestListenerValidate(t *testing.T) {
 			},
 		}
 		listenerState := &state.ListenerState{}
-		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(&core.Secret{
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+
+		condition := listenerState.Status.Ready.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalid, condition.Reason)
+		assert.Equal(t, "unrecognized or unsupported TLS cipher suite: foo", condition.Message)
+	})
+
+	t.Run("Gateway TLS annotations as defaults", func(t *testing.T) {
+		gateway := &gwv1beta1.Gateway{
+			ObjectMeta: meta.ObjectMeta{
+				Annotations: map[string]string{
+					"api-gateway.consul.hashicorp.com/tls_min_version":   "TLSv1_2",
+					"api-gateway.consul.hashicorp.com/tls_max_version":   "TLSv1_3",
+					"api-gateway.consul.hashicorp.com/tls_cipher_suites": "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256",
+				},
+			},
+		}
+		listener := gwv1beta1.Listener{
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+				Options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+					"api-gateway.consul.hashicorp.com/tls_max_version": "TLSv1_2",
+				},
+			},
+		}
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+
+		condition := listenerState.Status.Ready.Condition(0)
+		assert.Equal(t, meta.ConditionTrue, condition.Status)
+		assert.Equal(t, "TLSv1_2", listenerState.TLS.MinVersion)
+		assert.Equal(t, "TLSv1_2", listenerState.TLS.MaxVersion)
+		assert.Equal(t, []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}, listenerState.TLS.CipherSuites)
+	})
+
+	t.Run("Invalid gateway TLS annotation", func(t *testing.T) {
+		gateway := &gwv1beta1.Gateway{
 			ObjectMeta: meta.ObjectMeta{
-				Name: "secret",
+				Annotations: map[string]string{
+					"api-gateway.consul.hashicorp.com/tls_min_version": "foo",
+				},
 			},
-		}, nil)
+		}
+		listener := gwv1beta1.Listener{
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+			},
+		}
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
 
-		err := validator.validateTLS(context.Background(), listenerState, &gwv1beta1.Gateway{}, listener)
+		err := validator.validateTLS(context.Background(), listenerState, gateway, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+
+		condition := listenerState.Status.Ready.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalid, condition.Reason)
+		assert.Equal(t, "unrecognized TLS min version", condition.Message)
+	})
+
+	for _, test := range []struct {
+		name        string
+		annotations map[string]string
+		options     map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue
+		expected    string
+	}{{
+		name: "ALPN protocols",
+		options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			"api-gateway.consul.hashicorp.com/tls_alpn_protocols": "h2,http/1.1",
+		},
+		expected: "TLS option api-gateway.consul.hashicorp.com/tls_alpn_protocols is not supported",
+	}, {
+		name: "ECDH curves",
+		annotations: map[string]string{
+			"api-gateway.consul.hashicorp.com/tls_ecdh_curves": "P-256",
+		},
+		options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			"api-gateway.consul.hashicorp.com/tls_ecdh_curves": "X25519",
+		},
+		expected: "TLS option api-gateway.consul.hashicorp.com/tls_ecdh_curves is not supported",
+	}} {
+		t.Run("Unsupported "+test.name, func(t *testing.T) {
+			gateway := &gwv1beta1.Gateway{
+				ObjectMeta: meta.ObjectMeta{Annotations: test.annotations},
+			}
+			listener := gwv1beta1.Listener{
+				Protocol: gwv1beta1.HTTPSProtocolType,
+				TLS: &gwv1beta1.GatewayTLSConfig{
+					CertificateRefs: []gwv1beta1.SecretObjectReference{{
+						Name: "secret",
+					}},
+					Options: test.options,
+				},
+			}
+			listenerState := &state.ListenerState{}
+			client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
+
+			err := validator.validateTLS(context.Background(), listenerState, gateway, apigwv1alpha1.GatewayClassConfig{}, listener)
+			require.NoError(t, err)
+
+			condition := listenerState.Status.Detached.Condition(0)
+			assert.Equal(t, status.ListenerConditionReasonUnsupportedExtension, condition.Reason)
+			assert.Equal(t, test.expected, condition.Message)
+		})
+	}
+
+	t.Run("Unsupported gateway TLS annotation", func(t *testing.T) {
+		gateway := &gwv1beta1.Gateway{
+			ObjectMeta: meta.ObjectMeta{
+				Annotations: map[string]string{
+					"api-gateway.consul.hashicorp.com/tls_ecdh_curves": "X25519",
+				},
+			},
+		}
+		listener := gwv1beta1.Listener{
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{{
+					Name: "secret",
+				}},
+			},
+		}
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(gwTesting.DefaultTestServerCertificate), nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, apigwv1alpha1.GatewayClassConfig{}, listener)
+		require.NoError(t, err)
+
+		// defaults from the gateway's annotations don't detach its listeners
+		condition := listenerState.Status.Detached.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonAttached, condition.Reason)
+
+		condition = listenerState.Status.TLSOptionsIgnored.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonIgnoredTLSOption, condition.Reason)
+		assert.Equal(t, "TLS option api-gateway.consul.hashicorp.com/tls_ecdh_curves set on the Gateway is not supported", condition.Message)
+	})
+}
+
+func TestListenerValidate_CertManager(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mocks.NewMockClient(ctrl)
+
+	validator := NewGatewayValidator(client, vault.CertificatePolicy{})
+
+	hostname := gwv1beta1.Hostname("example.com")
+	gateway := &gwv1beta1.Gateway{
+		ObjectMeta: meta.ObjectMeta{Namespace: "namespace", Name: "gateway", UID: "gateway"},
+		TypeMeta:   meta.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1beta1", Kind: "Gateway"},
+	}
+	config := apigwv1alpha1.GatewayClassConfig{
+		Spec: apigwv1alpha1.GatewayClassConfigSpec{
+			CertManagerSpec: &apigwv1alpha1.CertManagerSpec{
+				AllowedIssuers: []apigwv1alpha1.CertManagerIssuerReference{{Name: "issuer"}},
+			},
+		},
+	}
+	listenerWith := func(options map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue, ref gwv1beta1.SecretObjectReference) gwv1beta1.Listener {
+		return gwv1beta1.Listener{
+			Name:     "https",
+			Hostname: &hostname,
+			Protocol: gwv1beta1.HTTPSProtocolType,
+			TLS: &gwv1beta1.GatewayTLSConfig{
+				CertificateRefs: []gwv1beta1.SecretObjectReference{ref},
+				Options:         options,
+			},
+		}
+	}
+	issuerOptions := map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+		utils.CertManagerIssuerOption: "issuer",
+	}
+	readyCertificate := func(ready string) *unstructured.Unstructured {
+		certificate := utils.NewCertManagerCertificate("namespace", "gateway-https", "secret", []string{"example.com"}, utils.CertManagerIssuer{Name: "issuer", Kind: "Issuer"})
+		certificate.SetOwnerReferences([]meta.OwnerReference{*meta.NewControllerRef(gateway, gateway.GroupVersionKind())})
+		certificate.Object["status"] = map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{
+				"type":    "Ready",
+				"status":  ready,
+				"reason":  "Issuing",
+				"message": "issuing certificate",
+			}},
+		}
+		return certificate
+	}
+
+	t.Run("Certificate ready", func(t *testing.T) {
+		certificate, err := gwTesting.GenerateSignedCertificate(gwTesting.GenerateCertificateOptions{
+			CA:          gwTesting.DefaultTestCA,
+			ServiceName: "gateway",
+			ExtraSANs:   []string{"example.com"},
+			Expiration:  time.Now().Add(90 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+
+		secret := tlsSecret(certificate)
+		secret.Annotations = map[string]string{"cert-manager.io/certificate-name": "gateway-https"}
+
+		listener := listenerWith(issuerOptions, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetCertManagerCertificate(gomock.Any(), types.NamespacedName{Namespace: "namespace", Name: "gateway-https"}).Return(readyCertificate("True"), nil)
+		client.EXPECT().GetSecret(gomock.Any(), types.NamespacedName{Namespace: "namespace", Name: "secret"}).Return(secret, nil).Times(2)
+
+		err = validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+		assert.Len(t, listenerState.TLS.Certificates, 1)
+
+		condition := listenerState.Status.CertificateReady.Condition(0)
+		assert.Equal(t, meta.ConditionTrue, condition.Status)
+		condition = listenerState.Status.Ready.Condition(0)
+		assert.Equal(t, meta.ConditionTrue, condition.Status)
+	})
+
+	t.Run("Secret not yet issued", func(t *testing.T) {
+		listener := listenerWith(issuerOptions, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetCertManagerCertificate(gomock.Any(), gomock.Any()).Return(readyCertificate("False"), nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+		assert.Empty(t, listenerState.TLS.Certificates)
+
+		condition := listenerState.Status.CertificateReady.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonCertificateNotReady, condition.Reason)
+		assert.Equal(t, "Issuing: issuing certificate", condition.Message)
+		condition = listenerState.Status.Ready.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonPending, condition.Reason)
+	})
+
+	t.Run("Certificate not yet created", func(t *testing.T) {
+		listener := listenerWith(issuerOptions, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetCertManagerCertificate(gomock.Any(), gomock.Any()).Return(nil, nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+
+		condition := listenerState.Status.CertificateReady.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonCertificateNotReady, condition.Reason)
+		condition = listenerState.Status.Ready.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonPending, condition.Reason)
+	})
+
+	t.Run("Issuer not allowed", func(t *testing.T) {
+		listener := listenerWith(map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			utils.CertManagerClusterIssuerOption: "issuer",
+		}, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+		assert.Empty(t, listenerState.TLS.Certificates)
+
+		condition := listenerState.Status.ResolvedRefs.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonRefNotPermitted, condition.Reason)
+		assert.Equal(t, `cert-manager ClusterIssuer "issuer" is not allowed by the GatewayClassConfig`, condition.Message)
+	})
+
+	t.Run("Certificate not owned by the Gateway", func(t *testing.T) {
+		certificate := readyCertificate("True")
+		certificate.SetOwnerReferences(nil)
+
+		listener := listenerWith(issuerOptions, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetCertManagerCertificate(gomock.Any(), gomock.Any()).Return(certificate, nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+		assert.Empty(t, listenerState.TLS.Certificates)
+
+		condition := listenerState.Status.ResolvedRefs.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
+	})
+
+	t.Run("Secret not issued for the Certificate", func(t *testing.T) {
+		certificate, err := gwTesting.GenerateSignedCertificate(gwTesting.GenerateCertificateOptions{
+			CA:          gwTesting.DefaultTestCA,
+			ServiceName: "gateway",
+			ExtraSANs:   []string{"example.com"},
+		})
+		require.NoError(t, err)
+
+		listener := listenerWith(issuerOptions, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetCertManagerCertificate(gomock.Any(), gomock.Any()).Return(nil, nil)
+		client.EXPECT().GetSecret(gomock.Any(), gomock.Any()).Return(tlsSecret(certificate), nil)
+
+		err = validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+		assert.Empty(t, listenerState.TLS.Certificates)
+
+		condition := listenerState.Status.ResolvedRefs.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
+	})
+
+	t.Run("cert-manager not installed", func(t *testing.T) {
+		listener := listenerWith(issuerOptions, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetCertManagerCertificate(gomock.Any(), gomock.Any()).Return(nil, gatewayclient.ErrCertManagerNotInstalled)
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+
+		condition := listenerState.Status.Ready.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalid, condition.Reason)
+	})
+
+	t.Run("Cross-namespace secret ref", func(t *testing.T) {
+		namespace := gwv1beta1.Namespace("other")
+		listener := listenerWith(issuerOptions, gwv1beta1.SecretObjectReference{Name: "secret", Namespace: &namespace})
+		listenerState := &state.ListenerState{}
+		client.EXPECT().GetReferenceGrantsInNamespace(gomock.Any(), "other").
+			Return([]gwv1alpha2.ReferenceGrant{{
+				Spec: gwv1alpha2.ReferenceGrantSpec{
+					From: []gwv1alpha2.ReferenceGrantFrom{{
+						Group:     "gateway.networking.k8s.io",
+						Kind:      "Gateway",
+						Namespace: "namespace",
+					}},
+					To: []gwv1alpha2.ReferenceGrantTo{{
+						Kind: "Secret",
+					}},
+				},
+			}}, nil)
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
+		require.NoError(t, err)
+
+		condition := listenerState.Status.ResolvedRefs.Condition(0)
+		assert.Equal(t, status.ListenerConditionReasonInvalidCertificateRef, condition.Reason)
+	})
+
+	t.Run("Combined with certificate issuer", func(t *testing.T) {
+		listener := listenerWith(map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			utils.CertManagerClusterIssuerOption:                      "issuer",
+			"api-gateway.consul.hashicorp.com/tls_certificate_issuer": "acme",
+		}, gwv1beta1.SecretObjectReference{Name: "secret"})
+		listenerState := &state.ListenerState{}
+
+		err := validator.validateTLS(context.Background(), listenerState, gateway, config, listener)
 		require.NoError(t, err)
 
 		condition := listenerState.Status.Ready.Condition(0)
 		assert.Equal(t, status.ListenerConditionReasonInvalid, condition.Reason)
-		assert.Equal(t, "unrecognized or unsupported TLS cipher suite: foo", condition.Message)
 	})
 }
 
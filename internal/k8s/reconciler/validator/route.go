@@ -13,6 +13,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	"github.com/hashicorp/consul/api"
+
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/reconciler/state"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
@@ -80,9 +82,11 @@ func (r *RouteValidator) validateHTTPRoute(ctx context.Context, state *state.Rou
 				if !errors.As(err, &resolutionError) {
 					return err
 				}
+				watchUnresolvedService(state, resolutionError)
 				state.ResolutionErrors.Add(resolutionError)
 				continue
 			}
+			watchResolvedService(state, reference)
 			reference.Reference.Set(&ref)
 			state.References.Add(routeRule, *reference)
 		}
@@ -124,15 +128,39 @@ func (r *RouteValidator) validateTCPRoute(ctx context.Context, state *state.Rout
 		if !errors.As(err, &resolutionError) {
 			return err
 		}
+		watchUnresolvedService(state, resolutionError)
 		state.ResolutionErrors.Add(resolutionError)
 		return nil
 	}
 
+	watchResolvedService(state, reference)
 	reference.Reference.Set(&ref)
 	state.References.Add(routeRule, *reference)
 	return nil
 }
 
+// watchUnresolvedService tracks a Consul service that could not be found so that
+// the route gets re-resolved once the service is registered.
+func watchUnresolvedService(state *state.RouteState, err service.ResolutionError) {
+	if name, ok := err.ConsulService(); ok {
+		state.WatchConsulService(name, false)
+	}
+}
+
+// watchResolvedService tracks the Consul service a backend resolved to so that
+// the route gets re-resolved if the service is deregistered. External services
+// are registered by us and imported services never show up in the local
+// catalog, so neither is watched.
+func watchResolvedService(state *state.RouteState, reference *service.ResolvedReference) {
+	if reference.Consul == nil || reference.Consul.External != nil || reference.Consul.Peer != "" {
+		return
+	}
+	state.WatchConsulService(api.CompoundServiceName{
+		Name:      reference.Consul.Name,
+		Namespace: reference.Consul.Namespace,
+	}, true)
+}
+
 // routeAllowedForBackendRef determines whether the route is allowed
 // for the backend either by being in the same namespace or by having
 // an applicable ReferenceGrant in the same namespace as the backend.
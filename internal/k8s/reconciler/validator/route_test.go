@@ -15,6 +15,9 @@ import (
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
+	"github.com/hashicorp/consul/api"
+
+	"github.com/hashicorp/consul-api-gateway/internal/consul"
 	clientMocks "github.com/hashicorp/consul-api-gateway/internal/k8s/gatewayclient/mocks"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/service"
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/service/mocks"
@@ -91,6 +94,54 @@ func TestRouteValidate(t *testing.T) {
 	assert.False(t, routeState.ResolutionErrors.Empty())
 }
 
+func TestRouteValidateWatchesConsulServices(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	resolver := mocks.NewMockBackendResolver(ctrl)
+	client := clientMocks.NewMockClient(ctrl)
+
+	validator := NewRouteValidator(resolver, client)
+
+	found := gwv1alpha2.BackendObjectReference{Name: "found"}
+	missing := gwv1alpha2.BackendObjectReference{Name: "missing"}
+	external := gwv1alpha2.BackendObjectReference{Name: "external"}
+
+	resolver.EXPECT().Resolve(gomock.Any(), gomock.Any(), found).Return(service.NewConsulServiceReference(&service.ConsulService{
+		Name:      "found",
+		Namespace: "consul",
+	}), nil)
+	resolver.EXPECT().Resolve(gomock.Any(), gomock.Any(), missing).Return(nil, service.NewBackendNotFoundError("missing").WithConsulService(api.CompoundServiceName{
+		Name:      "missing",
+		Namespace: "consul",
+	}))
+	resolver.EXPECT().Resolve(gomock.Any(), gomock.Any(), external).Return(service.NewConsulServiceReference(&service.ConsulService{
+		Name:     "external",
+		External: &service.ExternalService{Address: "example.com", Port: 443},
+	}), nil)
+
+	backendRefs := []gwv1alpha2.HTTPBackendRef{}
+	for _, reference := range []gwv1alpha2.BackendObjectReference{found, missing, external} {
+		backendRefs = append(backendRefs, gwv1alpha2.HTTPBackendRef{
+			BackendRef: gwv1alpha2.BackendRef{BackendObjectReference: reference},
+		})
+	}
+
+	routeState, err := validator.Validate(context.Background(), &gwv1alpha2.HTTPRoute{
+		Spec: gwv1alpha2.HTTPRouteSpec{
+			Rules: []gwv1alpha2.HTTPRouteRule{{BackendRefs: backendRefs}},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []consul.WatchedService{{
+		Name:       api.CompoundServiceName{Name: "found", Namespace: "consul"},
+		Registered: true,
+	}, {
+		Name: api.CompoundServiceName{Name: "missing", Namespace: "consul"},
+	}}, routeState.ConsulServices)
+}
+
 func TestRouteValidateDontAllowCrossNamespace(t *testing.T) {
 	t.Parallel()
 
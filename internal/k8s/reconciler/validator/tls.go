@@ -3,9 +3,117 @@
 
 package validator
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/hashicorp/consul-api-gateway/internal/common"
+	"github.com/hashicorp/consul-api-gateway/internal/core"
+)
+
 const (
-	annotationKeyPrefix          = "api-gateway.consul.hashicorp.com/"
-	tlsMinVersionAnnotationKey   = annotationKeyPrefix + "tls_min_version"
-	tlsMaxVersionAnnotationKey   = annotationKeyPrefix + "tls_max_version"
-	tlsCipherSuitesAnnotationKey = annotationKeyPrefix + "tls_cipher_suites"
+	annotationKeyPrefix           = "api-gateway.consul.hashicorp.com/"
+	tlsMinVersionAnnotationKey    = annotationKeyPrefix + "tls_min_version"
+	tlsMaxVersionAnnotationKey    = annotationKeyPrefix + "tls_max_version"
+	tlsCipherSuitesAnnotationKey  = annotationKeyPrefix + "tls_cipher_suites"
+	tlsALPNProtocolsAnnotationKey = annotationKeyPrefix + "tls_alpn_protocols"
+	tlsECDHCurvesAnnotationKey    = annotationKeyPrefix + "tls_ecdh_curves"
+
+	// tlsCertificateIssuerAnnotationKey marks a listener's certificate reference as
+	// the secret a certificate is issued into rather than one that is managed externally
+	tlsCertificateIssuerAnnotationKey = annotationKeyPrefix + "tls_certificate_issuer"
+	acmeCertificateIssuer             = "acme"
 )
+
+// tlsOption returns the value of a TLS option for a listener, options set in the
+// listener's TLS config take precedence over the Gateway's annotations, which
+// act as defaults for all of its listeners
+func tlsOption(gateway *gwv1beta1.Gateway, listener gwv1beta1.Listener, key string) string {
+	if listener.TLS != nil {
+		if value := listener.TLS.Options[gwv1beta1.AnnotationKey(key)]; value != "" {
+			return string(value)
+		}
+	}
+	return gateway.Annotations[key]
+}
+
+// splitTLSOption splits a comma delimited TLS option and trims any whitespace
+func splitTLSOption(value string) []string {
+	values := strings.Split(value, ",")
+	for i, v := range values {
+		values[i] = strings.TrimSpace(v)
+	}
+	return values
+}
+
+// unsupportedTLSOptions are recognized TLS options that can't be applied because
+// Consul's ingress gateway config entries have no settings for them
+var unsupportedTLSOptions = []string{
+	tlsALPNProtocolsAnnotationKey,
+	tlsECDHCurvesAnnotationKey,
+}
+
+// validateUnsupportedTLSOptions returns an error if a listener sets a TLS option
+// that the controller recognizes but can't configure, rather than ignoring it.
+// Options that are only set as defaults in the Gateway's annotations apply to every
+// listener, so those are returned as ignored instead of detaching the listeners.
+func validateUnsupportedTLSOptions(gateway *gwv1beta1.Gateway, listener gwv1beta1.Listener) ([]string, error) {
+	ignored := []string{}
+	for _, key := range unsupportedTLSOptions {
+		if listener.TLS != nil && listener.TLS.Options[gwv1beta1.AnnotationKey(key)] != "" {
+			return nil, fmt.Errorf("TLS option %s is not supported", key)
+		}
+		if gateway.Annotations[key] != "" {
+			ignored = append(ignored, fmt.Sprintf("TLS option %s set on the Gateway is not supported", key))
+		}
+	}
+	return ignored, nil
+}
+
+// validateTLSOptions validates the TLS versions and cipher suites configured for a
+// listener and sets them on the listener's TLS params. Cipher suites can't be configured
+// for TLS 1.3, so they're returned as ignored when that's the minimum version.
+func validateTLSOptions(gateway *gwv1beta1.Gateway, listener gwv1beta1.Listener, params *core.TLSParams) ([]string, error) {
+	tlsMinVersion := tlsOption(gateway, listener, tlsMinVersionAnnotationKey)
+	tlsMaxVersion := tlsOption(gateway, listener, tlsMaxVersionAnnotationKey)
+	tlsCipherSuites := tlsOption(gateway, listener, tlsCipherSuitesAnnotationKey)
+
+	ignored := []string{}
+	if tlsMinVersion != "" {
+		if _, ok := common.SupportedTLSVersions[tlsMinVersion]; !ok {
+			return nil, errors.New("unrecognized TLS min version")
+		}
+
+		if tlsCipherSuites != "" {
+			if _, ok := common.TLSVersionsWithConfigurableCipherSuites[tlsMinVersion]; !ok {
+				ignored = append(ignored, "TLS cipher suites are only configurable for TLS 1.2 and earlier")
+				tlsCipherSuites = ""
+			}
+		}
+
+		params.MinVersion = tlsMinVersion
+	}
+
+	if tlsMaxVersion != "" {
+		if _, ok := common.SupportedTLSVersions[tlsMaxVersion]; !ok {
+			return nil, errors.New("unrecognized TLS max version")
+		}
+
+		params.MaxVersion = tlsMaxVersion
+	}
+
+	if tlsCipherSuites != "" {
+		cipherSuites := splitTLSOption(tlsCipherSuites)
+		for _, c := range cipherSuites {
+			if !common.SupportedTLSCipherSuite(c) {
+				return nil, fmt.Errorf("unrecognized or unsupported TLS cipher suite: %s", c)
+			}
+		}
+		params.CipherSuites = cipherSuites
+	}
+
+	return ignored, nil
+}
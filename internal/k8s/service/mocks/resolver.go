@@ -10,6 +10,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	service "github.com/hashicorp/consul-api-gateway/internal/k8s/service"
+	v1alpha1 "github.com/hashicorp/consul-api-gateway/pkg/apis/v1alpha1"
 	v1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
@@ -50,3 +51,18 @@ func (mr *MockBackendResolverMockRecorder) Resolve(ctx, namespace, ref interface
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockBackendResolver)(nil).Resolve), ctx, namespace, ref)
 }
+
+// ResolveMeshService mocks base method.
+func (m *MockBackendResolver) ResolveMeshService(ctx context.Context, meshService *v1alpha1.MeshService) (*service.ResolvedReference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveMeshService", ctx, meshService)
+	ret0, _ := ret[0].(*service.ResolvedReference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveMeshService indicates an expected call of ResolveMeshService.
+func (mr *MockBackendResolverMockRecorder) ResolveMeshService(ctx, meshService interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveMeshService", reflect.TypeOf((*MockBackendResolver)(nil).ResolveMeshService), ctx, meshService)
+}
@@ -8,12 +8,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 	gwv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
 
@@ -39,6 +42,7 @@ const (
 	GenericResolutionErrorType       ServiceResolutionErrorType = "GenericResolutionError"
 	InvalidKindErrorType             ServiceResolutionErrorType = "InvalidKindError"
 	NoResolutionErrorType            ServiceResolutionErrorType = "NoResolutionError"
+	PortNotFoundErrorType            ServiceResolutionErrorType = "PortNotFoundError"
 	RefNotPermittedErrorType         ServiceResolutionErrorType = "RefNotPermittedError"
 )
 
@@ -50,30 +54,36 @@ var errorTypePrefixMap = map[ServiceResolutionErrorType]string{
 type ResolutionError struct {
 	inner  string
 	remote ServiceResolutionErrorType
+	// service is the Consul service that could not be found, if known
+	service *api.CompoundServiceName
 }
 
 func NewResolutionError(inner string) ResolutionError {
-	return ResolutionError{inner, GenericResolutionErrorType}
+	return ResolutionError{inner: inner, remote: GenericResolutionErrorType}
 }
 
 func NewK8sResolutionError(inner string) ResolutionError {
-	return ResolutionError{inner, K8sServiceResolutionErrorType}
+	return ResolutionError{inner: inner, remote: K8sServiceResolutionErrorType}
 }
 
 func NewBackendNotFoundError(inner string) ResolutionError {
-	return ResolutionError{inner, BackendNotFoundErrorType}
+	return ResolutionError{inner: inner, remote: BackendNotFoundErrorType}
 }
 
 func NewConsulResolutionError(inner string) ResolutionError {
-	return ResolutionError{inner, ConsulServiceResolutionErrorType}
+	return ResolutionError{inner: inner, remote: ConsulServiceResolutionErrorType}
 }
 
 func NewInvalidKindError(inner string) ResolutionError {
-	return ResolutionError{inner, InvalidKindErrorType}
+	return ResolutionError{inner: inner, remote: InvalidKindErrorType}
+}
+
+func NewPortNotFoundError(inner string) ResolutionError {
+	return ResolutionError{inner: inner, remote: PortNotFoundErrorType}
 }
 
 func NewRefNotPermittedError(inner string) ResolutionError {
-	return ResolutionError{inner, RefNotPermittedErrorType}
+	return ResolutionError{inner: inner, remote: RefNotPermittedErrorType}
 }
 
 func getErrorTypePrefix(errType ServiceResolutionErrorType) string {
@@ -84,6 +94,22 @@ func (r ResolutionError) Error() string {
 	return r.inner
 }
 
+// WithConsulService annotates the error with the name of the Consul service
+// that could not be found so that it can be watched for registration.
+func (r ResolutionError) WithConsulService(name api.CompoundServiceName) ResolutionError {
+	r.service = &name
+	return r
+}
+
+// ConsulService returns the name of the Consul service that could not be found
+// if the error was caused by a missing catalog registration.
+func (r ResolutionError) ConsulService() (api.CompoundServiceName, bool) {
+	if r.service == nil {
+		return api.CompoundServiceName{}, false
+	}
+	return *r.service, true
+}
+
 type ResolutionErrors struct {
 	errors map[ServiceResolutionErrorType][]ResolutionError
 }
@@ -156,11 +182,42 @@ const (
 
 	MetaKeyKubeServiceName = "k8s-service-name"
 	MetaKeyKubeNS          = "k8s-namespace"
+
+	// AnnotationExternalService opts a Kubernetes Service that is not part of
+	// the service mesh into being registered as a Consul external service
+	// and reached through a terminating gateway.
+	AnnotationExternalService = "api-gateway.consul.hashicorp.com/external-service"
 )
 
 type ConsulService struct {
 	Namespace string
 	Name      string
+	// Peer is set when the service is imported from a cluster peer and
+	// so never shows up in the local catalog.
+	Peer string `json:",omitempty"`
+	// External is set when the service is not registered in the mesh and
+	// needs to be registered in Consul as an external service.
+	External *ExternalService `json:",omitempty"`
+}
+
+// ExternalService holds the address information needed to register a
+// backend living outside of the service mesh in the Consul catalog.
+type ExternalService struct {
+	Address string
+	Port    int
+	TLS     *ExternalServiceTLS `json:",omitempty"`
+	// Owner is the Kubernetes object the service is registered for, objects in
+	// different namespaces can map to the same Consul service name
+	Owner string
+}
+
+// ExternalServiceTLS holds the options a terminating gateway uses to
+// originate TLS to an external service.
+type ExternalServiceTLS struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	SNI      string
 }
 
 type BackendReference struct {
@@ -196,6 +253,7 @@ func NewConsulServiceReference(consul *ConsulService) *ResolvedReference {
 
 type BackendResolver interface {
 	Resolve(ctx context.Context, namespace string, ref gwv1alpha2.BackendObjectReference) (*ResolvedReference, error)
+	ResolveMeshService(ctx context.Context, meshService *apigwv1alpha1.MeshService) (*ResolvedReference, error)
 }
 
 type backendResolver struct {
@@ -233,15 +291,25 @@ func (r *backendResolver) Resolve(ctx context.Context, namespace string, ref gwv
 		if ref.Port == nil {
 			return nil, NewK8sResolutionError("service port must not be empty")
 		}
-		return r.consulServiceForK8SService(ctx, namespacedName)
+		return r.consulServiceForK8SService(ctx, namespacedName, int(*ref.Port))
 	case group == apigwv1alpha1.GroupVersion.Group && kind == apigwv1alpha1.MeshServiceKind:
-		return r.consulServiceForMeshService(ctx, namespacedName)
+		return r.consulServiceForMeshService(ctx, namespacedName, refPort(ref))
+	case group == apigwv1alpha1.GroupVersion.Group && kind == apigwv1alpha1.ExternalServiceKind:
+		return r.consulServiceForExternalService(ctx, namespacedName, refPort(ref))
 	default:
 		return nil, NewInvalidKindError(fmt.Sprintf("unsupported reference kind %s", kind))
 	}
 }
 
-func (r *backendResolver) consulServiceForK8SService(ctx context.Context, namespacedName types.NamespacedName) (*ResolvedReference, error) {
+// refPort returns the port a backend reference targets, or 0 if it doesn't set one
+func refPort(ref gwv1alpha2.BackendObjectReference) int {
+	if ref.Port == nil {
+		return 0
+	}
+	return int(*ref.Port)
+}
+
+func (r *backendResolver) consulServiceForK8SService(ctx context.Context, namespacedName types.NamespacedName, port int) (*ResolvedReference, error) {
 	var err error
 	var resolved *ResolvedReference
 
@@ -255,16 +323,45 @@ func (r *backendResolver) consulServiceForK8SService(ctx context.Context, namesp
 		return nil, NewBackendNotFoundError(fmt.Sprintf("service %s not found", namespacedName))
 	}
 
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		return r.externalServiceForExternalName(service, port)
+	}
+
+	servicePort, found := findServicePort(service, port)
+	if !found {
+		return nil, NewPortNotFoundError(fmt.Sprintf("service %s does not expose port %d", namespacedName, port))
+	}
+
+	if isExternalService(service) {
+		return r.externalServiceForK8SService(service, port)
+	}
+
+	targetPorts, err := r.targetPorts(ctx, service, servicePort)
+	if err != nil {
+		return nil, err
+	}
+
 	// we do an inner retry since consul may take some time to sync
 	err = backoff.Retry(func() error {
 		r.logger.Trace("attempting to resolve global catalog service")
-		resolved, err = r.findGlobalCatalogService(service)
+		resolved, err = r.findGlobalCatalogService(service, servicePort, targetPorts)
 		if err != nil {
 			r.logger.Trace("error resolving global catalog reference", "error", err)
+			var resolutionErr ResolutionError
+			if errors.As(err, &resolutionErr) && resolutionErr.remote == PortNotFoundErrorType {
+				// the registered services won't start serving another port by waiting
+				return backoff.Permanent(err)
+			}
 			return err
 		}
 		if resolved == nil {
-			return NewBackendNotFoundError(fmt.Sprintf("consul service %s not found", namespacedName))
+			// we look services up by their Kubernetes metadata, but when nothing is
+			// registered yet the best guess for a name to watch is the one consul-k8s
+			// registers by default
+			return NewBackendNotFoundError(fmt.Sprintf("consul service %s not found", namespacedName)).WithConsulService(api.CompoundServiceName{
+				Name:      service.Name,
+				Namespace: r.mapper(service.Namespace),
+			})
 		}
 		return nil
 	}, backoff.WithContext(backoff.WithMaxRetries(backoff.NewConstantBackOff(1*time.Second), 30), ctx))
@@ -275,34 +372,206 @@ func (r *backendResolver) consulServiceForK8SService(ctx context.Context, namesp
 	return resolved, nil
 }
 
-func validateAgentConsulReference(services map[string]*api.AgentService) (*ResolvedReference, error) {
-	serviceName := ""
-	serviceNamespace := ""
-	for _, service := range services {
-		if serviceName == "" {
-			serviceName = service.Service
+// findServicePort returns the port of the Kubernetes Service that a backend
+// reference's port maps to
+func findServicePort(service *corev1.Service, port int) (corev1.ServicePort, bool) {
+	for _, servicePort := range service.Spec.Ports {
+		if int(servicePort.Port) == port {
+			return servicePort, true
 		}
-		if serviceNamespace == "" {
-			serviceNamespace = service.Namespace
+	}
+	return corev1.ServicePort{}, false
+}
+
+// targetPorts returns the ports that the Service's pods serve a Service port on. Named
+// target ports are resolved through the Service's endpoints, which are empty until
+// the Service has ready pods.
+func (r *backendResolver) targetPorts(ctx context.Context, service *corev1.Service, servicePort corev1.ServicePort) ([]int, error) {
+	switch {
+	case servicePort.TargetPort.Type == intstr.String && servicePort.TargetPort.StrVal != "":
+		endpoints, err := r.client.GetEndpoints(ctx, types.NamespacedName{Name: service.Name, Namespace: service.Namespace})
+		if err != nil {
+			return nil, err
 		}
-		if service.Service != serviceName || service.Namespace != serviceNamespace {
-			return nil,
-				NewConsulResolutionError(fmt.Sprintf(
-					"must have a single service map to a kubernetes service, found - (%q, %q) and (%q, %q)",
-					serviceNamespace, serviceName, service.Namespace, service.Service,
-				))
+		ports := []int{}
+		if endpoints == nil {
+			return ports, nil
 		}
+		for _, subset := range endpoints.Subsets {
+			for _, endpointPort := range subset.Ports {
+				if endpointPort.Name == servicePort.Name && !slices.Contains(ports, int(endpointPort.Port)) {
+					ports = append(ports, int(endpointPort.Port))
+				}
+			}
+		}
+		return ports, nil
+	case servicePort.TargetPort.Type == intstr.Int && servicePort.TargetPort.IntVal != 0:
+		return []int{int(servicePort.TargetPort.IntVal)}, nil
+	default:
+		return []int{int(servicePort.Port)}, nil
+	}
+}
+
+// externalServiceOwner identifies the Kubernetes object an external service is registered for
+func externalServiceOwner(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+func isExternalService(service *corev1.Service) bool {
+	return service.Annotations[AnnotationExternalService] == "true"
+}
+
+// externalServiceForK8SService resolves a Kubernetes Service that has opted
+// out of the service mesh to a Consul external service that will be registered
+// pointing at the Service's cluster IP.
+func (r *backendResolver) externalServiceForK8SService(service *corev1.Service, port int) (*ResolvedReference, error) {
+	if service.Spec.ClusterIP == "" || service.Spec.ClusterIP == corev1.ClusterIPNone {
+		return nil, NewK8sResolutionError(fmt.Sprintf("external service %s/%s must have a cluster IP", service.Namespace, service.Name))
 	}
+
 	return NewConsulServiceReference(&ConsulService{
-		Name:      serviceName,
-		Namespace: serviceNamespace,
+		Name:      service.Name,
+		Namespace: r.mapper(service.Namespace),
+		External: &ExternalService{
+			Address: service.Spec.ClusterIP,
+			Port:    port,
+			Owner:   externalServiceOwner("Service", service.Namespace, service.Name),
+		},
+	}), nil
+}
+
+// externalServiceForExternalName resolves an ExternalName Kubernetes Service to a
+// Consul external service that will be registered pointing at its external hostname.
+func (r *backendResolver) externalServiceForExternalName(service *corev1.Service, port int) (*ResolvedReference, error) {
+	if service.Spec.ExternalName == "" {
+		return nil, NewK8sResolutionError(fmt.Sprintf("external name service %s/%s must specify an external name", service.Namespace, service.Name))
+	}
+
+	return NewConsulServiceReference(&ConsulService{
+		Name:      service.Name,
+		Namespace: r.mapper(service.Namespace),
+		External: &ExternalService{
+			Address: service.Spec.ExternalName,
+			Port:    port,
+			Owner:   externalServiceOwner("Service", service.Namespace, service.Name),
+		},
+	}), nil
+}
+
+// consulServiceForExternalService resolves an ExternalService, if the backend
+// reference sets a port it must be the one the ExternalService listens on
+func (r *backendResolver) consulServiceForExternalService(ctx context.Context, namespacedName types.NamespacedName, port int) (*ResolvedReference, error) {
+	service, err := r.client.GetExternalService(ctx, namespacedName)
+	if err != nil {
+		r.logger.Trace("error retrieving external service", "error", err, "name", namespacedName.Name, "namespace", namespacedName.Namespace)
+		return nil, err
+	}
+	if service == nil {
+		return nil, NewBackendNotFoundError(fmt.Sprintf("kubernetes external service object %s not found", namespacedName))
+	}
+	if port != 0 && port != int(service.Spec.Port) {
+		return nil, NewPortNotFoundError(fmt.Sprintf("external service %s listens on port %d, not %d", namespacedName, service.Spec.Port, port))
+	}
+
+	name := service.Spec.Name
+	if name == "" {
+		name = service.Name
+	}
+
+	external := &ExternalService{
+		Address: service.Spec.Address,
+		Port:    int(service.Spec.Port),
+		Owner:   externalServiceOwner(apigwv1alpha1.ExternalServiceKind, service.Namespace, service.Name),
+	}
+	if tls := service.Spec.TLS; tls != nil {
+		external.TLS = &ExternalServiceTLS{
+			CAFile:   tls.CAFile,
+			CertFile: tls.CertFile,
+			KeyFile:  tls.KeyFile,
+			SNI:      tls.SNI,
+		}
+	}
+
+	return NewConsulServiceReference(&ConsulService{
+		Name:      name,
+		Namespace: r.mapper(service.Namespace),
+		External:  external,
 	}), nil
 }
 
+func validateAgentConsulReference(services map[string]*api.AgentService, servicePort corev1.ServicePort, targetPorts []int) (*ResolvedReference, error) {
+	candidates := make(map[api.CompoundServiceName][]*api.AgentService)
+	for _, service := range services {
+		name := api.CompoundServiceName{Name: service.Service, Namespace: service.Namespace}
+		candidates[name] = append(candidates[name], service)
+	}
+
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// a Kubernetes service exposing several ports can be registered as a separate
+	// Consul service per port, so pick the ones for our port, a single registered
+	// service is assumed to serve every port of the Kubernetes service
+	if len(candidates) > 1 {
+		for name, instances := range candidates {
+			if !consulServiceMatchesPort(name.Name, instances, servicePort, targetPorts) {
+				delete(candidates, name)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, NewPortNotFoundError(fmt.Sprintf("no consul service registered for port %d", servicePort.Port))
+	}
+
+	if len(candidates) > 1 {
+		names := []string{}
+		for name := range candidates {
+			names = append(names, fmt.Sprintf("(%q, %q)", name.Namespace, name.Name))
+		}
+		sort.Strings(names)
+		return nil,
+			NewConsulResolutionError(fmt.Sprintf(
+				"must have a single service map to a kubernetes service port, found - %s",
+				strings.Join(names, " and "),
+			))
+	}
+
+	for name := range candidates {
+		return NewConsulServiceReference(&ConsulService{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+		}), nil
+	}
+	return nil, nil
+}
+
+// consulServiceMatchesPort checks whether a Consul service registered for a
+// Kubernetes service is the one serving the given port, either by the port the
+// service instances listen on or by being named after the port.
+func consulServiceMatchesPort(name string, instances []*api.AgentService, servicePort corev1.ServicePort, targetPorts []int) bool {
+	portNames := []string{servicePort.Name}
+	if servicePort.TargetPort.Type == intstr.String {
+		portNames = append(portNames, servicePort.TargetPort.StrVal)
+	}
+	for _, portName := range portNames {
+		if portName != "" && (name == portName || strings.HasSuffix(name, "-"+portName)) {
+			return true
+		}
+	}
+
+	for _, instance := range instances {
+		if slices.Contains(targetPorts, instance.Port) {
+			return true
+		}
+	}
+	return false
+}
+
 // this acts as a brute-force mechanism for resolving a consul service if we can't find it registered
 // in our local agent -- it checks all services based on their node with the same filtering mechanism
 // we use in filtering the agent endpoint
-func (r *backendResolver) findGlobalCatalogService(service *corev1.Service) (*ResolvedReference, error) {
+func (r *backendResolver) findGlobalCatalogService(service *corev1.Service, servicePort corev1.ServicePort, targetPorts []int) (*ResolvedReference, error) {
 	nodes, _, err := r.consul.Catalog().Nodes(nil)
 	if err != nil {
 		r.logger.Trace("error retrieving nodes", "error", err)
@@ -326,6 +595,9 @@ func (r *backendResolver) findGlobalCatalogService(service *corev1.Service) (*Re
 		}
 	}
 
+	// gather the services registered for the Kubernetes service across every node so
+	// that the result doesn't depend on which node's instances happen to be seen first
+	services := make(map[string]*api.AgentService)
 	filter := fmt.Sprintf(`Meta[%q] == %q and Meta[%q] == %q and Kind != "connect-proxy"`, MetaKeyKubeServiceName, service.Name, MetaKeyKubeNS, service.Namespace)
 	for _, node := range nodes {
 		for _, namespace := range namespaces {
@@ -340,23 +612,24 @@ func (r *backendResolver) findGlobalCatalogService(service *corev1.Service) (*Re
 			if nodeWithServices == nil {
 				continue
 			}
-			if len(nodeWithServices.Services) == 0 {
-				continue
-			}
-			resolved, err := validateAgentConsulReference(nodeWithServices.Services)
-			if err != nil {
-				r.logger.Trace("error validating node services", "error", err, "node", node.Node)
-				return nil, err
-			}
-			if resolved != nil {
-				return resolved, nil
+			for id, instance := range nodeWithServices.Services {
+				services[node.Node+"/"+namespace+"/"+id] = instance
 			}
 		}
 	}
-	return nil, nil
+
+	resolved, err := validateAgentConsulReference(services, servicePort, targetPorts)
+	if err != nil {
+		r.logger.Trace("error validating catalog services", "error", err)
+		return nil, err
+	}
+	return resolved, nil
 }
 
-func (r *backendResolver) consulServiceForMeshService(ctx context.Context, namespacedName types.NamespacedName) (*ResolvedReference, error) {
+// consulServiceForMeshService resolves a MeshService, if the backend reference sets
+// a port the service's instances must listen on it. The ports of services imported
+// from a peer aren't known, so those aren't checked
+func (r *backendResolver) consulServiceForMeshService(ctx context.Context, namespacedName types.NamespacedName, port int) (*ResolvedReference, error) {
 	var err error
 	var resolved *ResolvedReference
 
@@ -383,12 +656,15 @@ func (r *backendResolver) consulServiceForMeshService(ctx context.Context, names
 					fmt.Sprintf("imported consul service %s from peer %s not found", namespacedName, *service.Spec.Peer))
 			}
 		} else {
-			resolved, err = r.findCatalogService(service)
+			resolved, err = r.findCatalogService(service, port)
 			if err != nil {
 				r.logger.Trace("error resolving global catalog reference", "error", err)
 				return err
 			} else if resolved == nil {
-				return NewConsulResolutionError(fmt.Sprintf("consul service %s not found", namespacedName))
+				return NewConsulResolutionError(fmt.Sprintf("consul service %s not found", namespacedName)).WithConsulService(api.CompoundServiceName{
+					Name:      service.Spec.Name,
+					Namespace: r.mapper(service.Namespace),
+				})
 			}
 		}
 
@@ -401,6 +677,16 @@ func (r *backendResolver) consulServiceForMeshService(ctx context.Context, names
 	return resolved, nil
 }
 
+// ResolveMeshService does a single lookup of the Consul service, either local or
+// imported from a peer, that a MeshService references. A ResolutionError is
+// returned if the service can't be found.
+func (r *backendResolver) ResolveMeshService(ctx context.Context, service *apigwv1alpha1.MeshService) (*ResolvedReference, error) {
+	if pointer.StringDeref(service.Spec.Peer, "") != "" {
+		return r.findPeerService(ctx, service)
+	}
+	return r.findCatalogService(service, 0)
+}
+
 func (r *backendResolver) findPeerService(ctx context.Context, service *apigwv1alpha1.MeshService) (*ResolvedReference, error) {
 	if pointer.StringDeref(service.Spec.Peer, "") == "" {
 		return nil, NewConsulResolutionError("peer name expected but not provided")
@@ -425,6 +711,7 @@ func (r *backendResolver) findPeerService(ctx context.Context, service *apigwv1a
 			return NewConsulServiceReference(&ConsulService{
 				Namespace: consulNamespace,
 				Name:      consulName,
+				Peer:      consulPeer,
 			}), nil
 		}
 	}
@@ -432,7 +719,7 @@ func (r *backendResolver) findPeerService(ctx context.Context, service *apigwv1a
 	return nil, NewConsulResolutionError(fmt.Sprintf("no service %s found from peer %s", consulName, consulPeer))
 }
 
-func (r *backendResolver) findCatalogService(service *apigwv1alpha1.MeshService) (*ResolvedReference, error) {
+func (r *backendResolver) findCatalogService(service *apigwv1alpha1.MeshService, port int) (*ResolvedReference, error) {
 	consulNamespace := r.mapper(service.Namespace)
 	consulName := service.Spec.Name
 	services, _, err := r.consul.Catalog().Service(consulName, "", &api.QueryOptions{
@@ -443,7 +730,16 @@ func (r *backendResolver) findCatalogService(service *apigwv1alpha1.MeshService)
 		return nil, err
 	}
 	if len(services) == 0 {
-		return nil, NewBackendNotFoundError(fmt.Sprintf("consul service (%s, %s) not found", consulNamespace, consulName))
+		return nil, NewBackendNotFoundError(fmt.Sprintf("consul service (%s, %s) not found", consulNamespace, consulName)).WithConsulService(api.CompoundServiceName{
+			Name:      consulName,
+			Namespace: consulNamespace,
+		})
+	}
+	if port != 0 {
+		services = catalogServicesOnPort(services, port)
+		if len(services) == 0 {
+			return nil, NewPortNotFoundError(fmt.Sprintf("consul service (%s, %s) has no instances listening on port %d", consulNamespace, consulName, port))
+		}
 	}
 	resolved, err := validateCatalogConsulReference(services)
 	if err != nil {
@@ -453,6 +749,17 @@ func (r *backendResolver) findCatalogService(service *apigwv1alpha1.MeshService)
 	return resolved, nil
 }
 
+// catalogServicesOnPort returns the service instances listening on the given port
+func catalogServicesOnPort(services []*api.CatalogService, port int) []*api.CatalogService {
+	matching := []*api.CatalogService{}
+	for _, service := range services {
+		if service.ServicePort == port {
+			matching = append(matching, service)
+		}
+	}
+	return matching
+}
+
 func validateCatalogConsulReference(services []*api.CatalogService) (*ResolvedReference, error) {
 	serviceName := ""
 	serviceNamespace := ""
@@ -10,7 +10,9 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 
 	"github.com/hashicorp/consul/api"
@@ -287,10 +289,301 @@ func TestBackendResolver_consulServiceForMeshService_peer(t *testing.T) {
 		mapper: sameNamespaceMapper,
 	}
 
-	ref, err := resolver.consulServiceForMeshService(context.Background(), utils.NamespacedName(meshService))
+	ref, err := resolver.consulServiceForMeshService(context.Background(), utils.NamespacedName(meshService), 0)
 	require.NoError(t, err)
 	require.NotNil(t, ref)
 	require.NotNil(t, ref.Consul)
 	assert.Equal(t, meshService.Namespace, ref.Consul.Namespace)
 	assert.Equal(t, "imported_service", ref.Consul.Name)
 }
+
+func TestBackendResolver_consulServiceForK8SService_external(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: t.Name(),
+			Name:      "external",
+			Annotations: map[string]string{
+				AnnotationExternalService: "true",
+			},
+		},
+		Spec: core.ServiceSpec{
+			ClusterIP: "10.0.0.1",
+			Ports:     []core.ServicePort{{Port: 8080}},
+		},
+	}
+
+	gwClient := mocks.NewMockClient(ctrl)
+	gwClient.EXPECT().GetService(gomock.Any(), utils.NamespacedName(service)).Return(service, nil).Times(2)
+
+	resolver := &backendResolver{
+		client: gwClient,
+		consul: testing2.NewTestClient(nil),
+		logger: hclog.NewNullLogger(),
+		mapper: sameNamespaceMapper,
+	}
+
+	ref, err := resolver.consulServiceForK8SService(context.Background(), utils.NamespacedName(service), 8080)
+	require.NoError(t, err)
+	require.NotNil(t, ref)
+	require.NotNil(t, ref.Consul)
+	assert.Equal(t, service.Namespace, ref.Consul.Namespace)
+	assert.Equal(t, "external", ref.Consul.Name)
+	require.NotNil(t, ref.Consul.External)
+	assert.Equal(t, "10.0.0.1", ref.Consul.External.Address)
+	assert.Equal(t, 8080, ref.Consul.External.Port)
+
+	_, err = resolver.consulServiceForK8SService(context.Background(), utils.NamespacedName(service), 9090)
+	require.Error(t, err)
+	var resolutionErr ResolutionError
+	require.ErrorAs(t, err, &resolutionErr)
+	assert.Equal(t, PortNotFoundErrorType, resolutionErr.remote)
+}
+
+func TestValidateAgentConsulReference(t *testing.T) {
+	t.Parallel()
+
+	httpPort := core.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}
+	namedPort := core.ServicePort{Name: "web", Port: 80, TargetPort: intstr.FromString("web")}
+	adminPort := core.ServicePort{Name: "admin", Port: 9000, TargetPort: intstr.FromString("admin")}
+	metricsPort := core.ServicePort{Name: "metrics", Port: 9102, TargetPort: intstr.FromInt(9102)}
+
+	single := map[string]*api.AgentService{
+		"web-1": {Service: "web", Port: 8080},
+		"web-2": {Service: "web", Port: 8080},
+	}
+	multiPort := map[string]*api.AgentService{
+		"api-1":       {Service: "api", Port: 8080},
+		"api-admin-1": {Service: "api-admin", Port: 9000},
+	}
+
+	// nothing registered
+	ref, err := validateAgentConsulReference(map[string]*api.AgentService{}, httpPort, []int{8080})
+	require.NoError(t, err)
+	require.Nil(t, ref)
+
+	// a single consul service serves every port
+	for _, port := range []core.ServicePort{httpPort, adminPort, metricsPort} {
+		ref, err = validateAgentConsulReference(single, port, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "web", ref.Consul.Name)
+	}
+
+	// selected by the port the instances listen on
+	ref, err = validateAgentConsulReference(multiPort, httpPort, []int{8080})
+	require.NoError(t, err)
+	assert.Equal(t, "api", ref.Consul.Name)
+
+	// selected by the container port a named target port resolves to
+	ref, err = validateAgentConsulReference(multiPort, namedPort, []int{8080})
+	require.NoError(t, err)
+	assert.Equal(t, "api", ref.Consul.Name)
+
+	// selected by the name of the port
+	ref, err = validateAgentConsulReference(multiPort, adminPort, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "api-admin", ref.Consul.Name)
+
+	// nothing registered for the port
+	var resolutionErr ResolutionError
+	_, err = validateAgentConsulReference(multiPort, metricsPort, []int{9102})
+	require.ErrorAs(t, err, &resolutionErr)
+	assert.Equal(t, PortNotFoundErrorType, resolutionErr.remote)
+
+	// ambiguous
+	_, err = validateAgentConsulReference(map[string]*api.AgentService{
+		"web-1":   {Service: "web", Port: 8080},
+		"other-1": {Service: "other", Port: 8080},
+	}, httpPort, []int{8080})
+	require.ErrorAs(t, err, &resolutionErr)
+	assert.Equal(t, ConsulServiceResolutionErrorType, resolutionErr.remote)
+}
+
+func TestBackendResolver_targetPorts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: t.Name(),
+			Name:      "web",
+		},
+	}
+
+	gwClient := mocks.NewMockClient(ctrl)
+	resolver := &backendResolver{
+		client: gwClient,
+		logger: hclog.NewNullLogger(),
+		mapper: sameNamespaceMapper,
+	}
+
+	ports, err := resolver.targetPorts(context.Background(), service, core.ServicePort{Port: 80})
+	require.NoError(t, err)
+	assert.Equal(t, []int{80}, ports)
+
+	ports, err = resolver.targetPorts(context.Background(), service, core.ServicePort{Port: 80, TargetPort: intstr.FromInt(8080)})
+	require.NoError(t, err)
+	assert.Equal(t, []int{8080}, ports)
+
+	// named target ports resolve to the container ports in the service's endpoints
+	gwClient.EXPECT().GetEndpoints(gomock.Any(), utils.NamespacedName(service)).Return(&core.Endpoints{
+		Subsets: []core.EndpointSubset{{
+			Ports: []core.EndpointPort{{Name: "http", Port: 8080}, {Name: "admin", Port: 9000}},
+		}, {
+			Ports: []core.EndpointPort{{Name: "http", Port: 8080}},
+		}},
+	}, nil)
+	ports, err = resolver.targetPorts(context.Background(), service, core.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("web")})
+	require.NoError(t, err)
+	assert.Equal(t, []int{8080}, ports)
+
+	// no endpoints yet
+	gwClient.EXPECT().GetEndpoints(gomock.Any(), utils.NamespacedName(service)).Return(nil, nil)
+	ports, err = resolver.targetPorts(context.Background(), service, core.ServicePort{Name: "http", Port: 80, TargetPort: intstr.FromString("web")})
+	require.NoError(t, err)
+	assert.Empty(t, ports)
+}
+
+func TestBackendResolver_consulServiceForK8SService_externalName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: t.Name(),
+			Name:      "search",
+		},
+		Spec: core.ServiceSpec{
+			Type:         core.ServiceTypeExternalName,
+			ExternalName: "search.example.com",
+		},
+	}
+
+	gwClient := mocks.NewMockClient(ctrl)
+	gwClient.EXPECT().GetService(gomock.Any(), utils.NamespacedName(service)).Return(service, nil)
+
+	resolver := &backendResolver{
+		client: gwClient,
+		consul: testing2.NewTestClient(nil),
+		logger: hclog.NewNullLogger(),
+		mapper: sameNamespaceMapper,
+	}
+
+	ref, err := resolver.consulServiceForK8SService(context.Background(), utils.NamespacedName(service), 443)
+	require.NoError(t, err)
+	require.NotNil(t, ref.Consul)
+	assert.Equal(t, "search", ref.Consul.Name)
+	require.NotNil(t, ref.Consul.External)
+	assert.Equal(t, "search.example.com", ref.Consul.External.Address)
+	assert.Equal(t, 443, ref.Consul.External.Port)
+}
+
+func TestBackendResolver_consulServiceForExternalService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	externalService := &v1alpha1.ExternalService{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: t.Name(),
+			Name:      "saas",
+		},
+		Spec: v1alpha1.ExternalServiceSpec{
+			Address: "api.example.com",
+			Port:    443,
+			TLS: &v1alpha1.ExternalServiceTLSSpec{
+				CAFile: "/etc/ssl/certs/ca-certificates.crt",
+				SNI:    "api.example.com",
+			},
+		},
+	}
+
+	gwClient := mocks.NewMockClient(ctrl)
+	gwClient.EXPECT().GetExternalService(gomock.Any(), utils.NamespacedName(externalService)).Return(externalService, nil)
+
+	resolver := &backendResolver{
+		client: gwClient,
+		consul: testing2.NewTestClient(nil),
+		logger: hclog.NewNullLogger(),
+		mapper: sameNamespaceMapper,
+	}
+
+	ref, err := resolver.consulServiceForExternalService(context.Background(), utils.NamespacedName(externalService), 0)
+	require.NoError(t, err)
+	require.NotNil(t, ref.Consul)
+	assert.Equal(t, "saas", ref.Consul.Name)
+	assert.Equal(t, t.Name(), ref.Consul.Namespace)
+	require.NotNil(t, ref.Consul.External)
+	assert.Equal(t, "api.example.com", ref.Consul.External.Address)
+	assert.Equal(t, 443, ref.Consul.External.Port)
+	require.NotNil(t, ref.Consul.External.TLS)
+	assert.Equal(t, "/etc/ssl/certs/ca-certificates.crt", ref.Consul.External.TLS.CAFile)
+	assert.Equal(t, "api.example.com", ref.Consul.External.TLS.SNI)
+
+	// backend references may only target the port the service listens on
+	gwClient.EXPECT().GetExternalService(gomock.Any(), utils.NamespacedName(externalService)).Return(externalService, nil).Times(2)
+	ref, err = resolver.consulServiceForExternalService(context.Background(), utils.NamespacedName(externalService), 443)
+	require.NoError(t, err)
+	assert.Equal(t, 443, ref.Consul.External.Port)
+	_, err = resolver.consulServiceForExternalService(context.Background(), utils.NamespacedName(externalService), 8443)
+	var resolutionErr ResolutionError
+	require.ErrorAs(t, err, &resolutionErr)
+	assert.Equal(t, PortNotFoundErrorType, resolutionErr.remote)
+
+	gwClient.EXPECT().GetExternalService(gomock.Any(), utils.NamespacedName(externalService)).Return(nil, nil)
+	_, err = resolver.consulServiceForExternalService(context.Background(), utils.NamespacedName(externalService), 0)
+	require.ErrorAs(t, err, &resolutionErr)
+	assert.Equal(t, BackendNotFoundErrorType, resolutionErr.remote)
+}
+
+func TestBackendResolver_externalServiceOwners(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// an ExternalService and an ExternalName Service in different namespaces that
+	// both map to the same Consul service when namespaces aren't mirrored
+	externalService := &v1alpha1.ExternalService{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "team-a",
+			Name:      "search-saas",
+		},
+		Spec: v1alpha1.ExternalServiceSpec{
+			Name:    "search",
+			Address: "search.saas.example.com",
+			Port:    443,
+		},
+	}
+	externalName := &core.Service{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "team-b",
+			Name:      "search",
+		},
+		Spec: core.ServiceSpec{
+			Type:         core.ServiceTypeExternalName,
+			ExternalName: "search.example.com",
+		},
+	}
+
+	gwClient := mocks.NewMockClient(ctrl)
+	gwClient.EXPECT().GetExternalService(gomock.Any(), utils.NamespacedName(externalService)).Return(externalService, nil)
+	gwClient.EXPECT().GetService(gomock.Any(), utils.NamespacedName(externalName)).Return(externalName, nil)
+
+	resolver := &backendResolver{
+		client: gwClient,
+		consul: testing2.NewTestClient(nil),
+		logger: hclog.NewNullLogger(),
+		mapper: func(string) string { return "" },
+	}
+
+	fromExternalService, err := resolver.consulServiceForExternalService(context.Background(), utils.NamespacedName(externalService), 0)
+	require.NoError(t, err)
+	fromExternalName, err := resolver.consulServiceForK8SService(context.Background(), utils.NamespacedName(externalName), 443)
+	require.NoError(t, err)
+
+	assert.Equal(t, fromExternalService.Consul.Name, fromExternalName.Consul.Name)
+	assert.Equal(t, fromExternalService.Consul.Namespace, fromExternalName.Consul.Namespace)
+	// their registrations are told apart by the objects they're registered for
+	assert.Equal(t, "ExternalService/team-a/search-saas", fromExternalService.Consul.External.Owner)
+	assert.Equal(t, "Service/team-b/search", fromExternalName.Consul.External.Owner)
+}
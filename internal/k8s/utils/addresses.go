@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"fmt"
+	"net"
+
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// ExternalDNSHostnameAnnotation is the annotation external-dns uses to create
+// DNS records pointing at a service
+const ExternalDNSHostnameAnnotation = "external-dns.alpha.kubernetes.io/hostname"
+
+// RequestedAddresses returns the IP addresses and hostnames requested in a gateway's
+// spec.addresses, addresses without a type are IP addresses
+func RequestedAddresses(gw *gwv1beta1.Gateway) (ips []string, hostnames []string) {
+	for _, address := range gw.Spec.Addresses {
+		addressType := gwv1beta1.IPAddressType
+		if address.Type != nil {
+			addressType = *address.Type
+		}
+		switch addressType {
+		case gwv1beta1.IPAddressType:
+			ips = append(ips, address.Value)
+		case gwv1beta1.HostnameAddressType:
+			hostnames = append(hostnames, address.Value)
+		}
+	}
+	return ips, hostnames
+}
+
+// AllowedAddresses filters the addresses requested in a gateway's spec.addresses down to
+// the valid IP addresses within the allowed CIDRs and, if allowHostnames is set, hostnames.
+// An error describing the first requested address that was dropped is also returned.
+func AllowedAddresses(gw *gwv1beta1.Gateway, allowedRanges []string, allowHostnames bool) (ips []string, hostnames []string, err error) {
+	var ranges []*net.IPNet
+	for _, cidr := range allowedRanges {
+		if _, network, parseErr := net.ParseCIDR(cidr); parseErr == nil {
+			ranges = append(ranges, network)
+		}
+	}
+
+	requestedIPs, requestedHostnames := RequestedAddresses(gw)
+	for _, address := range requestedIPs {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			if err == nil {
+				err = fmt.Errorf("requested address %q is not a valid IP address", address)
+			}
+			continue
+		}
+		if !ipInRanges(ip, ranges) {
+			if err == nil {
+				err = fmt.Errorf("requested address %s is not allowed by the gateway class", address)
+			}
+			continue
+		}
+		ips = append(ips, address)
+	}
+	for _, hostname := range requestedHostnames {
+		if !allowHostnames {
+			if err == nil {
+				err = fmt.Errorf("requested hostname %s is not allowed by the gateway class", hostname)
+			}
+			continue
+		}
+		hostnames = append(hostnames, hostname)
+	}
+	return ips, hostnames, err
+}
+
+func ipInRanges(ip net.IP, ranges []*net.IPNet) bool {
+	for _, network := range ranges {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestRequestedAddresses(t *testing.T) {
+	t.Parallel()
+
+	hostname := gwv1beta1.HostnameAddressType
+	named := gwv1beta1.NamedAddressType
+	ipAddress := gwv1beta1.IPAddressType
+
+	ips, hostnames := RequestedAddresses(&gwv1beta1.Gateway{
+		Spec: gwv1beta1.GatewaySpec{
+			Addresses: []gwv1beta1.GatewayAddress{
+				{Value: "10.0.0.1"},
+				{Type: &ipAddress, Value: "10.0.0.2"},
+				{Type: &hostname, Value: "gateway.example.com"},
+				{Type: &named, Value: "reserved"},
+			},
+		},
+	})
+	require.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, ips)
+	require.Equal(t, []string{"gateway.example.com"}, hostnames)
+
+	ips, hostnames = RequestedAddresses(&gwv1beta1.Gateway{})
+	require.Empty(t, ips)
+	require.Empty(t, hostnames)
+}
+
+func TestAllowedAddresses(t *testing.T) {
+	t.Parallel()
+
+	hostname := gwv1beta1.HostnameAddressType
+	gateway := &gwv1beta1.Gateway{
+		Spec: gwv1beta1.GatewaySpec{
+			Addresses: []gwv1beta1.GatewayAddress{
+				{Value: "10.0.0.1"},
+				{Value: "192.168.0.1"},
+				{Value: "not-an-ip"},
+				{Type: &hostname, Value: "gateway.example.com"},
+			},
+		},
+	}
+
+	ips, hostnames, err := AllowedAddresses(gateway, nil, false)
+	require.Empty(t, ips)
+	require.Empty(t, hostnames)
+	require.EqualError(t, err, "requested address 10.0.0.1 is not allowed by the gateway class")
+
+	ips, hostnames, err = AllowedAddresses(gateway, []string{"10.0.0.0/8", "invalid"}, true)
+	require.Equal(t, []string{"10.0.0.1"}, ips)
+	require.Equal(t, []string{"gateway.example.com"}, hostnames)
+	require.EqualError(t, err, "requested address 192.168.0.1 is not allowed by the gateway class")
+
+	ips, hostnames, err = AllowedAddresses(gateway, []string{"0.0.0.0/0"}, false)
+	require.Equal(t, []string{"10.0.0.1", "192.168.0.1"}, ips)
+	require.Empty(t, hostnames)
+	require.EqualError(t, err, `requested address "not-an-ip" is not a valid IP address`)
+
+	ips, hostnames, err = AllowedAddresses(&gwv1beta1.Gateway{}, nil, false)
+	require.Empty(t, ips)
+	require.Empty(t, hostnames)
+	require.NoError(t, err)
+}
@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// cert-manager resources are handled as unstructured objects so that we
+// don't need to depend on cert-manager's API module
+var (
+	CertManagerCertificateGVK     = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+	CertManagerCertificateListGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "CertificateList"}
+)
+
+// Listener TLS options naming the cert-manager issuer used to issue a certificate
+// for the listener, these mirror the annotations used by cert-manager's ingress-shim
+const (
+	CertManagerIssuerOption        = "cert-manager.io/issuer"
+	CertManagerClusterIssuerOption = "cert-manager.io/cluster-issuer"
+	CertManagerIssuerKindOption    = "cert-manager.io/issuer-kind"
+	CertManagerIssuerGroupOption   = "cert-manager.io/issuer-group"
+
+	// certManagerCertificateNameAnnotation is set by cert-manager on the
+	// secrets it issues into, naming the Certificate they were issued for
+	certManagerCertificateNameAnnotation = "cert-manager.io/certificate-name"
+
+	certManagerIssuerKind        = "Issuer"
+	certManagerClusterIssuerKind = "ClusterIssuer"
+)
+
+// CertManagerIssuer references the cert-manager issuer for a listener's certificate
+type CertManagerIssuer struct {
+	Name  string
+	Kind  string
+	Group string
+}
+
+// CertManagerIssuerFor returns the cert-manager issuer set in a listener's TLS
+// options, or false if the listener's certificate isn't issued by cert-manager.
+func CertManagerIssuerFor(listener gwv1beta1.Listener) (CertManagerIssuer, bool) {
+	if listener.TLS == nil || listener.TLS.Options == nil {
+		return CertManagerIssuer{}, false
+	}
+	options := listener.TLS.Options
+
+	issuer := CertManagerIssuer{
+		Kind:  string(options[CertManagerIssuerKindOption]),
+		Group: string(options[CertManagerIssuerGroupOption]),
+	}
+	switch {
+	case options[CertManagerClusterIssuerOption] != "":
+		issuer.Name = string(options[CertManagerClusterIssuerOption])
+		issuer.Kind = certManagerClusterIssuerKind
+	case options[CertManagerIssuerOption] != "":
+		issuer.Name = string(options[CertManagerIssuerOption])
+		if issuer.Kind == "" {
+			issuer.Kind = certManagerIssuerKind
+		}
+	default:
+		return CertManagerIssuer{}, false
+	}
+	return issuer, true
+}
+
+// CertManagerCertificateName returns the name of the cert-manager
+// Certificate created for a Gateway's listener
+func CertManagerCertificateName(gateway *gwv1beta1.Gateway, listener gwv1beta1.Listener) string {
+	return fmt.Sprintf("%s-%s", gateway.Name, listener.Name)
+}
+
+// IsSecretInNamespace checks whether a certificate reference is a Secret in the given namespace,
+// cert-manager Certificates can only be created for these since they must be owned by the Gateway
+func IsSecretInNamespace(ref gwv1beta1.SecretObjectReference, namespace string) bool {
+	if ref.Group != nil && *ref.Group != "" {
+		return false
+	}
+	if ref.Kind != nil && *ref.Kind != "Secret" {
+		return false
+	}
+	return ref.Namespace == nil || string(*ref.Namespace) == namespace
+}
+
+// IsCertManagerSecretFor checks whether cert-manager issued a secret for the named Certificate,
+// existing secrets are never handed to a Certificate since cert-manager would overwrite them
+func IsCertManagerSecretFor(secret *corev1.Secret, certificateName string) bool {
+	return secret.Annotations[certManagerCertificateNameAnnotation] == certificateName && secret.Type == corev1.SecretTypeTLS
+}
+
+// NewCertManagerCertificate returns a cert-manager Certificate that issues a
+// certificate for the given hostnames into the given secret.
+func NewCertManagerCertificate(namespace, name, secretName string, hostnames []string, issuer CertManagerIssuer) *unstructured.Unstructured {
+	dnsNames := make([]interface{}, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		dnsNames = append(dnsNames, hostname)
+	}
+	issuerRef := map[string]interface{}{
+		"name": issuer.Name,
+		"kind": issuer.Kind,
+	}
+	if issuer.Group != "" {
+		issuerRef["group"] = issuer.Group
+	}
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(CertManagerCertificateGVK)
+	certificate.SetNamespace(namespace)
+	certificate.SetName(name)
+	certificate.Object["spec"] = map[string]interface{}{
+		"secretName": secretName,
+		"dnsNames":   dnsNames,
+		"issuerRef":  issuerRef,
+	}
+	return certificate
+}
+
+// CertManagerCertificateReady returns whether a cert-manager Certificate's Ready
+// condition is true along with a description of why it isn't.
+func CertManagerCertificateReady(certificate *unstructured.Unstructured) (bool, string) {
+	conditions, _, _ := unstructured.NestedSlice(certificate.Object, "status", "conditions")
+	for _, raw := range conditions {
+		condition, ok := raw.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		if condition["status"] == "True" {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%v: %v", condition["reason"], condition["message"])
+	}
+	return false, "certificate has not been issued yet"
+}
@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestCertManagerIssuerFor(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		options  map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue
+		expected CertManagerIssuer
+		found    bool
+	}{{
+		name: "no options",
+	}, {
+		name: "unrelated options",
+		options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			"api-gateway.consul.hashicorp.com/tls_min_version": "TLSv1_2",
+		},
+	}, {
+		name: "issuer",
+		options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			CertManagerIssuerOption: "issuer",
+		},
+		expected: CertManagerIssuer{Name: "issuer", Kind: "Issuer"},
+		found:    true,
+	}, {
+		name: "external issuer",
+		options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			CertManagerIssuerOption:      "issuer",
+			CertManagerIssuerKindOption:  "AWSPCAIssuer",
+			CertManagerIssuerGroupOption: "awspca.cert-manager.io",
+		},
+		expected: CertManagerIssuer{Name: "issuer", Kind: "AWSPCAIssuer", Group: "awspca.cert-manager.io"},
+		found:    true,
+	}, {
+		name: "cluster issuer",
+		options: map[gwv1beta1.AnnotationKey]gwv1beta1.AnnotationValue{
+			CertManagerClusterIssuerOption: "cluster-issuer",
+			CertManagerIssuerKindOption:    "Issuer",
+		},
+		expected: CertManagerIssuer{Name: "cluster-issuer", Kind: "ClusterIssuer"},
+		found:    true,
+	}} {
+		t.Run(test.name, func(t *testing.T) {
+			issuer, found := CertManagerIssuerFor(gwv1beta1.Listener{
+				TLS: &gwv1beta1.GatewayTLSConfig{Options: test.options},
+			})
+			require.Equal(t, test.found, found)
+			require.Equal(t, test.expected, issuer)
+		})
+	}
+}
+
+func TestCertManagerCertificateReady(t *testing.T) {
+	t.Parallel()
+
+	certificate := NewCertManagerCertificate("namespace", "name", "secret", []string{"example.com"}, CertManagerIssuer{Name: "issuer", Kind: "Issuer"})
+	secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+	require.Equal(t, "secret", secretName)
+
+	ready, reason := CertManagerCertificateReady(certificate)
+	require.False(t, ready)
+	require.Equal(t, "certificate has not been issued yet", reason)
+
+	certificate.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{
+			"type":    "Ready",
+			"status":  "False",
+			"reason":  "Failed",
+			"message": "issuer not found",
+		}},
+	}
+	ready, reason = CertManagerCertificateReady(certificate)
+	require.False(t, ready)
+	require.Equal(t, "Failed: issuer not found", reason)
+
+	certificate.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{
+			"type":   "Ready",
+			"status": "True",
+		}},
+	}
+	ready, _ = CertManagerCertificateReady(certificate)
+	require.True(t, ready)
+}
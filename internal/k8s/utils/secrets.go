@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 var (
@@ -17,8 +19,19 @@ var (
 
 const (
 	K8sSecretScheme = "k8s"
+
+	// ACMEManagedByLabel marks the secrets that ACME account keys and issued certificates
+	// are stored in, existing secrets without it are never overwritten
+	ACMEManagedByLabel = "api-gateway.consul.hashicorp.com/managed-by"
+	ACMEManagedByValue = "acme"
 )
 
+// IsACMEManagedSecret checks whether a secret was created to store
+// ACME account keys or issued certificates of the given secret type
+func IsACMEManagedSecret(secret *corev1.Secret, secretType corev1.SecretType) bool {
+	return secret.Labels[ACMEManagedByLabel] == ACMEManagedByValue && secret.Type == secretType
+}
+
 // K8sSecret is a wrapper to a Kubernetes certificate secret
 type K8sSecret struct {
 	Namespace string
@@ -6,19 +6,30 @@ package metrics
 import (
 	"github.com/armon/go-metrics"
 	"github.com/armon/go-metrics/prometheus"
+	prom "github.com/prometheus/client_golang/prometheus"
 )
 
 var (
 	SDSActiveStreams             = []string{"sds_active_streams"}
 	SDSCachedResources           = []string{"sds_cached_resources"}
 	SDSCertificateFetches        = []string{"sds_certificate_fetches"}
+	SDSDeniedConnections         = []string{"sds_denied_connections"}
 	K8sGateways                  = []string{"k8s_gateways"}
 	K8sNewGatewayDeployments     = []string{"k8s_new_gateway_deployments"}
 	ConsulLeafCertificateFetches = []string{"consul_leaf_certificate_fetches"}
+
+	SDSCertificateRotationLatency = []string{"sds_certificate_rotation_latency"}
 )
 
 var Registry metrics.MetricSink
 
+// ListenerCertificateExpiry is registered directly with prometheus rather than
+// through the sink so that the series of deleted gateways and listeners can be removed
+var ListenerCertificateExpiry = prom.NewGaugeVec(prom.GaugeOpts{
+	Name: "k8s_listener_certificate_expiry",
+	Help: "The expiration time of a gateway listener's certificate in seconds since the Unix epoch",
+}, []string{"namespace", "gateway", "listener", "certificate"})
+
 func init() {
 	sink, err := prometheus.NewPrometheusSinkFrom(prometheus.PrometheusOpts{
 		GaugeDefinitions: []prometheus.GaugeDefinition{{
@@ -34,6 +45,9 @@ func init() {
 		CounterDefinitions: []prometheus.CounterDefinition{{
 			Name: SDSCertificateFetches,
 			Help: "The total number of fetches per certificate segmented by fetcher",
+		}, {
+			Name: SDSDeniedConnections,
+			Help: "The total number of SDS streams rejected during client authentication segmented by reason",
 		}, {
 			Name: K8sNewGatewayDeployments,
 			Help: "The number of gateways the kubernetes controller has deployed",
@@ -41,9 +55,15 @@ func init() {
 			Name: ConsulLeafCertificateFetches,
 			Help: "The number of times a leaf certificate has been fetched from Consul",
 		}},
+		SummaryDefinitions: []prometheus.SummaryDefinition{{
+			Name: SDSCertificateRotationLatency,
+			Help: "The time in milliseconds between a certificate changing and the change being pushed to the certificate cache",
+		}},
 	})
 	if err != nil {
 		panic(err)
 	}
 	Registry = sink
+
+	prom.MustRegister(ListenerCertificateExpiry)
 }
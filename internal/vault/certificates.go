@@ -52,25 +52,21 @@ type KVClient interface {
 // implementation, k8s.K8sSecretClient.
 type KVSecretClient struct {
 	logger hclog.Logger
-	client KVClient
-}
-
-// NewKVSecretClient relies on having standard VAULT_x envars set
-// such as VAULT_TOKEN, VAULT_ADDR, etc. In the future, we may need
-// to construct the config externally to allow for custom flags, etc.
-func NewKVSecretClient(logger hclog.Logger, kvPath string) (*KVSecretClient, error) {
-	client, err := api.NewClient(api.DefaultConfig())
-	if err != nil {
-		return nil, err
-	}
+	// kv returns a client for the KV engine mounted at the given path
+	kv func(mount string) KVClient
 
-	// Ensure no leading or trailing / for path interpolation later
-	kvPath = strings.Trim(kvPath, "/")
+	kvPath string
+}
 
+// NewKVSecretClient creates a KVSecretClient that fetches certificates from
+// the KV v2 engine mounted at kvPath unless a secret specifies its own mount.
+func NewKVSecretClient(logger hclog.Logger, client *Client, kvPath string) *KVSecretClient {
 	return &KVSecretClient{
 		logger: logger,
-		client: client.KVv2(kvPath),
-	}, nil
+		kv:     client.KVv2,
+		// Ensure no leading or trailing / for path interpolation later
+		kvPath: strings.Trim(kvPath, "/"),
+	}
 }
 
 // FetchSecret accepts an opaque string containing necessary values for retrieving
@@ -88,9 +84,13 @@ func (c *KVSecretClient) FetchSecret(ctx context.Context, name string) (*tls.Sec
 		{Name: "name", Value: name}})
 
 	path := strings.TrimPrefix(secret.Path, "/")
+	mount := c.kvPath
+	if secret.Mount != "" {
+		mount = strings.Trim(secret.Mount, "/")
+	}
 
 	// Fetch
-	result, err := c.client.Get(ctx, path)
+	result, err := c.kv(mount).Get(ctx, path)
 	if err != nil {
 		return nil, time.Time{}, err
 	}
@@ -160,25 +160,18 @@ type PKISecretClient struct {
 	issue   string
 }
 
-// NewPKISecretClient relies on having standard VAULT_x envars set
-// such as VAULT_TOKEN, VAULT_ADDR, etc. In the future, we may need
-// to construct the config externally to allow for custom flags, etc.
-func NewPKISecretClient(logger hclog.Logger, pkiPath, issue string) (*PKISecretClient, error) {
-	client, err := api.NewClient(api.DefaultConfig())
-	if err != nil {
-		return nil, err
-	}
-
-	// Ensure no leading or trailing / for path interpolation later
-	pkiPath = strings.Trim(pkiPath, "/")
-
+// NewPKISecretClient creates a PKISecretClient that issues certificates with
+// the given role from the PKI engine mounted at pkiPath unless a secret
+// specifies its own mount or role.
+func NewPKISecretClient(logger hclog.Logger, client *Client, pkiPath, issue string) *PKISecretClient {
 	return &PKISecretClient{
-		logger:  logger,
-		client:  client.Logical(),
-		pkiPath: pkiPath,
+		logger: logger,
+		client: client.Logical(),
+		// Ensure no leading or trailing / for path interpolation later
+		pkiPath: strings.Trim(pkiPath, "/"),
 		issuer:  defaultIssuer,
 		issue:   issue,
-	}, nil
+	}
 }
 
 // FetchSecret accepts an opaque string containing necessary values for generating a
@@ -234,7 +227,18 @@ func (c *PKISecretClient) generateCertBundle(ctx context.Context, name string) (
 		{Name: "name", Value: name}})
 
 	// Generate certificate + key using Vault API
-	path := fmt.Sprintf("/%s/issuer/%s/issue/%s", c.pkiPath, c.issuer, c.issue)
+	issue := c.issue
+	if secret.Role != "" {
+		issue = secret.Role
+	}
+	if issue == "" {
+		return nil, errors.New("no Vault PKI role configured for certificate")
+	}
+	pkiPath := c.pkiPath
+	if secret.Mount != "" {
+		pkiPath = strings.Trim(secret.Mount, "/")
+	}
+	path := fmt.Sprintf("/%s/issuer/%s/issue/%s", pkiPath, c.issuer, issue)
 
 	body := make(map[string]interface{})
 	if err = mapstructure.Decode(
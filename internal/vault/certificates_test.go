@@ -30,8 +30,10 @@ import (
 )
 
 func TestNewPKISecretClient(t *testing.T) {
-	client, err := NewPKISecretClient(hclog.NewNullLogger(), "/pki/", t.Name())
+	vaultClient, err := NewClient(hclog.NewNullLogger(), Config{})
 	require.NoError(t, err)
+
+	client := NewPKISecretClient(hclog.NewNullLogger(), vaultClient, "/pki/", t.Name())
 	assert.NotNil(t, client.client)
 	assert.Equal(t, "pki", client.pkiPath)
 	assert.Equal(t, defaultIssuer, client.issuer)
@@ -42,9 +44,11 @@ func TestPKISecretClient_FetchSecret(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	client, err := NewPKISecretClient(hclog.NewNullLogger(), "pki", t.Name())
+	vaultClient, err := NewClient(hclog.NewNullLogger(), Config{})
 	require.NoError(t, err)
 
+	client := NewPKISecretClient(hclog.NewNullLogger(), vaultClient, "pki", t.Name())
+
 	ttl := 12 * time.Hour
 	vaultSecret := NewPKISecret("example.com", "", "", "", ttl.String())
 
@@ -140,3 +144,40 @@ func generateCertAndKey(t *testing.T, commonName string, ttl time.Duration) ([]b
 
 	return caPEM.Bytes(), caPrivKeyPEM.Bytes()
 }
+
+func TestPKISecretClient_FetchSecretOverrides(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	vaultClient, err := NewClient(hclog.NewNullLogger(), Config{})
+	require.NoError(t, err)
+
+	client := NewPKISecretClient(hclog.NewNullLogger(), vaultClient, "pki", "")
+
+	// no role configured globally or on the secret
+	vaultSecret := NewPKISecret("example.com", "", "", "", "12h")
+	_, _, err = client.FetchSecret(context.TODO(), vaultSecret.String())
+	require.Error(t, err)
+
+	cert, key := generateCertAndKey(t, "example.com", 12*time.Hour)
+
+	vault := mocks.NewMockLogicalClient(ctrl)
+	vault.EXPECT().
+		WriteWithContext(context.TODO(), fmt.Sprintf("/pki-int/issuer/%s/issue/web", defaultIssuer), gomock.Any()).
+		Return(&api.Secret{
+			Data: map[string]interface{}{
+				"ca_chain":         []string{string(cert)},
+				"certificate":      string(cert),
+				"issuing_ca":       string(cert),
+				"private_key":      string(key),
+				"private_key_type": certutil.RSAPrivateKey,
+				"serial_number":    "2022",
+			},
+		}, nil)
+	client.client = vault
+
+	vaultSecret.Mount = "/pki-int/"
+	vaultSecret.Role = "web"
+	_, _, err = client.FetchSecret(context.TODO(), vaultSecret.String())
+	require.NoError(t, err)
+}
@@ -0,0 +1,338 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	// AuthMethodToken uses the token from the standard VAULT_TOKEN environment
+	// variable or token helper.
+	AuthMethodToken = "token"
+	// AuthMethodKubernetes logs in with the controller's service account token.
+	AuthMethodKubernetes = "kubernetes"
+	// AuthMethodAppRole logs in with an AppRole role id and secret id.
+	AuthMethodAppRole = "approle"
+
+	DefaultKubernetesTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	loginRetryInterval = 5 * time.Second
+	// the shortest time we wait before logging in again with a token that
+	// can't be renewed, so that short-lived tokens don't cause a login loop
+	minLoginInterval = 10 * time.Second
+)
+
+// Config configures the Vault client used by the Vault secret clients. Any
+// unset connection values fall back to the standard VAULT_x environment variables.
+type Config struct {
+	Address       string
+	CACert        string
+	ClientCert    string
+	ClientKey     string
+	TLSServerName string
+	TLSSkipVerify bool
+	Namespace     string
+
+	// AuthMethod is one of "token", "kubernetes" or "approle", defaulting to "token"
+	AuthMethod string
+	// AuthMount is the path the auth method is mounted at, defaulting to the method name
+	AuthMount string
+
+	KubernetesRole      string
+	KubernetesTokenPath string
+
+	AppRoleID           string
+	AppRoleSecretIDPath string
+}
+
+// Client wraps a Vault API client, logging in with the configured auth method
+// and re-authenticating whenever its token expires or is revoked.
+type Client struct {
+	logger hclog.Logger
+	client *api.Client
+	config Config
+
+	// serializes logins so that concurrent fetches
+	// failing with the same token only log in once
+	mutex sync.Mutex
+}
+
+// NewClient creates a Client from the given configuration.
+func NewClient(logger hclog.Logger, config Config) (*Client, error) {
+	apiConfig := api.DefaultConfig()
+	if apiConfig.Error != nil {
+		return nil, apiConfig.Error
+	}
+	if config.Address != "" {
+		apiConfig.Address = config.Address
+	}
+	if config.CACert != "" || config.ClientCert != "" || config.ClientKey != "" || config.TLSServerName != "" || config.TLSSkipVerify {
+		if err := apiConfig.ConfigureTLS(&api.TLSConfig{
+			CACert:        config.CACert,
+			ClientCert:    config.ClientCert,
+			ClientKey:     config.ClientKey,
+			TLSServerName: config.TLSServerName,
+			Insecure:      config.TLSSkipVerify,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := api.NewClient(apiConfig)
+	if err != nil {
+		return nil, err
+	}
+	if config.Namespace != "" {
+		client.SetNamespace(config.Namespace)
+	}
+
+	switch config.AuthMethod {
+	case "", AuthMethodToken:
+		config.AuthMethod = AuthMethodToken
+	case AuthMethodKubernetes:
+		if config.KubernetesRole == "" {
+			return nil, errors.New("a role must be specified for Vault kubernetes auth")
+		}
+		if config.KubernetesTokenPath == "" {
+			config.KubernetesTokenPath = DefaultKubernetesTokenPath
+		}
+		// don't use any ambient token, we always log in
+		client.ClearToken()
+	case AuthMethodAppRole:
+		if config.AppRoleID == "" || config.AppRoleSecretIDPath == "" {
+			return nil, errors.New("a role id and secret id file must be specified for Vault approle auth")
+		}
+		client.ClearToken()
+	default:
+		return nil, fmt.Errorf("unsupported Vault auth method %q", config.AuthMethod)
+	}
+	if config.AuthMount == "" {
+		config.AuthMount = config.AuthMethod
+	}
+	config.AuthMount = strings.Trim(config.AuthMount, "/")
+
+	return &Client{
+		logger: logger,
+		client: client,
+		config: config,
+	}, nil
+}
+
+// Logical returns a LogicalClient that re-authenticates on token expiry.
+func (c *Client) Logical() LogicalClient {
+	return &logicalClient{client: c}
+}
+
+// KVv2 returns a KVClient for the KV v2 engine mounted at the given path
+// that re-authenticates on token expiry.
+func (c *Client) KVv2(mount string) KVClient {
+	return &kvClient{client: c, mount: strings.Trim(mount, "/")}
+}
+
+// Manage logs in with the configured auth method and keeps the resulting
+// token renewed, logging in again whenever it can no longer be renewed.
+// It blocks until the given context is canceled.
+func (c *Client) Manage(ctx context.Context) error {
+	if c.config.AuthMethod == AuthMethodToken {
+		// tokens are managed externally
+		return nil
+	}
+
+	for {
+		secret, err := c.reauthenticate(ctx, c.client.Token())
+		if err != nil {
+			c.logger.Error("error logging in to Vault", "error", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(loginRetryInterval):
+				continue
+			}
+		}
+		if secret == nil {
+			// a fetch logged in concurrently, log in again so that we
+			// have a login response to renew
+			continue
+		}
+		if err := c.renew(ctx, secret); err != nil {
+			c.logger.Warn("Vault token can no longer be renewed, logging in again", "error", err)
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// renew keeps the token in the given login response renewed until either the
+// context is canceled or the token reaches its max TTL.
+func (c *Client) renew(ctx context.Context, secret *api.Secret) error {
+	if secret.Auth.LeaseDuration == 0 {
+		// the token never expires
+		<-ctx.Done()
+		return nil
+	}
+	if !secret.Auth.Renewable {
+		// wait out most of the token's lifetime and then log in again
+		wait := time.Duration(secret.Auth.LeaseDuration) * time.Second * 2 / 3
+		if wait < minLoginInterval {
+			wait = minLoginInterval
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(wait):
+		}
+		return nil
+	}
+
+	watcher, err := c.client.NewLifetimeWatcher(&api.LifetimeWatcherInput{Secret: secret})
+	if err != nil {
+		return err
+	}
+	go watcher.Start()
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-watcher.DoneCh():
+			return err
+		case <-watcher.RenewCh():
+			c.logger.Trace("renewed Vault token")
+		}
+	}
+}
+
+// reauthenticate logs in again unless another caller has already replaced
+// the given stale token, returning the login response if it logged in.
+func (c *Client) reauthenticate(ctx context.Context, stale string) (*api.Secret, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if token := c.client.Token(); token != "" && token != stale {
+		return nil, nil
+	}
+
+	secret, err := c.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
+
+func (c *Client) login(ctx context.Context) (*api.Secret, error) {
+	var data map[string]interface{}
+	switch c.config.AuthMethod {
+	case AuthMethodKubernetes:
+		jwt, err := os.ReadFile(c.config.KubernetesTokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading service account token: %w", err)
+		}
+		data = map[string]interface{}{
+			"role": c.config.KubernetesRole,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		}
+	case AuthMethodAppRole:
+		secretID, err := os.ReadFile(c.config.AppRoleSecretIDPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading approle secret id: %w", err)
+		}
+		data = map[string]interface{}{
+			"role_id":   c.config.AppRoleID,
+			"secret_id": strings.TrimSpace(string(secretID)),
+		}
+	default:
+		return nil, fmt.Errorf("cannot log in with Vault auth method %q", c.config.AuthMethod)
+	}
+
+	// log in without sending any existing, possibly invalid, token
+	client, err := c.client.CloneWithHeaders()
+	if err != nil {
+		return nil, err
+	}
+	client.ClearToken()
+
+	secret, err := client.Logical().WriteWithContext(ctx, fmt.Sprintf("auth/%s/login", c.config.AuthMount), data)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return nil, errors.New("no token returned from Vault login")
+	}
+	c.logger.Debug("logged in to Vault", "method", c.config.AuthMethod, "ttl", secret.Auth.LeaseDuration)
+	return secret, nil
+}
+
+// do calls the given function, logging in first if we have no token yet and
+// logging in and retrying once if Vault rejects the current token.
+func (c *Client) do(ctx context.Context, fn func() error) error {
+	if c.config.AuthMethod == AuthMethodToken {
+		return fn()
+	}
+
+	token := c.client.Token()
+	if token == "" {
+		if _, err := c.reauthenticate(ctx, token); err != nil {
+			return err
+		}
+		token = c.client.Token()
+	}
+
+	err := fn()
+	if !isPermissionDenied(err) {
+		return err
+	}
+
+	c.logger.Debug("Vault token rejected, logging in again")
+	if _, err := c.reauthenticate(ctx, token); err != nil {
+		return err
+	}
+	return fn()
+}
+
+func isPermissionDenied(err error) bool {
+	var responseErr *api.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusForbidden
+}
+
+type logicalClient struct {
+	client *Client
+}
+
+func (l *logicalClient) WriteWithContext(ctx context.Context, path string, data map[string]interface{}) (*api.Secret, error) {
+	var secret *api.Secret
+	err := l.client.do(ctx, func() error {
+		var err error
+		secret, err = l.client.client.Logical().WriteWithContext(ctx, path, data)
+		return err
+	})
+	return secret, err
+}
+
+type kvClient struct {
+	client *Client
+	mount  string
+}
+
+func (k *kvClient) Get(ctx context.Context, path string) (*api.KVSecret, error) {
+	var secret *api.KVSecret
+	err := k.client.do(ctx, func() error {
+		var err error
+		secret, err = k.client.client.KVv2(k.mount).Get(ctx, path)
+		return err
+	})
+	return secret, err
+}
@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+type fakeVault struct {
+	mutex  sync.Mutex
+	logins int
+	token  string
+	login  map[string]interface{}
+	// issue tokens without a TTL
+	neverExpires bool
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	switch r.URL.Path {
+	case "/v1/auth/k8s/login", "/v1/auth/approle/login":
+		f.login = map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&f.login)
+		f.logins++
+		f.token = fmt.Sprintf("token-%d", f.logins)
+		leaseDuration := 60
+		if f.neverExpires {
+			leaseDuration = 0
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{
+				"client_token":   f.token,
+				"lease_duration": leaseDuration,
+			},
+		})
+	case "/v1/pki/issue/web":
+		if r.Header.Get("X-Vault-Token") != f.token {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"certificate": "cert"},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeVault) revoke() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.token = "revoked"
+}
+
+func TestClient(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+
+	directory := t.TempDir()
+	tokenPath := filepath.Join(directory, "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("jwt\n"), 0600))
+
+	t.Run("kubernetes", func(t *testing.T) {
+		vault := &fakeVault{}
+		server := httptest.NewServer(vault)
+		defer server.Close()
+
+		client, err := NewClient(hclog.NewNullLogger(), Config{
+			Address:             server.URL,
+			AuthMethod:          AuthMethodKubernetes,
+			AuthMount:           "/k8s/",
+			KubernetesRole:      "gateway",
+			KubernetesTokenPath: tokenPath,
+		})
+		require.NoError(t, err)
+
+		// logs in lazily on the first request
+		secret, err := client.Logical().WriteWithContext(context.Background(), "pki/issue/web", nil)
+		require.NoError(t, err)
+		require.Equal(t, "cert", secret.Data["certificate"])
+		require.Equal(t, 1, vault.logins)
+		require.Equal(t, map[string]interface{}{"role": "gateway", "jwt": "jwt"}, vault.login)
+
+		// logs in again once the token is no longer valid
+		vault.revoke()
+		_, err = client.Logical().WriteWithContext(context.Background(), "pki/issue/web", nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, vault.logins)
+	})
+
+	t.Run("approle", func(t *testing.T) {
+		vault := &fakeVault{}
+		server := httptest.NewServer(vault)
+		defer server.Close()
+
+		client, err := NewClient(hclog.NewNullLogger(), Config{
+			Address:             server.URL,
+			AuthMethod:          AuthMethodAppRole,
+			AppRoleID:           "role-id",
+			AppRoleSecretIDPath: tokenPath,
+		})
+		require.NoError(t, err)
+
+		_, err = client.Logical().WriteWithContext(context.Background(), "pki/issue/web", nil)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"role_id": "role-id", "secret_id": "jwt"}, vault.login)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		vault := &fakeVault{token: "valid"}
+		server := httptest.NewServer(vault)
+		defer server.Close()
+
+		client, err := NewClient(hclog.NewNullLogger(), Config{
+			Address: server.URL,
+		})
+		require.NoError(t, err)
+		require.NoError(t, client.Manage(context.Background()))
+
+		// externally managed tokens are never replaced
+		_, err = client.Logical().WriteWithContext(context.Background(), "pki/issue/web", nil)
+		require.Error(t, err)
+		require.Equal(t, 0, vault.logins)
+	})
+
+	t.Run("non-expiring token", func(t *testing.T) {
+		vault := &fakeVault{neverExpires: true}
+		server := httptest.NewServer(vault)
+		defer server.Close()
+
+		client, err := NewClient(hclog.NewNullLogger(), Config{
+			Address:             server.URL,
+			AuthMethod:          AuthMethodKubernetes,
+			AuthMount:           "k8s",
+			KubernetesRole:      "gateway",
+			KubernetesTokenPath: tokenPath,
+		})
+		require.NoError(t, err)
+
+		// tokens without a TTL are kept until the client stops
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		require.NoError(t, client.Manage(ctx))
+
+		vault.mutex.Lock()
+		defer vault.mutex.Unlock()
+		require.Equal(t, 1, vault.logins)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewClient(hclog.NewNullLogger(), Config{AuthMethod: "ldap"})
+		require.Error(t, err)
+		_, err = NewClient(hclog.NewNullLogger(), Config{AuthMethod: AuthMethodKubernetes})
+		require.Error(t, err)
+		_, err = NewClient(hclog.NewNullLogger(), Config{AuthMethod: AuthMethodAppRole})
+		require.Error(t, err)
+	})
+}
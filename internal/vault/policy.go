@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AllowlistAllNamespaces is the namespace whose allowlist entries apply to every namespace
+const AllowlistAllNamespaces = "*"
+
+// Allowlist maps Kubernetes namespaces to the Vault paths that the VaultCertificates
+// in them may reference, a path also allows everything beneath it.
+type Allowlist map[string][]string
+
+// ParseAllowlist parses <namespace>=<path> entries into an Allowlist
+func ParseAllowlist(entries []string) (Allowlist, error) {
+	allowlist := Allowlist{}
+	for _, entry := range entries {
+		namespace, path, found := strings.Cut(entry, "=")
+		namespace, path = strings.TrimSpace(namespace), strings.Trim(strings.TrimSpace(path), "/")
+		if !found || namespace == "" || path == "" || !validPath(path) {
+			return nil, fmt.Errorf("invalid vault allowlist entry %q, expected <namespace>=<path>", entry)
+		}
+		allowlist[namespace] = append(allowlist[namespace], path)
+	}
+	return allowlist, nil
+}
+
+// Allows checks whether a VaultCertificate in the given namespace may reference the path
+func (a Allowlist) Allows(namespace, path string) bool {
+	path = strings.Trim(path, "/")
+	if !validPath(path) {
+		return false
+	}
+	for _, entries := range [][]string{a[namespace], a[AllowlistAllNamespaces]} {
+		for _, allowed := range entries {
+			if path == allowed || strings.HasPrefix(path, allowed+"/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validPath rejects paths that could resolve outside of an allowed prefix
+func validPath(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// CertificatePolicy restricts what the VaultCertificates in each Kubernetes namespace
+// can reference, since their certificates are read with the controller's own Vault identity
+type CertificatePolicy struct {
+	// KVMount is the mount KV certificates are read from when they don't set their own
+	KVMount string
+	// KVPaths are the KV paths, prefixed with their mount, that certificates can be read from
+	KVPaths Allowlist
+	// PKIMount and PKIRole are used to issue PKI certificates that don't set their own
+	PKIMount string
+	PKIRole  string
+	// PKIRoles are the PKI roles, prefixed with their mount, that certificates can be
+	// issued with, a mount on its own allows any of its roles
+	PKIRoles Allowlist
+}
+
+// AllowsKV checks whether a VaultCertificate in the given namespace may read the KV secret
+func (p CertificatePolicy) AllowsKV(namespace string, secret KVSecret) bool {
+	mount := secret.Mount
+	if mount == "" {
+		mount = p.KVMount
+	}
+	return p.KVPaths.Allows(namespace, strings.Trim(mount, "/")+"/"+strings.Trim(secret.Path, "/"))
+}
+
+// AllowsPKI checks whether a VaultCertificate in the given namespace may issue the PKI secret
+func (p CertificatePolicy) AllowsPKI(namespace string, secret PKISecret) bool {
+	mount, role := secret.Mount, secret.Role
+	if mount == "" {
+		mount = p.PKIMount
+	}
+	if role == "" {
+		role = p.PKIRole
+	}
+	return p.PKIRoles.Allows(namespace, strings.Trim(mount, "/")+"/"+role)
+}
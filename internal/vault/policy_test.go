@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAllowlist(t *testing.T) {
+	allowlist, err := ParseAllowlist([]string{"team-a=secret/team-a/", "team-a=/secret/shared", "*=secret/global"})
+	require.NoError(t, err)
+	assert.Equal(t, Allowlist{
+		"team-a": {"secret/team-a", "secret/shared"},
+		"*":      {"secret/global"},
+	}, allowlist)
+
+	for _, entry := range []string{"team-a", "=secret", "team-a=", "team-a=secret/../other"} {
+		_, err := ParseAllowlist([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestAllowlistAllows(t *testing.T) {
+	allowlist := Allowlist{
+		"team-a": {"secret/team-a"},
+		"*":      {"secret/global"},
+	}
+
+	assert.True(t, allowlist.Allows("team-a", "secret/team-a"))
+	assert.True(t, allowlist.Allows("team-a", "/secret/team-a/gateway"))
+	assert.True(t, allowlist.Allows("team-b", "secret/global/gateway"))
+	assert.True(t, allowlist.Allows("team-a", "secret/global/gateway"))
+
+	// only whole path segments match
+	assert.False(t, allowlist.Allows("team-a", "secret/team-ab"))
+	// paths of other namespaces
+	assert.False(t, allowlist.Allows("team-b", "secret/team-a/gateway"))
+	// escaping an allowed path
+	assert.False(t, allowlist.Allows("team-a", "secret/team-a/../team-b"))
+	assert.False(t, allowlist.Allows("team-a", "secret/team-a//gateway"))
+
+	assert.False(t, Allowlist(nil).Allows("team-a", "secret/team-a"))
+}
+
+func TestCertificatePolicyAllowsKV(t *testing.T) {
+	policy := CertificatePolicy{
+		KVMount: "secret",
+		KVPaths: Allowlist{"team-a": {"secret/team-a", "kv/team-a"}},
+	}
+
+	assert.True(t, policy.AllowsKV("team-a", NewKVSecret("/team-a/gateway", "cert", "key")))
+	assert.False(t, policy.AllowsKV("team-a", NewKVSecret("/team-b/gateway", "cert", "key")))
+
+	secret := NewKVSecret("/team-a/gateway", "cert", "key")
+	secret.Mount = "kv"
+	assert.True(t, policy.AllowsKV("team-a", secret))
+	secret.Mount = "other"
+	assert.False(t, policy.AllowsKV("team-a", secret))
+}
+
+func TestCertificatePolicyAllowsPKI(t *testing.T) {
+	policy := CertificatePolicy{
+		PKIMount: "pki",
+		PKIRole:  "default",
+		PKIRoles: Allowlist{
+			"team-a": {"pki/default", "pki-team-a"},
+		},
+	}
+
+	secret := NewPKISecret("example.com", "", "", "", "")
+	assert.True(t, policy.AllowsPKI("team-a", secret))
+	assert.False(t, policy.AllowsPKI("team-b", secret))
+
+	secret.Role = "admin"
+	assert.False(t, policy.AllowsPKI("team-a", secret))
+
+	// any role on an allowed mount
+	secret.Mount = "pki-team-a"
+	assert.True(t, policy.AllowsPKI("team-a", secret))
+
+	// no role to issue with
+	policy.PKIRole = ""
+	assert.False(t, policy.AllowsPKI("team-a", NewPKISecret("example.com", "", "", "", "")))
+}
@@ -19,6 +19,10 @@ const (
 	queryParamIPSANs    = "ipSans"
 	queryParamOtherSANs = "otherSans"
 	queryParamTTL       = "ttl"
+	queryParamRole      = "role"
+
+	// Shared
+	queryParamMount = "mount"
 
 	// KV secret
 	queryParamCertField       = "tlsCertField"
@@ -29,6 +33,8 @@ type KVSecret struct {
 	Path            string
 	CertField       string
 	PrivateKeyField string
+	// Mount optionally overrides the path the KV engine is mounted at.
+	Mount string
 }
 
 func NewKVSecret(path, certField, privateKeyField string) KVSecret {
@@ -61,7 +67,9 @@ func ParseKVSecret(ref string) (KVSecret, error) {
 		return KVSecret{}, ErrInvalidSecret
 	}
 
-	return NewKVSecret(path, certField, privateKeyField), nil
+	secret := NewKVSecret(path, certField, privateKeyField)
+	secret.Mount = parsed.Query().Get(queryParamMount)
+	return secret, nil
 }
 
 func (s KVSecret) String() string {
@@ -75,6 +83,10 @@ func (s KVSecret) String() string {
 		v.Add(queryParamPrivateKeyField, s.PrivateKeyField)
 	}
 
+	if s.Mount != "" {
+		v.Add(queryParamMount, s.Mount)
+	}
+
 	return (&url.URL{
 		Scheme:   KVSecretScheme,
 		Path:     s.Path,
@@ -92,6 +104,10 @@ type PKISecret struct {
 	IPSANs     string
 	OtherSANs  string
 	TTL        string
+	// Role optionally overrides the PKI role the certificate is issued with.
+	Role string
+	// Mount optionally overrides the path the PKI engine is mounted at.
+	Mount string
 }
 
 // NewPKISecret creates a descriptor for a certificate to be generated via Vault's PKI API.
@@ -114,8 +130,9 @@ func NewPKISecret(commonName, altNames, ipSANs, otherSANs, ttl string) PKISecret
 //
 // https://www.vaultproject.io/api-docs/secret/pki
 //
-// Some components such as the issuer and issue are configured globally today.
-// In the future, we could include those as additional query parameters.
+// The issuer is configured globally today and the role and mount used to issue
+// the certificate are configured globally unless overridden by the "role" and
+// "mount" parameters.
 func ParsePKISecret(ref string) (PKISecret, error) {
 	parsed, err := url.Parse(ref)
 	if err != nil {
@@ -132,7 +149,10 @@ func ParsePKISecret(ref string) (PKISecret, error) {
 	otherSANs := parsed.Query().Get(queryParamOtherSANs)
 	ttl := parsed.Query().Get(queryParamTTL)
 
-	return NewPKISecret(commonName, altNames, ipSANs, otherSANs, ttl), nil
+	secret := NewPKISecret(commonName, altNames, ipSANs, otherSANs, ttl)
+	secret.Role = parsed.Query().Get(queryParamRole)
+	secret.Mount = parsed.Query().Get(queryParamMount)
+	return secret, nil
 }
 
 // String serializes a PKISecret into an opaque string that can later
@@ -151,6 +171,12 @@ func (s PKISecret) String() string {
 	if s.TTL != "" {
 		v.Add(queryParamTTL, s.TTL)
 	}
+	if s.Role != "" {
+		v.Add(queryParamRole, s.Role)
+	}
+	if s.Mount != "" {
+		v.Add(queryParamMount, s.Mount)
+	}
 
 	return (&url.URL{
 		Scheme:   PKISecretScheme,
@@ -63,6 +63,14 @@ func TestPKISecret_String(t *testing.T) {
 	secret.OtherSANs = "helloworld.com"
 	assert.Equal(t, "vault+pki://example.com?altNames=www.example.com&ipSans=127.0.0.1&otherSans=helloworld.com&ttl=12h", secret.String())
 
+	// Test with a role override
+	secret.Role = "web"
+	assert.Equal(t, "vault+pki://example.com?altNames=www.example.com&ipSans=127.0.0.1&otherSans=helloworld.com&role=web&ttl=12h", secret.String())
+
+	// Test with a mount override
+	secret.Mount = "pki-int"
+	assert.Equal(t, "vault+pki://example.com?altNames=www.example.com&ipSans=127.0.0.1&mount=pki-int&otherSans=helloworld.com&role=web&ttl=12h", secret.String())
+
 	// Test round trip
 	secret2, err := ParsePKISecret(secret.String())
 	require.NoError(t, err)
@@ -115,6 +123,10 @@ func TestKVSecret_String(t *testing.T) {
 	secret.PrivateKeyField = "tls.key"
 	assert.Equal(t, "vault+kv:///kv/api-gateway-tls-cert?tlsCertField=tls.cert&tlsPrivateKeyField=tls.key", secret.String())
 
+	// Test with a mount override
+	secret.Mount = "kv-tls"
+	assert.Equal(t, "vault+kv:///kv/api-gateway-tls-cert?mount=kv-tls&tlsCertField=tls.cert&tlsPrivateKeyField=tls.key", secret.String())
+
 	// Test round trip
 	secret2, err := ParseKVSecret(secret.String())
 	require.NoError(t, err)
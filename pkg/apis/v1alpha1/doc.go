@@ -23,5 +23,8 @@ var (
 func RegisterTypes(scheme *runtime.Scheme) {
 	scheme.AddKnownTypes(GroupVersion, &GatewayClassConfig{}, &GatewayClassConfigList{})
 	scheme.AddKnownTypes(GroupVersion, &MeshService{}, &MeshServiceList{})
+	scheme.AddKnownTypes(GroupVersion, &ExternalService{}, &ExternalServiceList{})
+	scheme.AddKnownTypes(GroupVersion, &VaultCertificate{}, &VaultCertificateList{})
+	scheme.AddKnownTypes(GroupVersion, &GatewayConfig{}, &GatewayConfigList{})
 	meta.AddToGroupVersion(scheme, GroupVersion)
 }
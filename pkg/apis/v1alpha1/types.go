@@ -4,11 +4,15 @@
 package v1alpha1
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	rbac "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
@@ -17,11 +21,19 @@ import (
 const (
 	GatewayClassConfigKind = "GatewayClassConfig"
 	MeshServiceKind        = "MeshService"
+	ExternalServiceKind    = "ExternalService"
+	VaultCertificateKind   = "VaultCertificate"
+	GatewayConfigKind      = "GatewayConfig"
 )
 
+// ConfigHashAnnotation records a hash of the pod template rendered for
+// a gateway deployment, so that changes to it roll out
+const ConfigHashAnnotation = "api-gateway.consul.hashicorp.com/config-hash"
+
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
 
 // GatewayClassConfig describes the configuration of a consul-api-gateway GatewayClass.
 type GatewayClassConfig struct {
@@ -31,8 +43,39 @@ type GatewayClassConfig struct {
 
 	// Spec defines the desired state of GatewayClassConfig.
 	Spec GatewayClassConfigSpec `json:"spec,omitempty"`
+	// Status defines the current state of GatewayClassConfig.
+	Status GatewayClassConfigStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayClassConfigStatus specifies the 'status' of the Config CRD.
+type GatewayClassConfigStatus struct {
+	// GatewayClasses is the number of GatewayClasses using this configuration.
+	GatewayClasses int32 `json:"gatewayClasses,omitempty"`
+	// Gateways is the number of Gateways using this configuration through their GatewayClass.
+	Gateways int32 `json:"gateways,omitempty"`
+	// Conditions describe whether the configuration is valid, the message of
+	// an invalid configuration lists its validation errors.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+const (
+	// GatewayClassConfigConditionValid is the condition set once the controller
+	// has validated a GatewayClassConfig.
+	GatewayClassConfigConditionValid = "Valid"
+
+	// GatewayClassConfigReasonValid is used when the configuration is valid.
+	GatewayClassConfigReasonValid = "Valid"
+	// GatewayClassConfigReasonInvalid is used when the configuration has validation errors.
+	GatewayClassConfigReasonInvalid = "Invalid"
+)
+
 // +k8s:deepcopy-gen=true
 
 // GatewayClassConfigSpec specifies the 'spec' of the Config CRD.
@@ -60,8 +103,67 @@ type GatewayClassConfigSpec struct {
 	LogLevel string `json:"logLevel,omitempty"`
 	// Configuration information about how many instances to deploy
 	DeploymentSpec DeploymentSpec `json:"deployment,omitempty"`
+	// Configuration information about the service created for gateways
+	ServiceSpec ServiceSpec `json:"service,omitempty"`
+	// The addresses Gateways may request in their spec.addresses, no addresses
+	// may be requested if this is unset
+	AddressesSpec *AddressesSpec `json:"addresses,omitempty"`
+	// The cert-manager issuers that listeners may request certificates from, listeners
+	// can't request cert-manager certificates if this is unset
+	CertManagerSpec *CertManagerSpec `json:"certManager,omitempty"`
 	// Configuration information for managing connections in Envoy
 	ConnectionManagement ConnectionManagementSpec `json:"connectionManagement,omitempty"`
+	// Overrides applied to the pod template of gateway deployments
+	PodTemplate *PodTemplateSpec `json:"podTemplate,omitempty"`
+	// +kubebuilder:validation:Enum=Snapshot;Follow
+	// How existing gateways are updated when this configuration changes. Snapshot, the
+	// default, keeps running the configuration a gateway was created with. Follow
+	// re-renders gateways from the latest configuration and rolls out the changes.
+	// Changes to a gateway's GatewayConfig are applied with either strategy.
+	UpdateStrategy UpdateStrategyType `json:"updateStrategy,omitempty"`
+	// The fields of this configuration that Gateways may override with their own
+	// GatewayConfig, Gateways can't override anything if this is empty
+	AllowedOverrides []GatewayConfigField `json:"allowedOverrides,omitempty"`
+}
+
+type UpdateStrategyType string
+
+const (
+	UpdateStrategySnapshot UpdateStrategyType = "Snapshot"
+	UpdateStrategyFollow   UpdateStrategyType = "Follow"
+)
+
+// +k8s:deepcopy-gen=true
+
+type PodTemplateSpec struct {
+	// Additional labels for gateway pods, labels used by the controller to select
+	// gateway pods cannot be overridden
+	Labels map[string]string `json:"labels,omitempty"`
+	// Additional annotations for gateway pods
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Compute resources required by the gateway container.
+	// More info: https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Security options for the gateway container
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// Security options for gateway pods
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Priority class of gateway pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Scheduling constraints for gateway pods, replaces the default
+	// anti-affinity that spreads gateway pods across nodes
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// How gateway pods are spread across topology domains.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Additional environment variables for the gateway container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Additional volumes for gateway pods
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// Additional volume mounts for the gateway container
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Sidecar containers added to gateway pods
+	Containers []corev1.Container `json:"containers,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -74,7 +176,25 @@ type ConnectionManagementSpec struct {
 
 // +k8s:deepcopy-gen=true
 
+type DeploymentMode string
+
+const (
+	DeploymentModeDeployment DeploymentMode = "Deployment"
+	DeploymentModeDaemonSet  DeploymentMode = "DaemonSet"
+)
+
+// +k8s:deepcopy-gen=true
+
 type DeploymentSpec struct {
+	// +kubebuilder:validation:Enum=Deployment;DaemonSet
+	// How gateway instances are run, either as a Deployment with a number of instances
+	// behind a Service, or as a DaemonSet running one instance on the host network of
+	// every node matching the node selector. Defaults to Deployment
+	Mode DeploymentMode `json:"mode,omitempty"`
+	// +kubebuilder:validation:Enum=ClusterFirst;ClusterFirstWithHostNet;Default
+	// DNS policy for gateway pods, defaults to ClusterFirstWithHostNet in DaemonSet
+	// mode so that gateways on the host network can still resolve cluster names
+	DNSPolicy corev1.DNSPolicy `json:"dnsPolicy,omitempty"`
 	// +kubebuilder:default:=1
 	// +kubebuilder:validation:Maximum=8
 	// +kubebuilder:validation:Minimum=1
@@ -90,8 +210,147 @@ type DeploymentSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	// Minimum allowed number of gateway instances
 	MinInstances *int32 `json:"minInstances,omitempty"`
+	// Configuration for autoscaling gateway instances between the minimum and maximum
+	// allowed number of instances, if set a HorizontalPodAutoscaler is created for each gateway
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// Configuration for limiting voluntary disruptions of gateway instances, such as
+	// node drains, if set a PodDisruptionBudget is created for each gateway
+	DisruptionBudget *DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+type ServiceSpec struct {
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// Whether external traffic is routed to gateway instances on any node or only
+	// to instances on the node it arrived at, which preserves the client IP. Only
+	// used with NodePort and LoadBalancer services, defaults to Cluster
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicyType `json:"externalTrafficPolicy,omitempty"`
+	// CIDRs allowed to reach LoadBalancer services, if supported by the cloud provider
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// The class of load balancer implementation used for LoadBalancer services,
+	// Kubernetes doesn't allow this to be changed once a service is created
+	LoadBalancerClass *string `json:"loadBalancerClass,omitempty"`
+	// The annotation used to request the IP addresses in a Gateway's spec.addresses
+	// from the cloud provider, such as service.beta.kubernetes.io/azure-load-balancer-ipv4.
+	// If unset, the first requested IP address is set as the service's loadBalancerIP.
+	// Only used with LoadBalancer services
+	LoadBalancerIPAnnotation string `json:"loadBalancerIPAnnotation,omitempty"`
+	// The IP families assigned to the service, Kubernetes only allows these to be
+	// changed on existing services along with the IP family policy
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+	// +kubebuilder:validation:Enum=SingleStack;PreferDualStack;RequireDualStack
+	// Whether the service is single or dual stack
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+	// Fixed node ports for listener ports, listener ports that aren't mapped are
+	// assigned a node port by Kubernetes. Only used with NodePort and LoadBalancer services
+	NodePorts []ServiceNodePort `json:"nodePorts,omitempty"`
+	// +kubebuilder:validation:Enum=None;ClientIP
+	// Whether connections from a client are sent to the same gateway instance, defaults to None
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=86400
+	// How long ClientIP session affinity lasts, defaults to 3 hours
+	SessionAffinityTimeoutSeconds *int32 `json:"sessionAffinityTimeoutSeconds,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+type AddressesSpec struct {
+	// CIDRs that the IP addresses requested by Gateways must fall within. Requested
+	// addresses are set on gateway services with the controller's permissions, so
+	// IP addresses outside of these ranges are never assigned
+	AllowedIPRanges []string `json:"allowedIPRanges,omitempty"`
+	// Whether Gateways may request hostnames, which are handed to external-dns.
+	// Only used with LoadBalancer services
+	AllowHostnames bool `json:"allowHostnames,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+type CertManagerSpec struct {
+	// The issuers that listeners may request certificates from. Certificates are
+	// requested with the controller's permissions, so other issuers are never used
+	AllowedIssuers []CertManagerIssuerReference `json:"allowedIssuers,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+type CertManagerIssuerReference struct {
+	// The name of the issuer, Issuers are looked up in the Gateway's namespace
+	Name string `json:"name"`
+	// The kind of the issuer, Issuer, ClusterIssuer or the kind of an external issuer
+	// +kubebuilder:default=Issuer
+	Kind string `json:"kind,omitempty"`
+	// The API group of the issuer, defaults to cert-manager.io
+	Group string `json:"group,omitempty"`
+}
+
+// AllowsIssuer checks whether listeners may request certificates from the given issuer
+func (s *CertManagerSpec) AllowsIssuer(name, kind, group string) bool {
+	if s == nil {
+		return false
+	}
+	for _, allowed := range s.AllowedIssuers {
+		if allowed.Name == name && certManagerIssuerKind(allowed.Kind) == certManagerIssuerKind(kind) && certManagerIssuerGroup(allowed.Group) == certManagerIssuerGroup(group) {
+			return true
+		}
+	}
+	return false
+}
+
+func certManagerIssuerKind(kind string) string {
+	if kind == "" {
+		return "Issuer"
+	}
+	return kind
+}
+
+func certManagerIssuerGroup(group string) string {
+	if group == "" {
+		return "cert-manager.io"
+	}
+	return group
+}
+
+type ServiceNodePort struct {
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// The listener port
+	Port int32 `json:"port"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// The node port to expose it on
+	NodePort int32 `json:"nodePort"`
+}
+
+// +k8s:deepcopy-gen=true
+
+type AutoscalingSpec struct {
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=1
+	// Target average CPU utilization of gateway instances, as a percentage of their requested CPU.
+	// Defaults to 80 if no other metrics are specified, which requires the pod template to request CPU.
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// Additional metrics to scale on, such as the number of active downstream
+	// connections exported through a custom or external metrics adapter.
+	// More info: https://kubernetes.io/docs/tasks/run-application/horizontal-pod-autoscale/#scaling-on-custom-metrics
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+type DisruptionBudgetSpec struct {
+	// Minimum number or percentage of gateway instances that must remain available
+	// during voluntary disruptions. Takes precedence over MaxUnavailable.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// Maximum number or percentage of gateway instances that may be unavailable
+	// during voluntary disruptions. Defaults to 1 if MinAvailable isn't set.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
+// +k8s:deepcopy-gen=true
+
 type ConsulSpec struct {
 	// Consul authentication information
 	AuthSpec AuthSpec `json:"authentication,omitempty"`
@@ -127,13 +386,15 @@ type ImageSpec struct {
 	Envoy string `json:"envoy,omitempty"`
 }
 
-//+kubebuilder:object:generate=true
+// +k8s:deepcopy-gen=true
 
 type CopyAnnotationsSpec struct {
 	// List of annotations to copy to the gateway service.
 	Service []string `json:"service,omitempty"`
 }
 
+// +k8s:deepcopy-gen=true
+
 type AuthSpec struct {
 	// Whether deployments should be run with "managed" Kubernetes ServiceAccounts created by the gateway controller.
 	Managed bool `json:"managed,omitempty"`
@@ -145,6 +406,29 @@ type AuthSpec struct {
 	Namespace string `json:"namespace,omitempty"`
 	// The name of an existing Kubernetes PodSecurityPolicy to bind to the managed ServiceAccount if managed is true.
 	PodSecurityPolicy string `json:"podSecurityPolicy,omitempty"`
+	// The name of an existing OpenShift SecurityContextConstraints to bind to the managed ServiceAccount if managed
+	// is true. Gateway pods don't request a user ID when this is set, so that OpenShift can assign one from the
+	// namespace's range.
+	SecurityContextConstraints string `json:"securityContextConstraints,omitempty"`
+	// The path of the Kubernetes ServiceAccount token gateways use to log in with the auth method. Defaults to the
+	// projected token's path if projectedToken is set and to the pod's default ServiceAccount token otherwise.
+	BearerTokenFile string `json:"bearerTokenFile,omitempty"`
+	// Mount a projected, audience-bound ServiceAccount token into gateway pods at bearerTokenFile rather than
+	// logging in with the pod's default ServiceAccount token. Gateways re-read the token and log in again before
+	// either it or their Consul ACL token expire.
+	ProjectedToken *ProjectedTokenSpec `json:"projectedToken,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ProjectedTokenSpec configures the projected ServiceAccount token gateways log in to Consul with.
+type ProjectedTokenSpec struct {
+	// The intended audience of the token, this should match the audience the Consul auth method is configured
+	// to validate. Defaults to the audience of the Kubernetes API server.
+	Audience string `json:"audience,omitempty"`
+	// How long the token is valid for, Kubernetes rotates the token before it expires. Defaults to one hour.
+	// +kubebuilder:validation:Minimum=600
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -161,7 +445,29 @@ type GatewayClassConfigList struct {
 // on the GatewayClassConfig. If the GatewayClassConfig is configured in
 // such a way that does not require a Role, nil is returned.
 func (c *GatewayClassConfig) RoleFor(gw *gwv1beta1.Gateway) *rbac.Role {
-	if !c.Spec.ConsulSpec.AuthSpec.Managed || c.Spec.ConsulSpec.AuthSpec.PodSecurityPolicy == "" {
+	auth := c.Spec.ConsulSpec.AuthSpec
+	if !auth.Managed {
+		return nil
+	}
+
+	rules := []rbac.PolicyRule{}
+	if auth.PodSecurityPolicy != "" {
+		rules = append(rules, rbac.PolicyRule{
+			APIGroups:     []string{"policy"},
+			Resources:     []string{"podsecuritypolicies"},
+			ResourceNames: []string{auth.PodSecurityPolicy},
+			Verbs:         []string{"use"},
+		})
+	}
+	if auth.SecurityContextConstraints != "" {
+		rules = append(rules, rbac.PolicyRule{
+			APIGroups:     []string{"security.openshift.io"},
+			Resources:     []string{"securitycontextconstraints"},
+			ResourceNames: []string{auth.SecurityContextConstraints},
+			Verbs:         []string{"use"},
+		})
+	}
+	if len(rules) == 0 {
 		return nil
 	}
 
@@ -171,12 +477,7 @@ func (c *GatewayClassConfig) RoleFor(gw *gwv1beta1.Gateway) *rbac.Role {
 			Namespace: gw.Namespace,
 			Labels:    utils.LabelsForGateway(gw),
 		},
-		Rules: []rbac.PolicyRule{{
-			APIGroups:     []string{"policy"},
-			Resources:     []string{"podsecuritypolicies"},
-			ResourceNames: []string{c.Spec.ConsulSpec.AuthSpec.PodSecurityPolicy},
-			Verbs:         []string{"use"},
-		}},
+		Rules: rules,
 	}
 }
 
@@ -231,8 +532,124 @@ func (c *GatewayClassConfig) ServiceAccountFor(gw *gwv1beta1.Gateway) *corev1.Se
 	}
 }
 
+// +kubebuilder:validation:Enum=serviceType;nodeSelector;tolerations;copyAnnotations;deployment;deploymentMode;service;podTemplate
+
+// GatewayConfigField is the name of a GatewayClassConfig field that a GatewayConfig can
+// override. Overriding the deployment doesn't allow changing its mode, since DaemonSet
+// mode runs gateways on the host network, that needs deploymentMode as well.
+type GatewayConfigField string
+
+const (
+	GatewayConfigFieldServiceType     GatewayConfigField = "serviceType"
+	GatewayConfigFieldNodeSelector    GatewayConfigField = "nodeSelector"
+	GatewayConfigFieldTolerations     GatewayConfigField = "tolerations"
+	GatewayConfigFieldCopyAnnotations GatewayConfigField = "copyAnnotations"
+	GatewayConfigFieldDeployment      GatewayConfigField = "deployment"
+	GatewayConfigFieldDeploymentMode  GatewayConfigField = "deploymentMode"
+	GatewayConfigFieldService         GatewayConfigField = "service"
+	GatewayConfigFieldPodTemplate     GatewayConfigField = "podTemplate"
+)
+
+// GatewayConfigAnnotation is set on a Gateway to the name of a GatewayConfig in
+// the Gateway's namespace that overrides the configuration of its GatewayClass
+const GatewayConfigAnnotation = "api-gateway.consul.hashicorp.com/gateway-config"
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// GatewayConfig overrides parts of a GatewayClassConfig for the Gateways that
+// reference it, the fields it may set are limited by the GatewayClassConfig's
+// allowedOverrides.
+type GatewayConfig struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of GatewayConfig.
+	Spec GatewayConfigSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// GatewayConfigSpec specifies the 'spec' of the GatewayConfig CRD. Fields
+// that are set replace the corresponding GatewayClassConfig fields.
+type GatewayConfigSpec struct {
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	ServiceType *corev1.ServiceType `json:"serviceType,omitempty"`
+	// Selector which must match a node's labels for the pod to be scheduled on that node.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations allow the scheduler to schedule nodes with matching taints
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Annotation Information to copy to services or deployments
+	CopyAnnotations *CopyAnnotationsSpec `json:"copyAnnotations,omitempty"`
+	// Configuration information about how many instances to deploy
+	DeploymentSpec *DeploymentSpec `json:"deployment,omitempty"`
+	// Configuration information about the service created for gateways
+	ServiceSpec *ServiceSpec `json:"service,omitempty"`
+	// Overrides applied to the pod template of gateway deployments
+	PodTemplate *PodTemplateSpec `json:"podTemplate,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayConfigList is a list of GatewayConfig resources.
+type GatewayConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GatewayConfig `json:"items"`
+}
+
+// WithOverrides returns a copy of the GatewayClassConfig with the fields set in
+// the GatewayConfig merged over it. An error is returned if the GatewayConfig
+// sets any fields that the GatewayClassConfig doesn't allow to be overridden.
+func (c *GatewayClassConfig) WithOverrides(override *GatewayConfig) (GatewayClassConfig, error) {
+	merged := *c.DeepCopy()
+
+	allowed := make(map[GatewayConfigField]struct{})
+	for _, field := range c.Spec.AllowedOverrides {
+		allowed[field] = struct{}{}
+	}
+	set := func(field GatewayConfigField, isSet bool, apply func()) error {
+		if !isSet {
+			return nil
+		}
+		if _, ok := allowed[field]; !ok {
+			return fmt.Errorf("GatewayClassConfig %q does not allow overriding %s", c.Name, field)
+		}
+		apply()
+		return nil
+	}
+
+	spec := override.Spec
+	for _, err := range []error{
+		set(GatewayConfigFieldServiceType, spec.ServiceType != nil, func() { merged.Spec.ServiceType = spec.ServiceType }),
+		set(GatewayConfigFieldNodeSelector, spec.NodeSelector != nil, func() { merged.Spec.NodeSelector = spec.NodeSelector }),
+		set(GatewayConfigFieldTolerations, spec.Tolerations != nil, func() { merged.Spec.Tolerations = spec.Tolerations }),
+		set(GatewayConfigFieldCopyAnnotations, spec.CopyAnnotations != nil, func() { merged.Spec.CopyAnnotations = *spec.CopyAnnotations }),
+		set(GatewayConfigFieldDeployment, spec.DeploymentSpec != nil, func() {
+			mode := merged.Spec.DeploymentSpec.Mode
+			merged.Spec.DeploymentSpec = *spec.DeploymentSpec
+			if merged.Spec.DeploymentSpec.Mode == "" {
+				merged.Spec.DeploymentSpec.Mode = mode
+			}
+		}),
+		set(GatewayConfigFieldDeploymentMode, spec.DeploymentSpec != nil && spec.DeploymentSpec.Mode != "" &&
+			(spec.DeploymentSpec.Mode == DeploymentModeDaemonSet) != (c.Spec.DeploymentSpec.Mode == DeploymentModeDaemonSet), func() {}),
+		set(GatewayConfigFieldService, spec.ServiceSpec != nil, func() { merged.Spec.ServiceSpec = *spec.ServiceSpec }),
+		set(GatewayConfigFieldPodTemplate, spec.PodTemplate != nil, func() { merged.Spec.PodTemplate = spec.PodTemplate }),
+	} {
+		if err != nil {
+			return GatewayClassConfig{}, err
+		}
+	}
+
+	return merged, nil
+}
+
 // +genclient
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 
 // MeshService holds a reference to an externally managed Consul Service Mesh service.
 type MeshService struct {
@@ -242,6 +659,8 @@ type MeshService struct {
 
 	// Spec defines the desired state of MeshService.
 	Spec MeshServiceSpec `json:"spec,omitempty"`
+	// Status defines the current state of MeshService.
+	Status MeshServiceStatus `json:"status,omitempty"`
 }
 
 // +k8s:deepcopy-gen=true
@@ -255,6 +674,34 @@ type MeshServiceSpec struct {
 	Peer *string `json:"peer,omitempty"`
 }
 
+const (
+	// MeshServiceConditionResolved is the condition set once the controller has
+	// attempted to resolve the Consul service a MeshService references.
+	MeshServiceConditionResolved = "Resolved"
+
+	// MeshServiceReasonResolved is used when the Consul service was found.
+	MeshServiceReasonResolved = "Resolved"
+	// MeshServiceReasonNotFound is used when the Consul service could not be found.
+	MeshServiceReasonNotFound = "NotFound"
+)
+
+// +k8s:deepcopy-gen=true
+
+// MeshServiceStatus specifies the 'status' of the MeshService CRD.
+type MeshServiceStatus struct {
+	// ConsulNamespace is the Consul namespace the service is looked up in.
+	ConsulNamespace string `json:"consulNamespace,omitempty"`
+	// ConsulPartition is the Consul admin partition the service is looked up in.
+	ConsulPartition string `json:"consulPartition,omitempty"`
+	// Conditions describe whether the Consul service could be resolved.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // MeshServiceList is a list of MeshService resources.
@@ -265,6 +712,140 @@ type MeshServiceList struct {
 	Items []MeshService `json:"items"`
 }
 
+// +genclient
+// +kubebuilder:object:root=true
+
+// ExternalService holds a reference to a service outside of the service mesh that
+// is registered in Consul and reached through a terminating gateway.
+type ExternalService struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of ExternalService.
+	Spec ExternalServiceSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// ExternalServiceSpec specifies the 'spec' of the ExternalService CRD.
+type ExternalServiceSpec struct {
+	// Name optionally specifies the name the service is registered as in Consul.
+	// If not specified, the name of the ExternalService is used.
+	Name string `json:"name,omitempty"`
+	// Address is the hostname or IP address of the external service.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+	// Port is the port the external service listens on.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// TLS optionally configures the terminating gateway to originate TLS
+	// connections to the external service.
+	TLS *ExternalServiceTLSSpec `json:"tls,omitempty"`
+}
+
+// ExternalServiceTLSSpec describes how a terminating gateway originates TLS to an
+// external service. All file paths refer to files on the terminating gateway.
+type ExternalServiceTLSSpec struct {
+	// CAFile is the path to a CA bundle used to verify the external service's certificate.
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile is the path to a client certificate presented to the external service.
+	CertFile string `json:"certFile,omitempty"`
+	// KeyFile is the path to the private key for the client certificate.
+	KeyFile string `json:"keyFile,omitempty"`
+	// SNI is the server name to use when connecting to the external service.
+	SNI string `json:"sni,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExternalServiceList is a list of ExternalService resources.
+type ExternalServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ExternalService `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+
+// VaultCertificate describes a listener certificate that is either stored in
+// Vault's KV secrets engine or issued by Vault's PKI secrets engine. Gateway
+// listeners reference it from their TLS certificateRefs.
+type VaultCertificate struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of VaultCertificate.
+	Spec VaultCertificateSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// VaultCertificateSpec specifies the 'spec' of the VaultCertificate CRD. Exactly
+// one of KV or PKI must be set.
+type VaultCertificateSpec struct {
+	// KV references a certificate and private key stored in Vault's KV secrets engine.
+	KV *VaultKVCertificate `json:"kv,omitempty"`
+	// PKI describes a certificate to be issued by Vault's PKI secrets engine.
+	PKI *VaultPKICertificate `json:"pki,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// VaultKVCertificate references a base64 encoded, PEM formatted certificate and
+// private key stored in Vault's KV secrets engine.
+type VaultKVCertificate struct {
+	// Mount is the path the KV v2 secrets engine is mounted at. If not
+	// specified, the mount the controller is configured with is used.
+	Mount string `json:"mount,omitempty"`
+	// Path is the path of the secret inside of the KV mount.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+	// CertField is the field of the secret that holds the certificate.
+	// +kubebuilder:validation:MinLength=1
+	CertField string `json:"certField"`
+	// PrivateKeyField is the field of the secret that holds the private key.
+	// +kubebuilder:validation:MinLength=1
+	PrivateKeyField string `json:"privateKeyField"`
+}
+
+// +k8s:deepcopy-gen=true
+
+// VaultPKICertificate describes a certificate issued by Vault's PKI secrets engine.
+type VaultPKICertificate struct {
+	// Mount is the path the PKI secrets engine is mounted at. If not
+	// specified, the mount the controller is configured with is used.
+	Mount string `json:"mount,omitempty"`
+	// Role is the PKI role used to issue the certificate. If not specified,
+	// the role the controller is configured with is used.
+	Role string `json:"role,omitempty"`
+	// CommonName is the common name of the issued certificate.
+	// +kubebuilder:validation:MinLength=1
+	CommonName string `json:"commonName"`
+	// AltNames are additional DNS names to include as SANs.
+	AltNames []string `json:"altNames,omitempty"`
+	// IPSANs are IP addresses to include as SANs.
+	IPSANs []string `json:"ipSans,omitempty"`
+	// OtherSANs are custom OID/UTF8-string SANs, in Vault's `<oid>;UTF8:<value>` format.
+	OtherSANs []string `json:"otherSans,omitempty"`
+	// TTL is the requested lifetime of the certificate, e.g. "72h".
+	TTL string `json:"ttl,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// VaultCertificateList is a list of VaultCertificate resources.
+type VaultCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []VaultCertificate `json:"items"`
+}
+
 func MergeSecret(a, b *corev1.Secret) *corev1.Secret {
 	if !compareSecrets(a, b) {
 		b.Annotations = a.Annotations
@@ -298,7 +879,35 @@ func compareSecrets(a, b *corev1.Secret) bool {
 func MergeService(a, b *corev1.Service) *corev1.Service {
 	if !compareServices(a, b) {
 		b.Annotations = a.Annotations
-		b.Spec.Ports = a.Spec.Ports
+		// keep any node ports Kubernetes allocated for ports that don't have a fixed one
+		allocated := make(map[int32]int32)
+		for _, port := range b.Spec.Ports {
+			allocated[port.Port] = port.NodePort
+		}
+		ports := make([]corev1.ServicePort, len(a.Spec.Ports))
+		for i, port := range a.Spec.Ports {
+			if port.NodePort == 0 {
+				port.NodePort = allocated[port.Port]
+			}
+			ports[i] = port
+		}
+		b.Spec.Ports = ports
+		b.Spec.ExternalTrafficPolicy = a.Spec.ExternalTrafficPolicy
+		b.Spec.LoadBalancerSourceRanges = a.Spec.LoadBalancerSourceRanges
+		b.Spec.SessionAffinity = a.Spec.SessionAffinity
+		b.Spec.SessionAffinityConfig = a.Spec.SessionAffinityConfig
+		b.Spec.LoadBalancerIP = a.Spec.LoadBalancerIP
+		b.Spec.ExternalIPs = a.Spec.ExternalIPs
+		// these are defaulted or immutable once set, so only change them when configured
+		if a.Spec.LoadBalancerClass != nil {
+			b.Spec.LoadBalancerClass = a.Spec.LoadBalancerClass
+		}
+		if len(a.Spec.IPFamilies) > 0 {
+			b.Spec.IPFamilies = a.Spec.IPFamilies
+		}
+		if a.Spec.IPFamilyPolicy != nil {
+			b.Spec.IPFamilyPolicy = a.Spec.IPFamilyPolicy
+		}
 	}
 
 	return b
@@ -307,7 +916,7 @@ func MergeService(a, b *corev1.Service) *corev1.Service {
 func compareServices(a, b *corev1.Service) bool {
 	// since K8s adds a bunch of defaults when we create a service, check that
 	// they don't differ by the things that we may actually change, namely container
-	// ports and propagated annotations
+	// ports, propagated annotations and the options from the service config
 	if !equality.Semantic.DeepEqual(a.Annotations, b.Annotations) {
 		return false
 	}
@@ -323,6 +932,37 @@ func compareServices(a, b *corev1.Service) bool {
 		if port.Protocol != otherPort.Protocol {
 			return false
 		}
+		if port.NodePort != 0 && port.NodePort != otherPort.NodePort {
+			return false
+		}
+	}
+
+	if a.Spec.ExternalTrafficPolicy != b.Spec.ExternalTrafficPolicy {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(a.Spec.LoadBalancerSourceRanges, b.Spec.LoadBalancerSourceRanges) {
+		return false
+	}
+	if a.Spec.SessionAffinity != b.Spec.SessionAffinity {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(a.Spec.SessionAffinityConfig, b.Spec.SessionAffinityConfig) {
+		return false
+	}
+	if a.Spec.LoadBalancerIP != b.Spec.LoadBalancerIP {
+		return false
+	}
+	if !equality.Semantic.DeepEqual(a.Spec.ExternalIPs, b.Spec.ExternalIPs) {
+		return false
+	}
+	if a.Spec.LoadBalancerClass != nil && !equality.Semantic.DeepEqual(a.Spec.LoadBalancerClass, b.Spec.LoadBalancerClass) {
+		return false
+	}
+	if len(a.Spec.IPFamilies) > 0 && !equality.Semantic.DeepEqual(a.Spec.IPFamilies, b.Spec.IPFamilies) {
+		return false
+	}
+	if a.Spec.IPFamilyPolicy != nil && !equality.Semantic.DeepEqual(a.Spec.IPFamilyPolicy, b.Spec.IPFamilyPolicy) {
+		return false
 	}
 	return true
 }
@@ -334,20 +974,69 @@ func MergeDeployment(a, b *appsv1.Deployment) *appsv1.Deployment {
 	if !compareDeployments(a, b) {
 		b.Spec.Template = a.Spec.Template
 		b.Spec.Replicas = a.Spec.Replicas
+		b.Spec.Strategy = a.Spec.Strategy
+	}
+
+	if hash, ok := a.Annotations[ConfigHashAnnotation]; ok {
+		if b.Annotations == nil {
+			b.Annotations = make(map[string]string)
+		}
+		b.Annotations[ConfigHashAnnotation] = hash
 	}
 
 	return b
 }
 
 func compareDeployments(a, b *appsv1.Deployment) bool {
-	// since K8s adds a bunch of defaults when we create a deployment, check that
+	// deployments created before we started recording the configuration they were
+	// rendered from have no hash, those just adopt the current one so that we don't
+	// restart every gateway on upgrade
+	if hash, ok := b.Annotations[ConfigHashAnnotation]; ok && hash != a.Annotations[ConfigHashAnnotation] {
+		return false
+	}
+	if !comparePodTemplates(a.Spec.Template, b.Spec.Template) {
+		return false
+	}
+
+	if *b.Spec.Replicas != *a.Spec.Replicas {
+		return false
+	}
+
+	return true
+}
+
+// MergeDaemonSet merges a gateway daemonset a onto b and returns b, overriding
+// all of the fields that we'd normally set for a gateway daemonset
+func MergeDaemonSet(a, b *appsv1.DaemonSet) *appsv1.DaemonSet {
+	if !compareDaemonSets(a, b) {
+		b.Spec.Template = a.Spec.Template
+		b.Spec.UpdateStrategy = a.Spec.UpdateStrategy
+		if b.Annotations == nil {
+			b.Annotations = make(map[string]string)
+		}
+		b.Annotations[ConfigHashAnnotation] = a.Annotations[ConfigHashAnnotation]
+	}
+
+	return b
+}
+
+func compareDaemonSets(a, b *appsv1.DaemonSet) bool {
+	// daemonsets always record the configuration they were rendered from
+	if b.Annotations[ConfigHashAnnotation] != a.Annotations[ConfigHashAnnotation] {
+		return false
+	}
+	return comparePodTemplates(a.Spec.Template, b.Spec.Template)
+}
+
+func comparePodTemplates(a, b corev1.PodTemplateSpec) bool {
+	// since K8s adds a bunch of defaults when we create a pod template, check that
 	// they don't differ by the things that we may actually change, namely container
 	// ports
-	if len(b.Spec.Template.Spec.Containers) != len(a.Spec.Template.Spec.Containers) {
+	if len(b.Spec.Containers) != len(a.Spec.Containers) {
 		return false
 	}
-	for i, container := range a.Spec.Template.Spec.Containers {
-		otherPorts := b.Spec.Template.Spec.Containers[i].Ports
+	for i, container := range a.Spec.Containers {
+		otherPorts := b.Spec.Containers[i].Ports
 		if len(container.Ports) != len(otherPorts) {
 			return false
 		}
@@ -361,10 +1050,5 @@ func compareDeployments(a, b *appsv1.Deployment) bool {
 			}
 		}
 	}
-
-	if *b.Spec.Replicas != *a.Spec.Replicas {
-		return false
-	}
-
 	return true
 }
@@ -8,8 +8,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apps "k8s.io/api/apps/v1"
 	core "k8s.io/api/core/v1"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	gwv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/hashicorp/consul-api-gateway/internal/k8s/utils"
@@ -104,6 +106,28 @@ func TestGatewayClassConfig_RoleFor(t *testing.T) {
 		assert.ElementsMatch(t, []string{"myPodSecurityPolicy"}, role.Rules[0].ResourceNames)
 		assert.ElementsMatch(t, []string{"use"}, role.Rules[0].Verbs)
 	})
+
+	t.Run("managed auth with securityContextConstraints", func(t *testing.T) {
+		gcc := &GatewayClassConfig{
+			Spec: GatewayClassConfigSpec{
+				ConsulSpec: ConsulSpec{
+					AuthSpec: AuthSpec{
+						Managed:                    true,
+						SecurityContextConstraints: "restricted-v2",
+					},
+				},
+			},
+		}
+
+		role := gcc.RoleFor(&gwv1beta1.Gateway{})
+		require.NotNil(t, role)
+
+		require.Len(t, role.Rules, 1)
+		assert.ElementsMatch(t, []string{"security.openshift.io"}, role.Rules[0].APIGroups)
+		assert.ElementsMatch(t, []string{"securitycontextconstraints"}, role.Rules[0].Resources)
+		assert.ElementsMatch(t, []string{"restricted-v2"}, role.Rules[0].ResourceNames)
+		assert.ElementsMatch(t, []string{"use"}, role.Rules[0].Verbs)
+	})
 }
 
 func TestGatewayClassConfig_RoleBindingFor(t *testing.T) {
@@ -195,3 +219,216 @@ func TestGatewayClassConfig_ServiceAccountFor(t *testing.T) {
 		assert.Equal(t, utils.LabelsForGateway(gw), sa.Labels)
 	})
 }
+
+func TestMergeDeployment(t *testing.T) {
+	deployment := func(hash, image string) *apps.Deployment {
+		d := &apps.Deployment{
+			Spec: apps.DeploymentSpec{
+				Replicas: pointer.Int32(1),
+				Template: core.PodTemplateSpec{
+					Spec: core.PodSpec{
+						Containers: []core.Container{{Image: image}},
+					},
+				},
+			},
+		}
+		if hash != "" {
+			d.Annotations = map[string]string{ConfigHashAnnotation: hash}
+		}
+		return d
+	}
+
+	t.Run("unchanged config", func(t *testing.T) {
+		merged := MergeDeployment(deployment("a", "new"), deployment("a", "old"))
+		assert.Equal(t, "old", merged.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("changed config", func(t *testing.T) {
+		merged := MergeDeployment(deployment("b", "new"), deployment("a", "old"))
+		assert.Equal(t, "new", merged.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, "b", merged.Annotations[ConfigHashAnnotation])
+	})
+
+	t.Run("adopts config without rolling", func(t *testing.T) {
+		merged := MergeDeployment(deployment("b", "new"), deployment("", "old"))
+		assert.Equal(t, "old", merged.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, "b", merged.Annotations[ConfigHashAnnotation])
+	})
+}
+
+func TestMergeDaemonSet(t *testing.T) {
+	daemonSet := func(hash, image string) *apps.DaemonSet {
+		d := &apps.DaemonSet{
+			Spec: apps.DaemonSetSpec{
+				Template: core.PodTemplateSpec{
+					Spec: core.PodSpec{
+						Containers: []core.Container{{Image: image}},
+					},
+				},
+			},
+		}
+		if hash != "" {
+			d.Annotations = map[string]string{ConfigHashAnnotation: hash}
+		}
+		return d
+	}
+
+	t.Run("unchanged config", func(t *testing.T) {
+		merged := MergeDaemonSet(daemonSet("a", "new"), daemonSet("a", "old"))
+		assert.Equal(t, "old", merged.Spec.Template.Spec.Containers[0].Image)
+	})
+
+	t.Run("changed config", func(t *testing.T) {
+		merged := MergeDaemonSet(daemonSet("b", "new"), daemonSet("a", "old"))
+		assert.Equal(t, "new", merged.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, "b", merged.Annotations[ConfigHashAnnotation])
+	})
+
+	t.Run("new daemonset", func(t *testing.T) {
+		merged := MergeDaemonSet(daemonSet("b", "new"), daemonSet("", ""))
+		assert.Equal(t, "new", merged.Spec.Template.Spec.Containers[0].Image)
+		assert.Equal(t, "b", merged.Annotations[ConfigHashAnnotation])
+	})
+}
+
+func TestMergeService(t *testing.T) {
+	service := func(policy core.ServiceExternalTrafficPolicyType, nodePort int32) *core.Service {
+		return &core.Service{
+			Spec: core.ServiceSpec{
+				Type:                  core.ServiceTypeLoadBalancer,
+				ExternalTrafficPolicy: policy,
+				SessionAffinity:       core.ServiceAffinityNone,
+				Ports:                 []core.ServicePort{{Port: 80, Protocol: "TCP", NodePort: nodePort}},
+			},
+		}
+	}
+
+	t.Run("unchanged config keeps allocated node ports", func(t *testing.T) {
+		merged := MergeService(service(core.ServiceExternalTrafficPolicyTypeCluster, 0), service(core.ServiceExternalTrafficPolicyTypeCluster, 30000))
+		assert.Equal(t, int32(30000), merged.Spec.Ports[0].NodePort)
+	})
+
+	t.Run("changed config keeps allocated node ports", func(t *testing.T) {
+		merged := MergeService(service(core.ServiceExternalTrafficPolicyTypeLocal, 0), service(core.ServiceExternalTrafficPolicyTypeCluster, 30000))
+		assert.Equal(t, core.ServiceExternalTrafficPolicyTypeLocal, merged.Spec.ExternalTrafficPolicy)
+		assert.Equal(t, int32(30000), merged.Spec.Ports[0].NodePort)
+	})
+
+	t.Run("fixed node port", func(t *testing.T) {
+		merged := MergeService(service(core.ServiceExternalTrafficPolicyTypeCluster, 30080), service(core.ServiceExternalTrafficPolicyTypeCluster, 30000))
+		assert.Equal(t, int32(30080), merged.Spec.Ports[0].NodePort)
+	})
+
+	t.Run("unset IP families are left to Kubernetes", func(t *testing.T) {
+		existing := service(core.ServiceExternalTrafficPolicyTypeCluster, 30000)
+		existing.Spec.IPFamilies = []core.IPFamily{core.IPv4Protocol}
+		merged := MergeService(service(core.ServiceExternalTrafficPolicyTypeLocal, 0), existing)
+		assert.Equal(t, []core.IPFamily{core.IPv4Protocol}, merged.Spec.IPFamilies)
+	})
+}
+
+func TestGatewayClassConfig_WithOverrides(t *testing.T) {
+	loadBalancer := core.ServiceTypeLoadBalancer
+	clusterIP := core.ServiceTypeClusterIP
+
+	gcc := &GatewayClassConfig{
+		ObjectMeta: meta.ObjectMeta{Name: "config"},
+		Spec: GatewayClassConfigSpec{
+			ServiceType:      &clusterIP,
+			LogLevel:         "info",
+			AllowedOverrides: []GatewayConfigField{GatewayConfigFieldServiceType, GatewayConfigFieldDeployment},
+		},
+	}
+
+	t.Run("allowed overrides", func(t *testing.T) {
+		merged, err := gcc.WithOverrides(&GatewayConfig{
+			Spec: GatewayConfigSpec{
+				ServiceType:    &loadBalancer,
+				DeploymentSpec: &DeploymentSpec{DefaultInstances: pointer.Int32(3)},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, loadBalancer, *merged.Spec.ServiceType)
+		assert.Equal(t, int32(3), *merged.Spec.DeploymentSpec.DefaultInstances)
+		assert.Equal(t, "info", merged.Spec.LogLevel)
+		// the class config is left untouched
+		assert.Equal(t, clusterIP, *gcc.Spec.ServiceType)
+	})
+
+	t.Run("disallowed overrides", func(t *testing.T) {
+		_, err := gcc.WithOverrides(&GatewayConfig{
+			Spec: GatewayConfigSpec{
+				NodeSelector: map[string]string{"pool": "edge"},
+			},
+		})
+		require.EqualError(t, err, `GatewayClassConfig "config" does not allow overriding nodeSelector`)
+
+		_, err = gcc.WithOverrides(&GatewayConfig{
+			Spec: GatewayConfigSpec{
+				ServiceSpec: &ServiceSpec{ExternalTrafficPolicy: core.ServiceExternalTrafficPolicyTypeLocal},
+			},
+		})
+		require.EqualError(t, err, `GatewayClassConfig "config" does not allow overriding service`)
+	})
+
+	t.Run("deployment mode", func(t *testing.T) {
+		// the deployment mode needs to be allowed separately
+		_, err := gcc.WithOverrides(&GatewayConfig{
+			Spec: GatewayConfigSpec{
+				DeploymentSpec: &DeploymentSpec{Mode: DeploymentModeDaemonSet},
+			},
+		})
+		require.EqualError(t, err, `GatewayClassConfig "config" does not allow overriding deploymentMode`)
+
+		// setting the class's own mode isn't a change
+		merged, err := gcc.WithOverrides(&GatewayConfig{
+			Spec: GatewayConfigSpec{
+				DeploymentSpec: &DeploymentSpec{Mode: DeploymentModeDeployment},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, DeploymentModeDeployment, merged.Spec.DeploymentSpec.Mode)
+
+		daemonSet := gcc.DeepCopy()
+		daemonSet.Spec.DeploymentSpec.Mode = DeploymentModeDaemonSet
+		merged, err = daemonSet.WithOverrides(&GatewayConfig{
+			Spec: GatewayConfigSpec{
+				DeploymentSpec: &DeploymentSpec{DefaultInstances: pointer.Int32(3)},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, DeploymentModeDaemonSet, merged.Spec.DeploymentSpec.Mode)
+
+		daemonSet.Spec.AllowedOverrides = append(daemonSet.Spec.AllowedOverrides, GatewayConfigFieldDeploymentMode)
+		merged, err = daemonSet.WithOverrides(&GatewayConfig{
+			Spec: GatewayConfigSpec{
+				DeploymentSpec: &DeploymentSpec{Mode: DeploymentModeDeployment},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, DeploymentModeDeployment, merged.Spec.DeploymentSpec.Mode)
+	})
+}
+
+func TestCertManagerSpec_AllowsIssuer(t *testing.T) {
+	spec := &CertManagerSpec{
+		AllowedIssuers: []CertManagerIssuerReference{
+			{Name: "issuer"},
+			{Name: "cluster-issuer", Kind: "ClusterIssuer"},
+			{Name: "external", Kind: "ExternalIssuer", Group: "example.com"},
+		},
+	}
+
+	assert.True(t, spec.AllowsIssuer("issuer", "Issuer", ""))
+	assert.True(t, spec.AllowsIssuer("issuer", "Issuer", "cert-manager.io"))
+	assert.True(t, spec.AllowsIssuer("cluster-issuer", "ClusterIssuer", ""))
+	assert.True(t, spec.AllowsIssuer("external", "ExternalIssuer", "example.com"))
+
+	// the kind and group must match as well
+	assert.False(t, spec.AllowsIssuer("issuer", "ClusterIssuer", ""))
+	assert.False(t, spec.AllowsIssuer("external", "ExternalIssuer", ""))
+	assert.False(t, spec.AllowsIssuer("other", "Issuer", ""))
+
+	// nothing is allowed without a spec
+	assert.False(t, (*CertManagerSpec)(nil).AllowsIssuer("issuer", "Issuer", ""))
+}